@@ -2,6 +2,7 @@ package chromecast
 
 import (
 	"context"
+	"time"
 )
 
 type Scanner interface {
@@ -19,6 +20,14 @@ type Serializer interface {
 	Send(Envelope, []byte) error
 }
 
+// BinarySerializer is a Serializer which supports binary payloads (PayloadType BINARY)
+type BinarySerializer interface {
+	Serializer
+	// ReceiveAny receives a message (isBinary is true for a binary payload)
+	ReceiveAny() (env Envelope, payload []byte, isBinary bool, err error)
+	SendBinary(Envelope, []byte) error
+}
+
 type IdentifiablePayload interface {
 	SetRequestID(uint32)
 }
@@ -32,8 +41,14 @@ func (p *PayloadWithID) SetRequestID(id uint32) {
 	p.RequestID = &id
 }
 
+// Pinger measures the round-trip time to the chromecast
+type Pinger interface {
+	Ping(ctx context.Context) (time.Duration, error)
+}
+
 type AmpController interface {
 	Mute(muted bool) error
 	SetVolume(level float64) error
+	// Quit stops the app in the foreground (the connection stays open)
 	Quit() error
 }
@@ -2,7 +2,10 @@ package cli
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/oliverpool/go-chromecast"
 
@@ -47,24 +50,68 @@ func FirstClientWithStatus(ctx context.Context, logger chromecast.Logger, matche
 }
 
 // ConnectedClient will create a client and keep it connected
+// If the connection drops, it is reestablished with an exponential backoff
+// (see OnReconnect of the client to be notified)
 func ConnectedClient(ctx context.Context, addr string, logger chromecast.Logger) (*client.Client, error) {
-	conn, err := net.Dial(ctx, addr)
-	if err != nil {
+	return ConnectedClientTLS(ctx, addr, nil, logger)
+}
+
+// ConnectedClientTLS is like ConnectedClient, with a custom TLS configuration (see net.DialTLS)
+func ConnectedClientTLS(ctx context.Context, addr string, config *tls.Config, logger chromecast.Logger) (*client.Client, error) {
+	return ConnectedClientWith(ctx, nil, addr, config, logger)
+}
+
+// ConnectedClientWith is like ConnectedClientTLS, connecting through a custom dialer
+// (like a SOCKS5 proxy or a VPN interface, see net.DialWith)
+func ConnectedClientWith(ctx context.Context, dialer net.ContextDialer, addr string, config *tls.Config, logger chromecast.Logger) (*client.Client, error) {
+	// the hooks of conn wait for the client to be created
+	var c *client.Client
+	ready := make(chan struct{})
+
+	conn := &net.ReconnectingConn{
+		Dial: func(ctx context.Context) (io.ReadWriteCloser, error) {
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			return net.DialWith(ctx, dialer, addr, config)
+		},
+		WriteTimeout: 10 * time.Second,
+		OnError: func(err error) {
+			logger.Log("step", "reconnect", "err", err)
+		},
+		OnDisconnect: func() {
+			logger.Log("step", "disconnected", "addr", addr)
+			<-ready
+			c.Reconnecting()
+		},
+		OnReconnect: func() {
+			logger.Log("step", "reconnected", "addr", addr)
+			<-ready
+			c.Reconnected()
+		},
+	}
+	if err := conn.Connect(ctx); err != nil {
 		return nil, err
 	}
 
-	serializer := gogoprotobuf.Serializer{
+	serializer := client.NewQueuedSerializer(&gogoprotobuf.Serializer{
 		Conn:   conn,
 		Logger: logger,
-	}
-	c := client.New(&serializer, logger)
+	}, 0, client.ErrorWhenFull, logger)
+	c = client.New(serializer, logger)
+	close(ready)
 
 	go func() {
 		<-ctx.Done()
-		command.Close.Send(c)
+		c.Close()
+		serializer.Close()
 		conn.Close()
 	}()
-	go heartbeat.RespondToPing(c)
+	go heartbeat.Heartbeat{
+		OnDead: func() {
+			logger.Log("step", "heartbeat", "err", "no PONG received, reconnecting")
+			conn.Drop()
+		},
+	}.Run(ctx, c)
 
 	return c, command.Connect.Send(c)
 }
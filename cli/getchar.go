@@ -96,6 +96,12 @@ func bytesToKeyPress(b []byte) KeyPress {
 				Type: SpaceBar,
 				Key:  by,
 			}
+		case '!' <= by && by <= '~' && !('0' <= by && by <= '9'):
+			// punctuation (the letters are handled above)
+			return KeyPress{
+				Type: Symbol,
+				Key:  by,
+			}
 		case by == 27:
 			// escape
 			return KeyPress{
@@ -134,6 +140,7 @@ const (
 	Arrow
 	SpaceBar
 	Escape
+	Symbol
 	Unsupported
 )
 
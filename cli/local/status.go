@@ -13,15 +13,34 @@ type Status struct {
 	mu          sync.Mutex
 	volume      float64
 	muted       bool
-	playerState string
+	playerState media.PlayerState
+	rate        float64
+	repeatMode  media.RepeatMode
 	time        time.Duration
 	totalTime   time.Duration
-	orderSent   time.Time
+	// live streams: time and totalTime are relative to the start of the seekable range
+	live      bool
+	liveStart time.Duration
+	orderSent time.Time
 }
 
 func New(cstatus chromecast.Status) *Status {
 	s := Status{}
+	s.updateVolume(cstatus)
+	return &s
+}
+
+// UpdateReceiver updates the volume (unless an order was just sent)
+func (s *Status) UpdateReceiver(cstatus chromecast.Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Since(s.orderSent) < time.Second {
+		return
+	}
+	s.updateVolume(cstatus)
+}
 
+func (s *Status) updateVolume(cstatus chromecast.Status) {
 	vol := cstatus.Volume
 	if vol != nil {
 		if vol.Level != nil {
@@ -31,7 +50,6 @@ func New(cstatus chromecast.Status) *Status {
 			s.muted = *vol.Muted
 		}
 	}
-	return &s
 }
 
 func (s *Status) UpdateMedia(mstatus media.Status) int {
@@ -41,6 +59,20 @@ func (s *Status) UpdateMedia(mstatus media.Status) int {
 		return int(s.time.Seconds())
 	}
 	s.playerState = mstatus.PlayerState
+	if mstatus.PlaybackRate > 0 {
+		s.rate = mstatus.PlaybackRate
+	}
+	if mstatus.RepeatMode != "" {
+		s.repeatMode = mstatus.RepeatMode
+	}
+	s.live = mstatus.IsLive()
+	if r := mstatus.LiveSeekableRange; r != nil {
+		s.liveStart = r.Start.Duration
+		s.time = mstatus.CurrentTime.Duration - s.liveStart
+		s.totalTime = r.End.Duration - s.liveStart
+		return int(s.time.Seconds())
+	}
+	s.liveStart = 0
 	s.time = mstatus.CurrentTime.Duration
 	if mstatus.Item != nil {
 		s.totalTime = mstatus.Item.Duration.Duration
@@ -48,6 +80,35 @@ func (s *Status) UpdateMedia(mstatus media.Status) int {
 	return int(mstatus.CurrentTime.Seconds())
 }
 
+// Elapse advances the current time of a playing media by d (between two statuses)
+// and returns the elapsed seconds
+func (s *Status) Elapse(d time.Duration) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.live {
+		// the live edge moves forward
+		s.totalTime += d
+	}
+	if s.playerState == media.Playing {
+		rate := s.rate
+		if rate == 0 {
+			rate = 1
+		}
+		s.time += time.Duration(float64(d) * rate)
+		if s.totalTime > 0 && s.time > s.totalTime {
+			s.time = s.totalTime
+		}
+	}
+	return int(s.time.Seconds())
+}
+
+// Total returns the duration of the media (or of the live window) in seconds
+func (s *Status) Total() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return int(s.totalTime.Seconds())
+}
+
 func (s *Status) order() func() {
 	s.mu.Lock()
 	s.orderSent = time.Now()
@@ -57,11 +118,11 @@ func (s *Status) order() func() {
 func (s *Status) TogglePlay() bool {
 	defer s.order()()
 
-	if s.playerState == "PAUSED" {
-		s.playerState = "PLAYING"
+	if s.playerState == media.Paused {
+		s.playerState = media.Playing
 		return true
 	}
-	s.playerState = "PAUSED"
+	s.playerState = media.Paused
 	return false
 }
 
@@ -84,6 +145,32 @@ func (s *Status) IncrVolume(diff float64) float64 {
 	return s.volume
 }
 
+// IncrRate changes the playback rate (between 0.5 and 2)
+func (s *Status) IncrRate(diff float64) float64 {
+	defer s.order()()
+
+	if s.rate == 0 {
+		s.rate = 1
+	}
+	s.rate += diff
+	if s.rate > 2 {
+		s.rate = 2
+	} else if s.rate < .5 {
+		s.rate = .5
+	}
+	return s.rate
+}
+
+// SeekBy moves the current time and returns the position to seek to
+// (within the seekable range for a live stream)
+// NextRepeatMode cycles through the repeat modes
+func (s *Status) NextRepeatMode() media.RepeatMode {
+	defer s.order()()
+
+	s.repeatMode = s.repeatMode.Next()
+	return s.repeatMode
+}
+
 func (s *Status) SeekBy(diff time.Duration) time.Duration {
 	defer s.order()()
 
@@ -91,25 +178,47 @@ func (s *Status) SeekBy(diff time.Duration) time.Duration {
 	if s.time < 0 {
 		s.time = 0
 	}
-	return s.time
+	if s.live && s.totalTime > 0 && s.time > s.totalTime {
+		s.time = s.totalTime
+	}
+	return s.liveStart + s.time
 }
 
 func (s *Status) PlayerState() string {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	state := string(s.playerState)
 	switch s.playerState {
-	case "PLAYING":
-		return " Playing "
-	case "PAUSED":
-		return "[paused] "
+	case media.Playing:
+		state = " Playing "
+	case media.Paused:
+		state = "[paused] "
+	}
+	if s.rate != 0 && s.rate != 1 {
+		state += fmt.Sprintf("x%.2g ", s.rate)
+	}
+	switch s.repeatMode {
+	case media.RepeatAll:
+		state += "(repeat all) "
+	case media.RepeatSingle:
+		state += "(repeat one) "
+	case media.RepeatAllAndShuffle:
+		state += "(shuffle) "
 	}
-	return s.playerState
+	return state
 }
 
 func (s *Status) TimeStatus() string {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.live {
+		behind := s.totalTime - s.time
+		if behind < time.Second {
+			return fmt.Sprintf("%-8s/%8s", "LIVE", "")
+		}
+		return fmt.Sprintf("%-8s/%8s", "-"+behind.Round(time.Second).String(), "LIVE")
+	}
 	return fmt.Sprintf("%-8s/%8s", s.time.Round(time.Second), s.totalTime.Round(time.Second))
 }
@@ -1,5 +1,7 @@
 package chromecast
 
+import "context"
+
 // Sender sends a payload (without expecting a reply)
 type Sender interface {
 	Send(env Envelope, payload interface{}) error
@@ -10,11 +12,24 @@ type Requester interface {
 	Request(env Envelope, payload IdentifiablePayload) (<-chan []byte, error)
 }
 
+// ContextRequester sends a payload and waits for the reply until ctx is done (ErrRequestTimeout)
+type ContextRequester interface {
+	RequestCtx(ctx context.Context, env Envelope, payload IdentifiablePayload) ([]byte, error)
+}
+
 // Listener allows to listen to specific messages and forward them (non-blocking) on ch
 type Listener interface {
 	Listen(env Envelope, responseType string, ch chan<- []byte)
 }
 
+// Subscriber allows to receive the broadcasted messages (destination "*") of a namespace
+// and forward them (non-blocking) on ch
+// (Unsubscribe stops the forwarding and closes ch)
+type Subscriber interface {
+	Subscribe(namespace string, responseType string, ch chan<- []byte)
+	Unsubscribe(namespace string, responseType string, ch chan<- []byte)
+}
+
 // Client interface is too weak
 type Client interface {
 	Listener
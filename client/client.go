@@ -1,10 +1,12 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"runtime/debug"
 	"sync"
-	"sync/atomic"
+	"time"
 
 	"github.com/oliverpool/go-chromecast"
 )
@@ -13,22 +15,29 @@ func New(serializer chromecast.Serializer, logger chromecast.Logger) *Client {
 	c := Client{
 		Serializer: serializer,
 		Logger:     logger,
+		state:      chromecast.Connected,
+		errors:     make(chan error, 8),
 	}
 
 	go func() {
 		var lastErr string
 		nbErr := 0
 		for nbErr <= 5 {
-			if err := c.Dispatch(); err != nil {
-				logger.Log("step", "dispatch", "err", err)
-				if err.Error() == lastErr {
-					nbErr++
-				} else {
-					lastErr = err.Error()
-					nbErr = 1
-				}
-			} else {
+			err := c.safeDispatch()
+			if err == nil {
 				nbErr = 0
+				continue
+			}
+			logger.Log("step", "dispatch", "err", err)
+			c.reportError(err)
+			if _, ok := err.(PanicError); ok {
+				// a bad message should not stop the dispatching
+				nbErr = 0
+			} else if err.Error() == lastErr {
+				nbErr++
+			} else {
+				lastErr = err.Error()
+				nbErr = 1
 			}
 		}
 		logger.Log("step", "dispatch-abort", "err", fmt.Errorf("same error %d times: %s", nbErr, lastErr))
@@ -37,15 +46,83 @@ func New(serializer chromecast.Serializer, logger chromecast.Logger) *Client {
 	return &c
 }
 
+// PanicError is reported when the dispatching of a message panicked (in a handler for instance)
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e PanicError) Error() string {
+	return fmt.Sprintf("panic during dispatch: %v", e.Value)
+}
+
+// safeDispatch calls Dispatch, converting a panic into a PanicError
+func (c *Client) safeDispatch() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return c.Dispatch()
+}
+
+// Errors returns a channel receiving the dispatch errors (including the PanicError)
+// The errors are dropped if the channel is not read.
+func (c *Client) Errors() <-chan error {
+	return c.errors
+}
+
+func (c *Client) reportError(err error) {
+	select {
+	case c.errors <- err:
+	default:
+	}
+}
+
+// Client sends and receives messages with the chromecast
+// The virtual connections are managed automatically: a CONNECT is sent before the first message
+// to a destination, and a CLOSE to every connected destination on Close
 type Client struct {
 	chromecast.Serializer
 	Logger     chromecast.Logger
 	AfterClose []func()
+	// AfterReconnect callbacks are called when the underlying connection has been reestablished
+	// (see Reconnected and OnReconnect)
+	AfterReconnect []func()
 
-	requestID uint32
+	// RequestTimeout after which a request without response is dropped (DefaultRequestTimeout if 0)
+	RequestTimeout time.Duration
+	// MaxPendingRequests waiting for a response (DefaultMaxPendingRequests if 0)
+	// The subsequent requests wait for a slot, in order
+	MaxPendingRequests int
+
+	pending   correlator
 	mu        sync.Mutex
-	pending   map[uint32]chan<- []byte
 	listeners map[chromecast.Envelope]map[string][]chan<- []byte
+	// subscribers of the broadcasts, by namespace
+	subscribers map[string]map[string][]chan<- []byte
+	handlers    map[string]Handler
+
+	pongWaiters []pongWaiter
+	// pingMu is held while sending a PING
+	pingMu sync.Mutex
+	// pingsInFlight were sent and not answered yet (the PONGs come in order)
+	pingsInFlight int
+
+	binaryHandlers map[string]Handler
+
+	imu          sync.RWMutex
+	interceptors []Interceptor
+
+	cmu         sync.Mutex
+	connections map[connection]bool
+	closed      bool
+
+	smu            sync.Mutex
+	state          chromecast.ConnectionState
+	stateListeners []chan chromecast.ConnectionState
+
+	errors chan error
 }
 
 func (c *Client) Listen(env chromecast.Envelope, responseType string, ch chan<- []byte) {
@@ -66,41 +143,232 @@ func (c *Client) Listen(env chromecast.Envelope, responseType string, ch chan<-
 	types[responseType] = append(types[responseType], ch)
 }
 
+// Subscribe forwards (non-blocking) the broadcasted messages (destination "*") of the namespace
+// and of the given type on ch, like the unsolicited RECEIVER_STATUS (when the volume
+// is changed with the remote for instance)
+// The channel is closed on Close.
+func (c *Client) Subscribe(namespace string, responseType string, ch chan<- []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.subscribers == nil {
+		c.subscribers = make(map[string]map[string][]chan<- []byte, 1)
+	}
+	types, ok := c.subscribers[namespace]
+	if !ok {
+		types = make(map[string][]chan<- []byte)
+		c.subscribers[namespace] = types
+	}
+	types[responseType] = append(types[responseType], ch)
+}
+
+// Unsubscribe stops forwarding the broadcasted messages on ch and closes it
+func (c *Client) Unsubscribe(namespace string, responseType string, ch chan<- []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	subscribers := c.subscribers[namespace][responseType]
+	for i, s := range subscribers {
+		if s == ch {
+			c.subscribers[namespace][responseType] = append(subscribers[:i:i], subscribers[i+1:]...)
+			close(ch)
+			return
+		}
+	}
+}
+
+// Handler handles the messages of a namespace
+type Handler func(env chromecast.Envelope, payload []byte)
+
+// Handle registers the handler for all the messages of the namespace (replacing the previous one, nil to remove it)
+// The handler is called by the dispatching goroutine: it should return quickly
+// (and may Send, but not wait for a Request)
+func (c *Client) Handle(namespace string, handler Handler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if handler == nil {
+		delete(c.handlers, namespace)
+		return
+	}
+	if c.handlers == nil {
+		c.handlers = make(map[string]Handler, 1)
+	}
+	c.handlers[namespace] = handler
+}
+
+// HandleBinary registers the handler for the binary messages of the namespace
+// (replacing the previous one, nil to remove it, see Handle)
+func (c *Client) HandleBinary(namespace string, handler Handler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if handler == nil {
+		delete(c.binaryHandlers, namespace)
+		return
+	}
+	if c.binaryHandlers == nil {
+		c.binaryHandlers = make(map[string]Handler, 1)
+	}
+	c.binaryHandlers[namespace] = handler
+}
+
 func (c *Client) Send(env chromecast.Envelope, payload interface{}) error {
 	pay, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal payload: %s", err)
 	}
-	return c.Serializer.Send(env, pay)
+	if err = c.ensureConnected(env, pay); err != nil {
+		return err
+	}
+	return c.send(env, pay)
 }
 
-func (c *Client) Request(env chromecast.Envelope, payload chromecast.IdentifiablePayload) (<-chan []byte, error) {
-	id := atomic.AddUint32(&c.requestID, 1)
+func (c *Client) send(env chromecast.Envelope, pay []byte) error {
+	return c.sendNotifying(env, pay, nil)
+}
 
-	payload.SetRequestID(id)
-	response := make(chan []byte, 1)
+// sendNotifying sends the payload. If it is a PING and pong is not nil,
+// pong is closed when the PONG answering this PING is received.
+func (c *Client) sendNotifying(env chromecast.Envelope, pay []byte, pong chan struct{}) error {
+	env, pay, err := c.intercept(Outbound, Message{Envelope: env, Payload: pay})
+	if err != nil {
+		return err
+	}
+	if env.Namespace != heartbeatNamespace || !isPing(pay) {
+		return c.Serializer.Send(env, pay)
+	}
+
+	// the PINGs are sent one at a time, to be counted in the order of their PONGs
+	c.pingMu.Lock()
+	defer c.pingMu.Unlock()
 	c.mu.Lock()
-	if c.pending == nil {
-		c.pending = make(map[uint32]chan<- []byte, 1)
+	if pong != nil {
+		c.pongWaiters = append(c.pongWaiters, pongWaiter{ch: pong, skip: c.pingsInFlight})
 	}
-	c.pending[id] = response
+	// counted before sending, in case the PONG comes before the end of Send
+	c.pingsInFlight++
 	c.mu.Unlock()
 
-	err := c.Send(env, payload)
+	err = c.Serializer.Send(env, pay)
 	if err != nil {
 		c.mu.Lock()
-		delete(c.pending, id)
+		c.pingsInFlight--
+		c.removePongWaiter(pong)
 		c.mu.Unlock()
+	}
+	return err
+}
+
+// Request sends the request and returns a channel for the response
+// The channel is closed without response after RequestTimeout
+// If MaxPendingRequests are already pending, it waits (at most RequestTimeout) for one to complete
+func (c *Client) Request(env chromecast.Envelope, payload chromecast.IdentifiablePayload) (<-chan []byte, error) {
+	_, response, err := c.request(nil, env, payload, 0)
+	return response, err
+}
+
+// RequestCtx sends the request and waits for the response
+// It returns chromecast.ErrRequestTimeout if ctx is done before
+func (c *Client) RequestCtx(ctx context.Context, env chromecast.Envelope, payload chromecast.IdentifiablePayload) ([]byte, error) {
+	var timeout time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		// keep the request a bit after the deadline, for the cancellation to win
+		timeout = time.Until(deadline) + time.Second
+	}
+	id, response, err := c.request(ctx.Done(), env, payload, timeout)
+	if err != nil {
 		return nil, err
 	}
-	return response, nil
+	select {
+	case pay, ok := <-response:
+		if !ok {
+			return nil, chromecast.ErrConnectionClosed
+		}
+		return pay, nil
+	case <-ctx.Done():
+		c.pending.cancel(id)
+		return nil, chromecast.ErrRequestTimeout
+	}
+}
+
+// request waits for a slot (until done is closed or timeout expires) and sends the request
+func (c *Client) request(done <-chan struct{}, env chromecast.Envelope, payload chromecast.IdentifiablePayload, timeout time.Duration) (uint32, <-chan []byte, error) {
+	if timeout <= 0 {
+		timeout = c.RequestTimeout
+	}
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	max := c.MaxPendingRequests
+	if max <= 0 {
+		max = DefaultMaxPendingRequests
+	}
+	if done == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		done = ctx.Done()
+	}
+	if err := c.pending.acquire(max, done); err != nil {
+		return 0, nil, err
+	}
+	id, response := c.pending.add(timeout)
+
+	payload.SetRequestID(id)
+	err := c.Send(env, payload)
+	if err != nil {
+		c.pending.cancel(id)
+		return id, nil, err
+	}
+	return id, response, nil
+}
+
+// Pending returns the number of requests waiting for a response
+func (c *Client) Pending() int {
+	return c.pending.len()
+}
+
+// SendBinary sends a binary payload (the Serializer must be a chromecast.BinarySerializer)
+func (c *Client) SendBinary(env chromecast.Envelope, payload []byte) error {
+	serializer, ok := c.Serializer.(chromecast.BinarySerializer)
+	if !ok {
+		return fmt.Errorf("the serializer does not support binary payloads")
+	}
+	if err := c.ensureConnected(env, nil); err != nil {
+		return err
+	}
+	env, payload, err := c.intercept(Outbound, Message{Envelope: env, Payload: payload, Binary: true})
+	if err != nil {
+		return err
+	}
+	return serializer.SendBinary(env, payload)
 }
 
 func (c *Client) Dispatch() error {
-	env, pay, err := c.Serializer.Receive()
+	var env chromecast.Envelope
+	var pay []byte
+	var isBinary bool
+	var err error
+	if serializer, ok := c.Serializer.(chromecast.BinarySerializer); ok {
+		env, pay, isBinary, err = serializer.ReceiveAny()
+	} else {
+		env, pay, err = c.Serializer.Receive()
+	}
 	if err != nil {
 		return err
 	}
+	env, pay, err = c.intercept(Inbound, Message{Envelope: env, Payload: pay, Binary: isBinary})
+	if err != nil {
+		c.Logger.Log("step", "intercept", "namespace", env.Namespace, "err", err)
+		return nil
+	}
+
+	if isBinary {
+		// binary payloads are only forwarded to the binary handler of the namespace
+		c.mu.Lock()
+		handler := c.binaryHandlers[env.Namespace]
+		c.mu.Unlock()
+		if handler != nil {
+			handler(env, pay)
+		}
+		return nil
+	}
 
 	var payID chromecast.PayloadWithID
 
@@ -110,20 +378,41 @@ func (c *Client) Dispatch() error {
 	}
 
 	if payID.RequestID != nil {
-		c.forwardResponse(*payID.RequestID, pay)
+		c.pending.resolve(*payID.RequestID, pay)
+	}
+	if env.Namespace == ConnectionNamespace && payID.Type == "CLOSE" {
+		// the chromecast closed the virtual connection
+		c.forgetConnections(func(conn connection) bool {
+			return conn.source == env.Destination && conn.destination == env.Source
+		})
+	}
+
+	handler := c.forward(env, payID.Type, pay)
+	if handler != nil {
+		handler(env, pay)
 	}
+	return err
+}
 
+// forward the payload to the listeners and returns the handler of the namespace
+func (c *Client) forward(env chromecast.Envelope, payloadType string, pay []byte) Handler {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if env.Destination == "*" {
 		// broadcast
-		for _, listeners := range c.listeners {
-			nonBlockingForwardTo(listeners, payID.Type, pay)
+		for listenerEnv, listeners := range c.listeners {
+			if listenerEnv.Namespace == env.Namespace {
+				nonBlockingForwardTo(listeners, payloadType, pay)
+			}
 		}
+		nonBlockingForwardTo(c.subscribers[env.Namespace], payloadType, pay)
 	} else if listeners, ok := c.listeners[env]; ok {
-		nonBlockingForwardTo(listeners, payID.Type, pay)
+		nonBlockingForwardTo(listeners, payloadType, pay)
 	}
-	return err
+	if env.Namespace == heartbeatNamespace && payloadType == "PONG" {
+		c.receivedPong()
+	}
+	return c.handlers[env.Namespace]
 }
 
 func nonBlockingForwardTo(listeners map[string][]chan<- []byte, key string, payload []byte) {
@@ -138,24 +427,48 @@ func nonBlockingForwardTo(listeners map[string][]chan<- []byte, key string, payl
 	}
 }
 
-func (c *Client) forwardResponse(id uint32, pay []byte) {
+// Reconnected must be called when the underlying connection has been reestablished
+// It replays the virtual connections and calls the AfterReconnect callbacks
+// (in a new goroutine, since they may wait for responses)
+func (c *Client) Reconnected() {
+	// the virtual connections (and the pending PINGs) were lost
+	c.mu.Lock()
+	c.pingsInFlight = 0
+	c.mu.Unlock()
+	c.reconnectConnections()
+	c.setState(chromecast.Connected)
+
+	c.mu.Lock()
+	callbacks := c.AfterReconnect
+	c.mu.Unlock()
+	go func() {
+		for _, cb := range callbacks {
+			cb()
+		}
+	}()
+}
+
+// OnReconnect registers a callback to call after a reconnection (see Reconnected)
+// It can be called concurrently with Reconnected (unlike appending to AfterReconnect)
+func (c *Client) OnReconnect(cb func()) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if requester, ok := c.pending[id]; ok {
-		requester <- pay
-		close(requester)
-		delete(c.pending, id)
-	}
+	c.AfterReconnect = append(c.AfterReconnect, cb)
 }
 
+// Close sends a CLOSE to the connected transports and to the receiver,
+// closes the pending requests and the listeners, and then calls the AfterClose callbacks
+// (which should close the underlying connection)
+// Subsequent calls do nothing.
 func (c *Client) Close() error {
+	if !c.closeConnections() {
+		return nil
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	for id, requester := range c.pending {
-		close(requester)
-		delete(c.pending, id)
-	}
+	c.pending.closeAll()
 	for _, envs := range c.listeners {
 		for responseType, listeners := range envs {
 			for _, ch := range listeners {
@@ -164,8 +477,17 @@ func (c *Client) Close() error {
 			delete(envs, responseType)
 		}
 	}
+	for _, types := range c.subscribers {
+		for responseType, subscribers := range types {
+			for _, ch := range subscribers {
+				close(ch)
+			}
+			delete(types, responseType)
+		}
+	}
 	for _, cb := range c.AfterClose {
 		cb()
 	}
+	c.setState(chromecast.Closed)
 	return nil
 }
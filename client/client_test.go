@@ -0,0 +1,425 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/client"
+	"github.com/oliverpool/go-chromecast/command"
+	"github.com/oliverpool/go-chromecast/log"
+)
+
+type message struct {
+	env     chromecast.Envelope
+	payload []byte
+}
+
+// chanSerializer receives from in and sends to out
+type chanSerializer struct {
+	in  chan message
+	out chan message
+}
+
+func newChanSerializer() chanSerializer {
+	return chanSerializer{
+		in:  make(chan message, 5),
+		out: make(chan message, 5),
+	}
+}
+
+func (s chanSerializer) Receive() (chromecast.Envelope, []byte, error) {
+	m := <-s.in
+	return m.env, m.payload, nil
+}
+
+func (s chanSerializer) Send(env chromecast.Envelope, payload []byte) error {
+	s.out <- message{env, payload}
+	return nil
+}
+
+func TestHandle(t *testing.T) {
+	serializer := newChanSerializer()
+	c := client.New(serializer, log.NopLogger())
+
+	custom := make(chan string, 2)
+	c.Handle("urn:x-cast:com.example.custom", func(env chromecast.Envelope, payload []byte) {
+		custom <- string(payload)
+	})
+
+	serializer.in <- message{chromecast.Envelope{Source: "app", Destination: "sender-0", Namespace: "urn:x-cast:com.google.cast.media"}, []byte(`{"type":"MEDIA_STATUS"}`)}
+	serializer.in <- message{chromecast.Envelope{Source: "app", Destination: "sender-0", Namespace: "urn:x-cast:com.example.custom"}, []byte(`{"type":"SCORE","value":3}`)}
+
+	if payload := <-custom; payload != `{"type":"SCORE","value":3}` {
+		t.Errorf("unexpected payload: %s", payload)
+	}
+	select {
+	case payload := <-custom:
+		t.Errorf("only the custom namespace should be handled, got %s", payload)
+	default:
+	}
+}
+
+func TestVirtualConnections(t *testing.T) {
+	serializer := newChanSerializer()
+	c := client.New(serializer, log.NopLogger())
+
+	env := chromecast.Envelope{Source: "sender-0", Destination: "transport-1", Namespace: "urn:x-cast:com.google.cast.media"}
+	c.Send(env, map[string]string{"type": "GET_STATUS"})
+	c.Send(env, map[string]string{"type": "GET_STATUS"})
+
+	expected := []string{
+		client.ConnectionNamespace + ` {"type":"CONNECT"}`,
+		env.Namespace + ` {"type":"GET_STATUS"}`,
+		env.Namespace + ` {"type":"GET_STATUS"}`,
+	}
+	for _, e := range expected {
+		m := <-serializer.out
+		if got := m.env.Namespace + " " + string(m.payload); got != e || m.env.Destination != "transport-1" {
+			t.Errorf("expected %s, got %s to %s", e, got, m.env.Destination)
+		}
+	}
+
+	if d := c.ConnectedDestinations(); len(d) != 1 || d[0] != "transport-1" {
+		t.Errorf("transport-1 should be connected, got %v", d)
+	}
+
+	c.Close()
+	m := <-serializer.out
+	if m.env.Namespace != client.ConnectionNamespace || string(m.payload) != `{"type":"CLOSE"}` || m.env.Destination != "transport-1" {
+		t.Errorf("a CLOSE to transport-1 was expected, got %+v", m)
+	}
+	if d := c.ConnectedDestinations(); len(d) != 0 {
+		t.Errorf("no destination should be connected, got %v", d)
+	}
+}
+
+func TestPendingRequestsAreDropped(t *testing.T) {
+	serializer := newChanSerializer()
+	go func() {
+		// discard the sent messages
+		for range serializer.out {
+		}
+	}()
+	c := client.New(serializer, log.NopLogger())
+	c.RequestTimeout = 10 * time.Millisecond
+	c.MaxPendingRequests = 1000
+	env := chromecast.Envelope{Source: "sender-0", Destination: "receiver-0", Namespace: "ns"}
+
+	goroutines := runtime.NumGoroutine()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 500; i++ {
+		wg.Add(2)
+		// cancelled request
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+			defer cancel()
+			if _, err := c.RequestCtx(ctx, env, command.Map{"type": "GET_STATUS"}); err != chromecast.ErrRequestTimeout {
+				t.Errorf("ErrRequestTimeout expected, got %v", err)
+			}
+		}()
+		// expired request
+		go func() {
+			defer wg.Done()
+			response, err := c.Request(env, command.Map{"type": "GET_STATUS"})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if payload, ok := <-response; ok {
+				t.Errorf("the response channel should have been closed, got %s", payload)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := c.Pending(); n != 0 {
+		t.Errorf("no request should be pending, got %d", n)
+	}
+	// let the timers and goroutines finish
+	time.Sleep(50 * time.Millisecond)
+	if n := runtime.NumGoroutine(); n > goroutines+2 {
+		t.Errorf("goroutines leaked: %d before, %d after", goroutines, n)
+	}
+}
+
+func TestPendingRequestsAreClosed(t *testing.T) {
+	serializer := newChanSerializer()
+	go func() {
+		for range serializer.out {
+		}
+	}()
+	c := client.New(serializer, log.NopLogger())
+	env := chromecast.Envelope{Source: "sender-0", Destination: "receiver-0", Namespace: "ns"}
+
+	response, _ := c.Request(env, command.Map{"type": "GET_STATUS"})
+	if n := c.Pending(); n != 1 {
+		t.Errorf("one request should be pending, got %d", n)
+	}
+	c.Close()
+	if _, ok := <-response; ok {
+		t.Errorf("the response channel should have been closed")
+	}
+	if n := c.Pending(); n != 0 {
+		t.Errorf("no request should be pending, got %d", n)
+	}
+}
+
+func TestPing(t *testing.T) {
+	serializer := newChanSerializer()
+	c := client.New(serializer, log.NopLogger())
+
+	go func() {
+		for m := range serializer.out {
+			if string(m.payload) == `{"type":"PING"}` {
+				time.Sleep(5 * time.Millisecond)
+				serializer.in <- message{
+					chromecast.Envelope{Source: m.env.Destination, Destination: m.env.Source, Namespace: m.env.Namespace},
+					[]byte(`{"type":"PONG"}`),
+				}
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	rtt, err := c.Ping(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rtt < 5*time.Millisecond {
+		t.Errorf("the round-trip time should be at least 5ms, got %s", rtt)
+	}
+}
+
+func TestPingIgnoresOtherPongs(t *testing.T) {
+	serializer := newChanSerializer()
+	c := client.New(serializer, log.NopLogger())
+	heartbeat := chromecast.Envelope{Source: "sender-0", Destination: "receiver-0", Namespace: "urn:x-cast:com.google.cast.tp.heartbeat"}
+	pong := message{
+		chromecast.Envelope{Source: heartbeat.Destination, Destination: heartbeat.Source, Namespace: heartbeat.Namespace},
+		[]byte(`{"type":"PONG"}`),
+	}
+
+	// unsolicited PONG, while no PING is in flight
+	serializer.in <- pong
+	time.Sleep(10 * time.Millisecond)
+
+	// PING of the heartbeat, answered before the one of Ping
+	if err := c.Send(heartbeat, chromecast.PayloadWithID{Type: "PING"}); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for m := range serializer.out {
+			if string(m.payload) == `{"type":"PING"}` {
+				time.Sleep(10 * time.Millisecond)
+				serializer.in <- pong
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	rtt, err := c.Ping(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the PONG of the heartbeat comes after about 10ms, the one of Ping after about 20ms
+	if rtt < 15*time.Millisecond {
+		t.Errorf("the PONG of the heartbeat should have been ignored, got %s", rtt)
+	}
+}
+
+func TestRequestRaw(t *testing.T) {
+	serializer := newChanSerializer()
+	c := client.New(serializer, log.NopLogger())
+
+	go func() {
+		for m := range serializer.out {
+			if m.env.Namespace != "urn:x-cast:com.example.custom" {
+				continue
+			}
+			var request struct {
+				RequestID uint32 `json:"requestId"`
+				Question  string `json:"question"`
+			}
+			json.Unmarshal(m.payload, &request)
+			serializer.in <- message{
+				chromecast.Envelope{Source: m.env.Destination, Destination: m.env.Source, Namespace: m.env.Namespace},
+				[]byte(fmt.Sprintf(`{"requestId":%d,"answer":42}`, request.RequestID)),
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	response, err := c.RequestRaw(ctx, "urn:x-cast:com.example.custom", "transport-1", []byte(`{"question":"?"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(response) != `{"requestId":1,"answer":42}` {
+		t.Errorf("unexpected response: %s", response)
+	}
+
+	if _, err := c.RequestRaw(ctx, "ns", "transport-1", []byte(`"not an object"`)); err == nil {
+		t.Errorf("an error was expected for a non-object payload")
+	}
+}
+
+func TestCloseOrder(t *testing.T) {
+	serializer := newChanSerializer()
+	c := client.New(serializer, log.NopLogger())
+
+	closed := 0
+	c.AfterClose = append(c.AfterClose, func() {
+		closed++
+	})
+
+	c.Send(chromecast.Envelope{Source: "sender-0", Destination: "receiver-0", Namespace: client.ConnectionNamespace}, map[string]string{"type": "CONNECT"})
+	c.Send(chromecast.Envelope{Source: "sender-0", Destination: "transport-1", Namespace: "ns"}, map[string]string{"type": "PLAY"})
+	for i := 0; i < 3; i++ {
+		<-serializer.out
+	}
+
+	c.Close()
+	if m := <-serializer.out; m.env.Destination != "transport-1" || string(m.payload) != `{"type":"CLOSE"}` {
+		t.Errorf("the transport should be closed first, got %s %s", m.env.Destination, m.payload)
+	}
+	if m := <-serializer.out; m.env.Destination != "receiver-0" || string(m.payload) != `{"type":"CLOSE"}` {
+		t.Errorf("the receiver should be closed last, got %s %s", m.env.Destination, m.payload)
+	}
+
+	c.Close()
+	if closed != 1 {
+		t.Errorf("AfterClose should be called once, got %d", closed)
+	}
+	if err := c.Send(chromecast.Envelope{Source: "sender-0", Destination: "transport-2", Namespace: "ns"}, map[string]string{}); err != chromecast.ErrConnectionClosed {
+		t.Errorf("ErrConnectionClosed expected after Close, got %v", err)
+	}
+}
+
+func TestState(t *testing.T) {
+	c := client.New(newChanSerializer(), log.NopLogger())
+
+	states := c.State()
+	if s := <-states; s != chromecast.Connected {
+		t.Errorf("the initial state should be connected, got %s", s)
+	}
+
+	c.Reconnecting()
+	if s := <-states; s != chromecast.Reconnecting {
+		t.Errorf("reconnecting expected, got %s", s)
+	}
+	c.Reconnected()
+	if s := <-states; s != chromecast.Connected {
+		t.Errorf("connected expected, got %s", s)
+	}
+
+	c.Close()
+	if s := <-states; s != chromecast.Closed {
+		t.Errorf("closed expected, got %s", s)
+	}
+	if _, ok := <-states; ok {
+		t.Errorf("the channel should be closed")
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	serializer := newChanSerializer()
+	c := client.New(serializer, log.NopLogger())
+
+	receiverNs := "urn:x-cast:com.google.cast.receiver"
+	statuses := make(chan []byte, 2)
+	c.Subscribe(receiverNs, "RECEIVER_STATUS", statuses)
+
+	serializer.in <- message{chromecast.Envelope{Source: "receiver-0", Destination: "*", Namespace: "urn:x-cast:com.google.cast.media"}, []byte(`{"type":"RECEIVER_STATUS"}`)}
+	serializer.in <- message{chromecast.Envelope{Source: "receiver-0", Destination: "*", Namespace: receiverNs}, []byte(`{"type":"RECEIVER_STATUS","requestId":0}`)}
+
+	if payload := <-statuses; string(payload) != `{"type":"RECEIVER_STATUS","requestId":0}` {
+		t.Errorf("unexpected payload: %s", payload)
+	}
+	select {
+	case payload := <-statuses:
+		t.Errorf("only the broadcasts of the namespace should be forwarded, got %s", payload)
+	default:
+	}
+
+	c.Close()
+	if _, ok := <-statuses; ok {
+		t.Error("the channel should be closed")
+	}
+}
+
+func TestMaxPendingRequests(t *testing.T) {
+	serializer := newChanSerializer()
+	c := client.New(serializer, log.NopLogger())
+	c.MaxPendingRequests = 1
+	env := chromecast.Envelope{Source: "sender-0", Destination: "receiver-0", Namespace: "ns"}
+
+	first, err := c.Request(env, command.Map{"type": "GET_STATUS"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-serializer.out // CONNECT
+	<-serializer.out
+
+	// the slot is taken
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.RequestCtx(ctx, env, command.Map{"type": "GET_STATUS"}); err != chromecast.ErrRequestTimeout {
+		t.Errorf("ErrRequestTimeout expected, got %v", err)
+	}
+
+	// the waiting requests are sent in order
+	order := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		go func(i int) {
+			if _, err := c.Request(env, command.Map{"type": "GET_STATUS"}); err == nil {
+				order <- i
+			}
+		}(i)
+		// let the request wait
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	serializer.in <- message{chromecast.Envelope{Source: "receiver-0", Destination: "sender-0", Namespace: "ns"}, []byte(`{"type":"RECEIVER_STATUS","requestId":1}`)}
+	<-first
+	if i := <-order; i != 0 {
+		t.Errorf("the first waiting request should have been sent first, got %d", i)
+	}
+	c.Close()
+}
+
+func TestDispatchPanic(t *testing.T) {
+	serializer := newChanSerializer()
+	c := client.New(serializer, log.NopLogger())
+
+	handled := make(chan string, 1)
+	c.Handle("ns", func(env chromecast.Envelope, payload []byte) {
+		if string(payload) == `{"type":"BAD"}` {
+			panic("malformed payload")
+		}
+		handled <- string(payload)
+	})
+
+	env := chromecast.Envelope{Source: "app", Destination: "sender-0", Namespace: "ns"}
+	for i := 0; i < 10; i++ {
+		serializer.in <- message{env, []byte(`{"type":"BAD"}`)}
+	}
+	serializer.in <- message{env, []byte(`{"type":"GOOD"}`)}
+
+	if payload := <-handled; payload != `{"type":"GOOD"}` {
+		t.Errorf("unexpected payload: %s", payload)
+	}
+	err := <-c.Errors()
+	if perr, ok := err.(client.PanicError); !ok || perr.Value != "malformed payload" {
+		t.Errorf("a PanicError was expected, got %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"encoding/json"
+	"sort"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// ConnectionNamespace is the namespace of the virtual connections
+const ConnectionNamespace = "urn:x-cast:com.google.cast.tp.connection"
+
+// platformDestination does not need a virtual connection (heartbeat)
+const platformDestination = "Tr@n$p0rt-0"
+
+// receiverDestination is the destination of the receiver (as opposed to the apps transportId)
+const receiverDestination = "receiver-0"
+
+type connection struct {
+	source, destination string
+}
+
+// ensureConnected sends a CONNECT before the first message from a source to a destination
+// The CONNECT and CLOSE sent explicitly on the ConnectionNamespace are tracked as well
+func (c *Client) ensureConnected(env chromecast.Envelope, payload []byte) error {
+	if env.Destination == platformDestination || env.Destination == "*" {
+		return nil
+	}
+	conn := connection{source: env.Source, destination: env.Destination}
+
+	c.cmu.Lock()
+	defer c.cmu.Unlock()
+	if c.closed {
+		return chromecast.ErrConnectionClosed
+	}
+	if env.Namespace == ConnectionNamespace {
+		switch payloadType(payload) {
+		case "CONNECT":
+			c.setConnected(conn, true)
+		case "CLOSE":
+			c.setConnected(conn, false)
+		}
+		return nil
+	}
+	if c.connections[conn] {
+		return nil
+	}
+	err := c.sendConnection(conn, "CONNECT")
+	if err == nil {
+		c.setConnected(conn, true)
+	}
+	return err
+}
+
+// closeConnections sends a CLOSE to all the connected destinations (the transports before the receiver)
+// and prevents new connections
+// It returns false if the connections were already closed
+func (c *Client) closeConnections() bool {
+	c.cmu.Lock()
+	defer c.cmu.Unlock()
+	if c.closed {
+		return false
+	}
+	c.closed = true
+
+	connections := make([]connection, 0, len(c.connections))
+	for conn := range c.connections {
+		connections = append(connections, conn)
+	}
+	sort.Slice(connections, func(i, j int) bool {
+		// the receiver comes last
+		return connections[j].destination == receiverDestination && connections[i].destination != receiverDestination
+	})
+	for _, conn := range connections {
+		if err := c.sendConnection(conn, "CLOSE"); err != nil {
+			c.Logger.Log("step", "close", "destination", conn.destination, "err", err)
+		}
+		delete(c.connections, conn)
+	}
+	return true
+}
+
+// reconnectConnections sends a CONNECT to all the previously connected destinations
+func (c *Client) reconnectConnections() {
+	c.cmu.Lock()
+	defer c.cmu.Unlock()
+	for conn := range c.connections {
+		if err := c.sendConnection(conn, "CONNECT"); err != nil {
+			c.Logger.Log("step", "reconnect", "destination", conn.destination, "err", err)
+			delete(c.connections, conn)
+		}
+	}
+}
+
+// forgetConnections forgets the connected destinations (after a CLOSE from the chromecast)
+func (c *Client) forgetConnections(match func(connection) bool) {
+	c.cmu.Lock()
+	defer c.cmu.Unlock()
+	for conn := range c.connections {
+		if match(conn) {
+			delete(c.connections, conn)
+		}
+	}
+}
+
+// ConnectedDestinations returns the destinations with an open virtual connection
+func (c *Client) ConnectedDestinations() []string {
+	c.cmu.Lock()
+	defer c.cmu.Unlock()
+	destinations := make([]string, 0, len(c.connections))
+	for conn := range c.connections {
+		destinations = append(destinations, conn.destination)
+	}
+	return destinations
+}
+
+func (c *Client) setConnected(conn connection, connected bool) {
+	if !connected {
+		delete(c.connections, conn)
+		return
+	}
+	if c.connections == nil {
+		c.connections = make(map[connection]bool, 1)
+	}
+	c.connections[conn] = true
+}
+
+func (c *Client) sendConnection(conn connection, messageType string) error {
+	env := chromecast.Envelope{
+		Source:      conn.source,
+		Destination: conn.destination,
+		Namespace:   ConnectionNamespace,
+	}
+	pay, err := json.Marshal(chromecast.PayloadWithID{Type: messageType})
+	if err != nil {
+		return err
+	}
+	return c.send(env, pay)
+}
+
+func payloadType(payload []byte) string {
+	var p chromecast.PayloadWithID
+	json.Unmarshal(payload, &p)
+	return p.Type
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// DefaultRequestTimeout is the duration after which a pending request without response is dropped
+const DefaultRequestTimeout = time.Minute
+
+// DefaultMaxPendingRequests is the number of requests which can wait for a response simultaneously
+const DefaultMaxPendingRequests = 16
+
+// correlator matches the responses to the pending requests (by requestId)
+// Each pending request is dropped (and its channel closed) when it gets a response,
+// when its timeout expires, when it is cancelled or when the correlator is closed
+// The number of pending requests is limited: the new requests wait for a slot (first come, first served)
+type correlator struct {
+	mu      sync.Mutex
+	lastID  uint32
+	pending map[uint32]pendingRequest
+
+	max      int
+	reserved int
+	waiters  []chan error
+	closed   bool
+}
+
+type pendingRequest struct {
+	response chan<- []byte
+	timer    *time.Timer
+}
+
+// acquire reserves a slot for a request (at most max pending requests)
+// It waits for a slot until done is closed (chromecast.ErrRequestTimeout)
+func (c *correlator) acquire(max int, done <-chan struct{}) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return chromecast.ErrConnectionClosed
+	}
+	c.max = max
+	if len(c.waiters) == 0 && c.available() {
+		c.reserved++
+		c.mu.Unlock()
+		return nil
+	}
+	waiter := make(chan error, 1)
+	c.waiters = append(c.waiters, waiter)
+	c.mu.Unlock()
+
+	select {
+	case err := <-waiter:
+		return err
+	case <-done:
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, w := range c.waiters {
+		if w == waiter {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return chromecast.ErrRequestTimeout
+		}
+	}
+	// the slot was granted in the meantime
+	if err := <-waiter; err == nil {
+		c.reserved--
+		c.grant()
+	}
+	return chromecast.ErrRequestTimeout
+}
+
+func (c *correlator) available() bool {
+	return len(c.pending)+c.reserved < c.max
+}
+
+// grant hands the available slots to the first waiters
+func (c *correlator) grant() {
+	for len(c.waiters) > 0 && c.available() {
+		c.reserved++
+		c.waiters[0] <- nil
+		c.waiters = c.waiters[1:]
+	}
+}
+
+// add registers a new request (using the slot reserved by acquire), which will be dropped after timeout
+func (c *correlator) add(timeout time.Duration) (uint32, <-chan []byte) {
+	response := make(chan []byte, 1)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reserved--
+	c.lastID++
+	if c.lastID == 0 {
+		// 0 is used by the chromecast for unsolicited messages
+		c.lastID++
+	}
+	id := c.lastID
+	if c.pending == nil {
+		c.pending = make(map[uint32]pendingRequest, 1)
+	}
+	c.pending[id] = pendingRequest{
+		response: response,
+		timer: time.AfterFunc(timeout, func() {
+			c.cancel(id)
+		}),
+	}
+	return id, response
+}
+
+// resolve forwards the payload to the pending request (if any)
+func (c *correlator) resolve(id uint32, payload []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if p, ok := c.pending[id]; ok {
+		p.response <- payload
+		c.drop(id, p)
+	}
+}
+
+// cancel drops the pending request (if any), closing its channel
+func (c *correlator) cancel(id uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if p, ok := c.pending[id]; ok {
+		c.drop(id, p)
+	}
+}
+
+// closeAll drops all the pending requests and the waiting ones
+func (c *correlator) closeAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for _, w := range c.waiters {
+		w <- chromecast.ErrConnectionClosed
+	}
+	c.waiters = nil
+	for id, p := range c.pending {
+		c.drop(id, p)
+	}
+}
+
+// len returns the number of pending requests
+func (c *correlator) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.pending)
+}
+
+func (c *correlator) drop(id uint32, p pendingRequest) {
+	p.timer.Stop()
+	close(p.response)
+	delete(c.pending, id)
+	c.grant()
+}
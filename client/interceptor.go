@@ -0,0 +1,74 @@
+package client
+
+import (
+	"fmt"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// Direction of a message
+type Direction int
+
+// Directions of the messages
+const (
+	Inbound Direction = iota
+	Outbound
+)
+
+func (d Direction) String() string {
+	if d == Outbound {
+		return "outbound"
+	}
+	return "inbound"
+}
+
+// Message exchanged with the chromecast
+type Message struct {
+	chromecast.Envelope
+	Payload []byte
+	// Binary is true for a binary payload (PayloadType BINARY)
+	Binary bool
+}
+
+// Interceptor sees every message exchanged (inbound after reception, outbound before sending)
+// It can modify the message.
+// Returning an error drops the message (the error is returned by Send, or logged by Dispatch)
+type Interceptor func(dir Direction, msg *Message) error
+
+// Intercept registers interceptors (called in the order of registration)
+func (c *Client) Intercept(interceptors ...Interceptor) {
+	c.imu.Lock()
+	defer c.imu.Unlock()
+	c.interceptors = append(c.interceptors, interceptors...)
+}
+
+func (c *Client) intercept(dir Direction, msg Message) (chromecast.Envelope, []byte, error) {
+	c.imu.RLock()
+	interceptors := c.interceptors
+	c.imu.RUnlock()
+
+	for _, interceptor := range interceptors {
+		if err := interceptor(dir, &msg); err != nil {
+			return msg.Envelope, msg.Payload, err
+		}
+	}
+	return msg.Envelope, msg.Payload, nil
+}
+
+// LogInterceptor logs every message
+func LogInterceptor(logger chromecast.Logger) Interceptor {
+	return func(dir Direction, msg *Message) error {
+		payload := string(msg.Payload)
+		if msg.Binary {
+			payload = fmt.Sprintf("<%d bytes>", len(msg.Payload))
+		}
+		logger.Log(
+			"direction", dir,
+			"source", msg.Source,
+			"destination", msg.Destination,
+			"namespace", msg.Namespace,
+			"payload", payload,
+		)
+		return nil
+	}
+}
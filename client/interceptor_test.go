@@ -0,0 +1,63 @@
+package client_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/client"
+	"github.com/oliverpool/go-chromecast/log"
+)
+
+func TestInterceptOutbound(t *testing.T) {
+	serializer := newChanSerializer()
+	c := client.New(serializer, log.NopLogger())
+
+	var seen []client.Direction
+	c.Intercept(func(dir client.Direction, msg *client.Message) error {
+		seen = append(seen, dir)
+		msg.Payload = bytes.Replace(msg.Payload, []byte("PING"), []byte("PONG"), 1)
+		return nil
+	})
+
+	if err := c.Send(chromecast.Envelope{Destination: "Tr@n$p0rt-0", Namespace: "ns"}, map[string]string{"type": "PING"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sent := <-serializer.out
+	if string(sent.payload) != `{"type":"PONG"}` {
+		t.Errorf("the payload should have been rewritten, got %s", sent.payload)
+	}
+	if len(seen) != 1 || seen[0] != client.Outbound {
+		t.Errorf("one outbound message expected, got %v", seen)
+	}
+
+	dropErr := errors.New("dropped")
+	c.Intercept(func(dir client.Direction, msg *client.Message) error {
+		return dropErr
+	})
+	if err := c.Send(chromecast.Envelope{Destination: "Tr@n$p0rt-0"}, map[string]string{}); err != dropErr {
+		t.Errorf("the interceptor error should have been returned, got %v", err)
+	}
+}
+
+func TestInterceptInbound(t *testing.T) {
+	serializer := newChanSerializer()
+	seen := make(chan client.Message, 1)
+
+	c := client.New(serializer, log.NopLogger())
+	c.Intercept(func(dir client.Direction, msg *client.Message) error {
+		if dir == client.Inbound {
+			seen <- *msg
+		}
+		return nil
+	})
+
+	env := chromecast.Envelope{Source: "receiver-0", Destination: "sender-0", Namespace: "ns"}
+	serializer.in <- message{env, []byte(`{"type":"RECEIVER_STATUS"}`)}
+
+	msg := <-seen
+	if msg.Envelope != env || string(msg.Payload) != `{"type":"RECEIVER_STATUS"}` {
+		t.Errorf("unexpected inbound message: %+v", msg)
+	}
+}
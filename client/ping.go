@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+const heartbeatNamespace = "urn:x-cast:com.google.cast.tp.heartbeat"
+
+var pingEnvelope = chromecast.Envelope{
+	Source:      defaultSource,
+	Destination: "receiver-0",
+	Namespace:   heartbeatNamespace,
+}
+
+// pongWaiter is notified by the PONG answering its PING
+type pongWaiter struct {
+	ch chan struct{}
+	// skip the PONGs of the PINGs sent before (by the heartbeat for instance)
+	skip int
+}
+
+// Ping sends a PING and returns the round-trip time until its PONG
+// It returns chromecast.ErrRequestTimeout if ctx is done before
+func (c *Client) Ping(ctx context.Context) (time.Duration, error) {
+	pay, err := json.Marshal(chromecast.PayloadWithID{Type: "PING"})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal payload: %s", err)
+	}
+	if err = c.ensureConnected(pingEnvelope, pay); err != nil {
+		return 0, err
+	}
+
+	pong := make(chan struct{})
+	start := time.Now()
+	err = c.sendNotifying(pingEnvelope, pay, pong)
+	if err == nil {
+		select {
+		case <-pong:
+			return time.Since(start), nil
+		case <-ctx.Done():
+			err = chromecast.ErrRequestTimeout
+		}
+	}
+
+	c.mu.Lock()
+	c.removePongWaiter(pong)
+	c.mu.Unlock()
+	return 0, err
+}
+
+// removePongWaiter forgets the waiter of the pong channel (if any)
+// c.mu must be held
+func (c *Client) removePongWaiter(pong chan struct{}) {
+	for i, w := range c.pongWaiters {
+		if w.ch == pong {
+			c.pongWaiters = append(c.pongWaiters[:i], c.pongWaiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// receivedPong notifies the pending ping answered by this PONG
+// (a PONG received while no PING is in flight is ignored)
+// c.mu must be held
+func (c *Client) receivedPong() {
+	if c.pingsInFlight == 0 {
+		return
+	}
+	c.pingsInFlight--
+	waiters := c.pongWaiters[:0]
+	for _, w := range c.pongWaiters {
+		if w.skip == 0 {
+			close(w.ch)
+			continue
+		}
+		w.skip--
+		waiters = append(waiters, w)
+	}
+	c.pongWaiters = waiters
+}
+
+// isPing returns true for a PING payload
+func isPing(payload []byte) bool {
+	var p struct {
+		Type string `json:"type"`
+	}
+	return json.Unmarshal(payload, &p) == nil && p.Type == "PING"
+}
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// QueuePolicy defines what happens when the send queue is full
+type QueuePolicy int
+
+const (
+	// BlockWhenFull makes Send wait until there is room in the queue (backpressure)
+	BlockWhenFull QueuePolicy = iota
+	// ErrorWhenFull makes Send return chromecast.ErrQueueFull immediately
+	ErrorWhenFull
+)
+
+// DefaultQueueSize is the size of the send queue (if 0)
+const DefaultQueueSize = 16
+
+// QueuedSerializer sends the messages from a bounded queue, in a dedicated goroutine,
+// so that a stuck connection does not block the senders (depending on the Policy)
+// Send returns as soon as the message is queued: the write errors are logged
+type QueuedSerializer struct {
+	chromecast.Serializer
+	Logger chromecast.Logger
+	Policy QueuePolicy
+
+	queue     chan queuedMessage
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+type queuedMessage struct {
+	env      chromecast.Envelope
+	payload  []byte
+	isBinary bool
+}
+
+// NewQueuedSerializer starts sending the messages queued (size is DefaultQueueSize if 0)
+func NewQueuedSerializer(serializer chromecast.Serializer, size int, policy QueuePolicy, logger chromecast.Logger) *QueuedSerializer {
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+	q := &QueuedSerializer{
+		Serializer: serializer,
+		Logger:     logger,
+		Policy:     policy,
+		queue:      make(chan queuedMessage, size),
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+func (q *QueuedSerializer) run() {
+	defer close(q.stopped)
+	for {
+		select {
+		case <-q.done:
+			// flush the remaining messages
+			for {
+				select {
+				case m := <-q.queue:
+					q.send(m)
+				default:
+					return
+				}
+			}
+		case m := <-q.queue:
+			q.send(m)
+		}
+	}
+}
+
+func (q *QueuedSerializer) send(m queuedMessage) {
+	var err error
+	if m.isBinary {
+		err = q.Serializer.(chromecast.BinarySerializer).SendBinary(m.env, m.payload)
+	} else {
+		err = q.Serializer.Send(m.env, m.payload)
+	}
+	if err != nil {
+		q.Logger.Log("step", "send", "namespace", m.env.Namespace, "err", err)
+	}
+}
+
+// Send queues the payload
+func (q *QueuedSerializer) Send(env chromecast.Envelope, payload []byte) error {
+	return q.enqueue(queuedMessage{env: env, payload: payload})
+}
+
+// SendBinary queues the binary payload (the underlying Serializer must be a chromecast.BinarySerializer)
+func (q *QueuedSerializer) SendBinary(env chromecast.Envelope, payload []byte) error {
+	if _, ok := q.Serializer.(chromecast.BinarySerializer); !ok {
+		return fmt.Errorf("the serializer does not support binary payloads")
+	}
+	return q.enqueue(queuedMessage{env: env, payload: payload, isBinary: true})
+}
+
+// ReceiveAny receives a message (with isBinary always false if the underlying Serializer does not support binary payloads)
+func (q *QueuedSerializer) ReceiveAny() (chromecast.Envelope, []byte, bool, error) {
+	if s, ok := q.Serializer.(chromecast.BinarySerializer); ok {
+		return s.ReceiveAny()
+	}
+	env, payload, err := q.Serializer.Receive()
+	return env, payload, false, err
+}
+
+func (q *QueuedSerializer) enqueue(m queuedMessage) error {
+	select {
+	case <-q.done:
+		return chromecast.ErrConnectionClosed
+	default:
+	}
+
+	if q.Policy == ErrorWhenFull {
+		select {
+		case q.queue <- m:
+			return nil
+		default:
+			return chromecast.ErrQueueFull
+		}
+	}
+	select {
+	case q.queue <- m:
+		return nil
+	case <-q.done:
+		return chromecast.ErrConnectionClosed
+	}
+}
+
+// Len returns the number of messages waiting to be sent
+func (q *QueuedSerializer) Len() int {
+	return len(q.queue)
+}
+
+// Close stops accepting messages and waits for the queued messages to be sent
+func (q *QueuedSerializer) Close() error {
+	q.closeOnce.Do(func() {
+		close(q.done)
+	})
+	<-q.stopped
+	return nil
+}
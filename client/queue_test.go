@@ -0,0 +1,57 @@
+package client_test
+
+import (
+	"testing"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/client"
+	"github.com/oliverpool/go-chromecast/log"
+)
+
+var _ chromecast.BinarySerializer = &client.QueuedSerializer{}
+
+// stuckSerializer blocks every Send until unblock is closed
+type stuckSerializer struct {
+	chanSerializer
+	unblock chan struct{}
+}
+
+func (s stuckSerializer) Send(env chromecast.Envelope, payload []byte) error {
+	<-s.unblock
+	return s.chanSerializer.Send(env, payload)
+}
+
+func TestQueueErrorWhenFull(t *testing.T) {
+	stuck := stuckSerializer{
+		chanSerializer: chanSerializer{out: make(chan message, 10)},
+		unblock:        make(chan struct{}),
+	}
+	q := client.NewQueuedSerializer(stuck, 2, client.ErrorWhenFull, log.NopLogger())
+
+	// wait for the first message to be stuck while sending
+	err := q.Send(chromecast.Envelope{}, []byte(`{}`))
+	for q.Len() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	sent := 1
+	for ; sent < 10 && err == nil; sent++ {
+		err = q.Send(chromecast.Envelope{}, []byte(`{}`))
+	}
+	if err != chromecast.ErrQueueFull {
+		t.Fatalf("ErrQueueFull expected, got %v", err)
+	}
+	// 1 message being sent, 2 in the queue, the last one failed
+	if sent != 4 {
+		t.Errorf("the 4th message should have failed, got %d", sent)
+	}
+
+	close(stuck.unblock)
+	q.Close()
+	if n := len(stuck.out); n != 3 {
+		t.Errorf("the queued messages should have been flushed, got %d", n)
+	}
+	if err = q.Send(chromecast.Envelope{}, []byte(`{}`)); err != chromecast.ErrConnectionClosed {
+		t.Errorf("ErrConnectionClosed expected after Close, got %v", err)
+	}
+}
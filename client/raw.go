@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// defaultSource is the sender id used by the raw messages
+const defaultSource = "sender-0"
+
+// SendRaw sends the payload as-is to the destination (like an app transportId)
+func (c *Client) SendRaw(namespace, destination string, payload []byte) error {
+	env := chromecast.Envelope{
+		Source:      defaultSource,
+		Destination: destination,
+		Namespace:   namespace,
+	}
+	if err := c.ensureConnected(env, payload); err != nil {
+		return err
+	}
+	return c.send(env, payload)
+}
+
+// RequestRaw sends the payload (a JSON object, which gets a requestId) to the destination
+// and returns the raw response payload
+// It returns chromecast.ErrRequestTimeout if ctx is done before
+func (c *Client) RequestRaw(ctx context.Context, namespace, destination string, payload []byte) ([]byte, error) {
+	var object rawObject
+	if err := json.Unmarshal(payload, &object); err != nil {
+		return nil, fmt.Errorf("the payload must be a JSON object: %w", err)
+	}
+	if object == nil {
+		return nil, fmt.Errorf("the payload must be a JSON object, got null")
+	}
+	env := chromecast.Envelope{
+		Source:      defaultSource,
+		Destination: destination,
+		Namespace:   namespace,
+	}
+	return c.RequestCtx(ctx, env, object)
+}
+
+// rawObject is a JSON object which accepts a requestId
+type rawObject map[string]json.RawMessage
+
+func (r rawObject) SetRequestID(id uint32) {
+	r["requestId"] = json.RawMessage(strconv.FormatUint(uint64(id), 10))
+}
@@ -0,0 +1,219 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// MarshalText encodes the direction as "inbound" or "outbound"
+func (d Direction) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes "inbound" or "outbound"
+func (d *Direction) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "inbound":
+		*d = Inbound
+	case "outbound":
+		*d = Outbound
+	default:
+		return fmt.Errorf("unknown direction %q", text)
+	}
+	return nil
+}
+
+// RecordedFrame is a message of a recorded session (written as one JSON object per line)
+type RecordedFrame struct {
+	Time        time.Time `json:"time"`
+	Direction   Direction `json:"direction"`
+	Source      string    `json:"source"`
+	Destination string    `json:"destination"`
+	Namespace   string    `json:"namespace"`
+	Payload     string    `json:"payload,omitempty"`
+	// Binary payload (base64 encoded in JSON)
+	Binary []byte `json:"binary,omitempty"`
+}
+
+func (f RecordedFrame) envelope() chromecast.Envelope {
+	return chromecast.Envelope{
+		Source:      f.Source,
+		Destination: f.Destination,
+		Namespace:   f.Namespace,
+	}
+}
+
+// RecordingSerializer writes every frame exchanged by the Serializer to W
+// (see ReplaySerializer to feed them back)
+type RecordingSerializer struct {
+	chromecast.Serializer
+	W io.Writer
+
+	mu sync.Mutex
+}
+
+func (r *RecordingSerializer) record(dir Direction, env chromecast.Envelope, payload []byte, isBinary bool) error {
+	frame := RecordedFrame{
+		Time:        time.Now(),
+		Direction:   dir,
+		Source:      env.Source,
+		Destination: env.Destination,
+		Namespace:   env.Namespace,
+	}
+	if isBinary {
+		frame.Binary = payload
+	} else {
+		frame.Payload = string(payload)
+	}
+	line, err := json.Marshal(frame)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.W.Write(append(line, '\n'))
+	return err
+}
+
+// Receive receives and records a message
+func (r *RecordingSerializer) Receive() (chromecast.Envelope, []byte, error) {
+	env, payload, _, err := r.ReceiveAny()
+	return env, payload, err
+}
+
+// ReceiveAny receives and records a message (binary if the Serializer is a chromecast.BinarySerializer)
+func (r *RecordingSerializer) ReceiveAny() (env chromecast.Envelope, payload []byte, isBinary bool, err error) {
+	if serializer, ok := r.Serializer.(chromecast.BinarySerializer); ok {
+		env, payload, isBinary, err = serializer.ReceiveAny()
+	} else {
+		env, payload, err = r.Serializer.Receive()
+	}
+	if err != nil {
+		return env, payload, isBinary, err
+	}
+	if err = r.record(Inbound, env, payload, isBinary); err != nil {
+		return env, payload, isBinary, fmt.Errorf("could not record the frame: %w", err)
+	}
+	return env, payload, isBinary, nil
+}
+
+// Send records and sends a message
+func (r *RecordingSerializer) Send(env chromecast.Envelope, payload []byte) error {
+	if err := r.record(Outbound, env, payload, false); err != nil {
+		return fmt.Errorf("could not record the frame: %w", err)
+	}
+	return r.Serializer.Send(env, payload)
+}
+
+// SendBinary records and sends a binary message (the Serializer must be a chromecast.BinarySerializer)
+func (r *RecordingSerializer) SendBinary(env chromecast.Envelope, payload []byte) error {
+	serializer, ok := r.Serializer.(chromecast.BinarySerializer)
+	if !ok {
+		return fmt.Errorf("the serializer does not support binary payloads")
+	}
+	if err := r.record(Outbound, env, payload, true); err != nil {
+		return fmt.Errorf("could not record the frame: %w", err)
+	}
+	return serializer.SendBinary(env, payload)
+}
+
+// ReplaySerializer feeds back a recorded session (see RecordingSerializer)
+// Each inbound frame is received once the outbound frames recorded before it have been sent
+// (the content of the sent messages is not checked, see Sent).
+// Receive returns io.EOF at the end of the recording.
+type ReplaySerializer struct {
+	frames []RecordedFrame
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	next int
+	// outbound frames before next
+	outbound int
+	sent     []Message
+}
+
+// NewReplaySerializer reads the recorded frames
+func NewReplaySerializer(r io.Reader) (*ReplaySerializer, error) {
+	s := &ReplaySerializer{}
+	s.cond = sync.NewCond(&s.mu)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var frame RecordedFrame
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal into RecordedFrame: %s", err)
+		}
+		s.frames = append(s.frames, frame)
+	}
+	return s, scanner.Err()
+}
+
+// Receive returns the next inbound frame
+func (s *ReplaySerializer) Receive() (chromecast.Envelope, []byte, error) {
+	env, payload, _, err := s.ReceiveAny()
+	return env, payload, err
+}
+
+// ReceiveAny returns the next inbound frame (waiting for the previous outbound frames to be sent)
+func (s *ReplaySerializer) ReceiveAny() (chromecast.Envelope, []byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ; s.next < len(s.frames); s.next++ {
+		frame := s.frames[s.next]
+		if frame.Direction == Outbound {
+			s.outbound++
+			continue
+		}
+		for len(s.sent) < s.outbound {
+			s.cond.Wait()
+		}
+		s.next++
+		if frame.Binary != nil {
+			return frame.envelope(), frame.Binary, true, nil
+		}
+		return frame.envelope(), []byte(frame.Payload), false, nil
+	}
+	return chromecast.Envelope{}, nil, false, io.EOF
+}
+
+// Send stores the message (see Sent)
+func (s *ReplaySerializer) Send(env chromecast.Envelope, payload []byte) error {
+	return s.store(Message{Envelope: env, Payload: payload})
+}
+
+// SendBinary stores the binary message (see Sent)
+func (s *ReplaySerializer) SendBinary(env chromecast.Envelope, payload []byte) error {
+	return s.store(Message{Envelope: env, Payload: payload, Binary: true})
+}
+
+func (s *ReplaySerializer) store(msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, msg)
+	s.cond.Broadcast()
+	return nil
+}
+
+// Sent returns the messages sent so far (to compare them with the recording, see Recorded)
+func (s *ReplaySerializer) Sent() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.sent...)
+}
+
+// Recorded returns the frames of the recording
+func (s *ReplaySerializer) Recorded() []RecordedFrame {
+	return s.frames
+}
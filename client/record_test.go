@@ -0,0 +1,51 @@
+package client_test
+
+import (
+	"bytes"
+	"testing"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/client"
+	"github.com/oliverpool/go-chromecast/command"
+	"github.com/oliverpool/go-chromecast/log"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	env := chromecast.Envelope{Source: "sender-0", Destination: "receiver-0", Namespace: "urn:x-cast:com.google.cast.receiver"}
+	status := `{"type":"RECEIVER_STATUS","requestId":1,"status":{}}`
+
+	// record
+	var recording bytes.Buffer
+	serializer := newChanSerializer()
+	c := client.New(&client.RecordingSerializer{Serializer: serializer, W: &recording}, log.NopLogger())
+	response, err := c.Request(env, command.Map{"type": "GET_STATUS"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-serializer.out // CONNECT
+	<-serializer.out
+	serializer.in <- message{chromecast.Envelope{Source: "receiver-0", Destination: "sender-0", Namespace: env.Namespace}, []byte(status)}
+	if payload := <-response; string(payload) != status {
+		t.Fatalf("unexpected response: %s", payload)
+	}
+
+	// replay
+	replay, err := client.NewReplaySerializer(&recording)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if frames := replay.Recorded(); len(frames) != 3 || frames[2].Direction != client.Inbound {
+		t.Fatalf("3 frames expected (CONNECT, GET_STATUS and RECEIVER_STATUS), got %+v", frames)
+	}
+	c = client.New(replay, log.NopLogger())
+	response, err = c.Request(env, command.Map{"type": "GET_STATUS"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payload := <-response; string(payload) != status {
+		t.Errorf("the recorded response should have been replayed, got %s", payload)
+	}
+	if sent := replay.Sent(); len(sent) != 2 || string(sent[1].Payload) != `{"requestId":1,"type":"GET_STATUS"}` {
+		t.Errorf("unexpected sent messages: %+v", sent)
+	}
+}
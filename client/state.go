@@ -0,0 +1,50 @@
+package client
+
+import (
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// State returns a channel receiving the current connection state and then its changes
+// A slow receiver only misses intermediate states (the latest one is always delivered).
+// The channel is closed after the chromecast.Closed state.
+func (c *Client) State() <-chan chromecast.ConnectionState {
+	ch := make(chan chromecast.ConnectionState, 1)
+	c.smu.Lock()
+	defer c.smu.Unlock()
+	ch <- c.state
+	if c.state == chromecast.Closed {
+		close(ch)
+		return ch
+	}
+	c.stateListeners = append(c.stateListeners, ch)
+	return ch
+}
+
+// Reconnecting must be called when the underlying connection dropped and is being reestablished
+// (see Reconnected)
+func (c *Client) Reconnecting() {
+	c.setState(chromecast.Reconnecting)
+}
+
+func (c *Client) setState(state chromecast.ConnectionState) {
+	c.smu.Lock()
+	defer c.smu.Unlock()
+	if c.state == state || c.state == chromecast.Closed {
+		return
+	}
+	c.state = state
+	for _, ch := range c.stateListeners {
+		// keep only the latest state
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- state
+		if state == chromecast.Closed {
+			close(ch)
+		}
+	}
+	if state == chromecast.Closed {
+		c.stateListeners = nil
+	}
+}
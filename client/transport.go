@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// Transport sends and receives the messages of an app (identified by its transportId)
+// The empty Source and Destination of the envelopes are filled automatically
+// and the virtual connection is opened before the first message (see Close)
+type Transport struct {
+	client      *Client
+	transportID string
+}
+
+// ForTransport returns a Transport bound to the app transportId
+// It implements chromecast.Client (Close only closes the virtual connection)
+func (c *Client) ForTransport(transportID string) *Transport {
+	return &Transport{
+		client:      c,
+		transportID: transportID,
+	}
+}
+
+// ID returns the transportId
+func (t *Transport) ID() string {
+	return t.transportID
+}
+
+// outbound fills the envelope of a message sent to the app
+func (t *Transport) outbound(env chromecast.Envelope) chromecast.Envelope {
+	if env.Source == "" {
+		env.Source = defaultSource
+	}
+	if env.Destination == "" {
+		env.Destination = t.transportID
+	}
+	return env
+}
+
+// inbound fills the envelope of a message received from the app
+func (t *Transport) inbound(env chromecast.Envelope) chromecast.Envelope {
+	if env.Source == "" {
+		env.Source = t.transportID
+	}
+	if env.Destination == "" {
+		env.Destination = defaultSource
+	}
+	return env
+}
+
+func (t *Transport) Send(env chromecast.Envelope, payload interface{}) error {
+	return t.client.Send(t.outbound(env), payload)
+}
+
+func (t *Transport) Request(env chromecast.Envelope, payload chromecast.IdentifiablePayload) (<-chan []byte, error) {
+	return t.client.Request(t.outbound(env), payload)
+}
+
+// RequestCtx sends the request and waits for the response (see Client.RequestCtx)
+func (t *Transport) RequestCtx(ctx context.Context, env chromecast.Envelope, payload chromecast.IdentifiablePayload) ([]byte, error) {
+	return t.client.RequestCtx(ctx, t.outbound(env), payload)
+}
+
+// Listen forwards the messages of the app (the envelope is seen from the chromecast)
+func (t *Transport) Listen(env chromecast.Envelope, responseType string, ch chan<- []byte) {
+	t.client.Listen(t.inbound(env), responseType, ch)
+}
+
+// Close sends a CLOSE to the app (the client stays connected)
+func (t *Transport) Close() error {
+	return t.client.Send(chromecast.Envelope{
+		Source:      defaultSource,
+		Destination: t.transportID,
+		Namespace:   ConnectionNamespace,
+	}, chromecast.PayloadWithID{Type: "CLOSE"})
+}
+
+// OnReconnect registers a callback to call after a reconnection of the client (see Client.OnReconnect)
+func (t *Transport) OnReconnect(cb func()) {
+	t.client.OnReconnect(cb)
+}
@@ -0,0 +1,48 @@
+package client_test
+
+import (
+	"testing"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/client"
+	"github.com/oliverpool/go-chromecast/log"
+)
+
+func TestForTransport(t *testing.T) {
+	serializer := newChanSerializer()
+	c := client.New(serializer, log.NopLogger())
+	transport := c.ForTransport("transport-1")
+
+	var _ chromecast.Client = transport
+
+	statuses := make(chan []byte, 1)
+	transport.Listen(chromecast.Envelope{Namespace: "ns"}, "STATUS", statuses)
+
+	if err := transport.Send(chromecast.Envelope{Namespace: "ns"}, map[string]string{"type": "GET_STATUS"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	connect := <-serializer.out
+	if connect.env.Namespace != client.ConnectionNamespace || connect.env.Destination != "transport-1" {
+		t.Errorf("a CONNECT to the transport was expected, got %+v", connect.env)
+	}
+	sent := <-serializer.out
+	expected := chromecast.Envelope{Source: "sender-0", Destination: "transport-1", Namespace: "ns"}
+	if sent.env != expected {
+		t.Errorf("the envelope should have been filled, got %+v", sent.env)
+	}
+
+	serializer.in <- message{chromecast.Envelope{Source: "transport-1", Destination: "sender-0", Namespace: "ns"}, []byte(`{"type":"STATUS"}`)}
+	if payload := <-statuses; string(payload) != `{"type":"STATUS"}` {
+		t.Errorf("unexpected payload: %s", payload)
+	}
+
+	if err := transport.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if closing := <-serializer.out; string(closing.payload) != `{"type":"CLOSE"}` || closing.env.Destination != "transport-1" {
+		t.Errorf("a CLOSE to the transport was expected, got %s", closing.payload)
+	}
+	if destinations := c.ConnectedDestinations(); len(destinations) != 0 {
+		t.Errorf("the transport should be disconnected, got %v", destinations)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/oliverpool/go-chromecast/command/media"
+	"github.com/spf13/cobra"
+)
+
+var attachControl bool
+
+func init() {
+	attachCmd.Flags().BoolVarP(&attachControl, "control", "c", true, "Control the media afterwards")
+	rootCmd.AddCommand(attachCmd)
+}
+
+var attachCmd = &cobra.Command{
+	Use:   "attach",
+	Short: "Attach to the media playing on the chromecast (without reloading it)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger, ctx, cancel := flags()
+		defer cancel()
+
+		client, status, err := GetClientWithStatus(ctx, logger)
+		if err != nil {
+			return fmt.Errorf("could not get a client: %w", err)
+		}
+		defer client.Close()
+
+		_, session, err := media.Attach(ctx, client, status)
+		if err != nil {
+			return fmt.Errorf("could not attach to a media session: %w", err)
+		}
+		st, err := session.StatusCtx(ctx)
+		if err != nil {
+			return fmt.Errorf("could not get the media status: %w", err)
+		}
+		fmt.Printf("Attached to the media session %d (%s)\n", session.ID, st.PlayerState)
+		if st.Item != nil {
+			fmt.Printf("  %s\n", st.Item.ContentId)
+		}
+
+		if attachControl {
+			return remote(ctx, cancel, logger, client, status)
+		}
+		return nil
+	},
+}
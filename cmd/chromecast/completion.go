@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oliverpool/go-chromecast/discovery"
+	"github.com/oliverpool/go-chromecast/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// namesScanTimeout bounds the discovery of the device names to complete, when none are known yet
+const namesScanTimeout = time.Second
+
+// bashCompleteNames completes the --name flag with the output of the hidden __names command
+// (the spaces of the names are escaped)
+const bashCompleteNames = `__chromecast_names()
+{
+    local IFS=$'\n'
+    local names
+    names=$(chromecast __names 2>/dev/null) || return
+    COMPREPLY=( $(compgen -W "${names}" -- "${cur}") )
+    COMPREPLY=( "${COMPREPLY[@]// /\\ }" )
+}
+`
+
+func init() {
+	rootCmd.BashCompletionFunction = bashCompleteNames
+	rootCmd.AddCommand(completionCmd, namesCmd)
+}
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish",
+	Short: "Print the shell completion script (the device names are completed after --name)",
+	Long: `Print the shell completion script (the device names are completed after --name).
+
+To load the completions:
+  bash: source <(chromecast completion bash)
+  zsh:  chromecast completion zsh > "${fpath[1]}/_chromecast" (then restart zsh)
+  fish: chromecast completion fish > ~/.config/fish/completions/chromecast.fish`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return genCompletion(os.Stdout, rootCmd, args[0])
+	},
+}
+
+// genCompletion writes the completion script of the shell
+func genCompletion(w io.Writer, root *cobra.Command, shell string) error {
+	switch shell {
+	case "bash":
+		return root.GenBashCompletion(w)
+	case "zsh":
+		return genZshCompletion(w, root)
+	case "fish":
+		return genFishCompletion(w, root)
+	}
+	return fmt.Errorf("unsupported shell '%s' (bash, zsh or fish)", shell)
+}
+
+// namesCmd prints the known device names (used by the completion scripts)
+var namesCmd = &cobra.Command{
+	Use:    "__names",
+	Short:  "Print the names of the known chromecasts, one per line",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names := make(map[string]bool)
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		for _, d := range cfg.Devices {
+			names[d.Name] = true
+		}
+
+		if deviceFinder.CacheFile != "" {
+			store := discovery.FileStore{Path: deviceFinder.CacheFile}
+			cached, err := store.Load()
+			if err != nil {
+				return err
+			}
+			for _, d := range cached {
+				names[d.Name()] = true
+			}
+			if len(names) == 0 {
+				// quick discovery, which fills the cache for the next completions
+				ctx, cancel := context.WithTimeout(context.Background(), namesScanTimeout)
+				defer cancel()
+				scanner, err := deviceFinder.Scanner(log.NopLogger(), nil)
+				if err != nil {
+					return err
+				}
+				devices, _ := discovery.Service{Scanner: discovery.Cache{
+					Scanner: scanner,
+					Store:   store,
+				}}.All(ctx)
+				for _, d := range devices {
+					names[d.Name()] = true
+				}
+			}
+		}
+
+		sorted := make([]string, 0, len(names))
+		for name := range names {
+			if name != "" {
+				sorted = append(sorted, name)
+			}
+		}
+		sort.Strings(sorted)
+		for _, name := range sorted {
+			fmt.Println(name)
+		}
+		return nil
+	},
+}
+
+// genFishCompletion writes the fish completions of the commands and of their flags
+func genFishCompletion(w io.Writer, root *cobra.Command) error {
+	name := root.Name()
+	fmt.Fprintf(w, "# fish completion for %s\n", name)
+	writeFishFlags(w, name, "", root.PersistentFlags())
+
+	var walk func(parent *cobra.Command, condition string)
+	walk = func(parent *cobra.Command, condition string) {
+		for _, c := range parent.Commands() {
+			if c.Hidden || c.Name() == "help" {
+				continue
+			}
+			fmt.Fprintf(w, "complete -c %s -f -n %s -a %s -d %s\n", name, fishQuote(condition), c.Name(), fishQuote(c.Short))
+			sub := "__fish_seen_subcommand_from " + c.Name()
+			writeFishFlags(w, name, sub, c.LocalNonPersistentFlags())
+			walk(c, sub)
+		}
+	}
+	walk(root, "__fish_use_subcommand")
+	return nil
+}
+
+// writeFishFlags writes the completions of the flags (when the condition is met, if any)
+func writeFishFlags(w io.Writer, name, condition string, flags *pflag.FlagSet) {
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		line := "complete -c " + name
+		if condition != "" {
+			line += " -n " + fishQuote(condition)
+		}
+		line += " -l " + f.Name
+		if f.Shorthand != "" {
+			line += " -s " + f.Shorthand
+		}
+		if _, ok := f.Annotations[cobra.BashCompCustom]; ok && f.Name == "name" {
+			line += " -x -a " + fishQuote("("+name+" __names 2>/dev/null)")
+		} else if f.Value.Type() != "bool" {
+			line += " -r"
+		}
+		line += " -d " + fishQuote(f.Usage)
+		fmt.Fprintln(w, line)
+	})
+}
+
+// fishQuote returns s between single quotes
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// zshCompleteNames completes the --name flag with the output of the hidden __names command
+// (the colons of the names are escaped for _describe)
+const zshCompleteNames = `__chromecast_names() {
+    local -a names
+    names=("${(@f)$(chromecast __names 2>/dev/null)}")
+    names=("${(@)names//:/\\:}")
+    _describe -t names 'device name' names
+}
+`
+
+// genZshCompletion writes a native zsh completion script: a function for each command
+// completing its flags and its subcommands
+func genZshCompletion(w io.Writer, root *cobra.Command) error {
+	fmt.Fprintf(w, "#compdef %s\n\n", root.Name())
+	fmt.Fprint(w, zshCompleteNames+"\n")
+
+	var walk func(c *cobra.Command, function string)
+	walk = func(c *cobra.Command, function string) {
+		var subcommands []*cobra.Command
+		for _, sub := range c.Commands() {
+			if !sub.Hidden && sub.Name() != "help" {
+				subcommands = append(subcommands, sub)
+			}
+		}
+
+		fmt.Fprintf(w, "%s() {\n", function)
+		if len(subcommands) > 0 {
+			fmt.Fprintln(w, "    local curcontext=\"$curcontext\" state line")
+			fmt.Fprintln(w, "    _arguments -C \\")
+		} else {
+			fmt.Fprintln(w, "    _arguments \\")
+		}
+		for _, flags := range []*pflag.FlagSet{c.LocalFlags(), c.InheritedFlags()} {
+			flags.VisitAll(func(f *pflag.Flag) {
+				if f.Hidden {
+					return
+				}
+				for _, spec := range zshFlagSpecs(f) {
+					fmt.Fprintf(w, "        %s \\\n", spec)
+				}
+			})
+		}
+		if len(subcommands) == 0 {
+			fmt.Fprintln(w, "        '*: :_files'")
+			fmt.Fprint(w, "}\n\n")
+			return
+		}
+
+		fmt.Fprintln(w, "        '1: :->command' \\")
+		fmt.Fprintln(w, "        '*:: :->args'")
+		fmt.Fprintln(w, "    case $state in")
+		fmt.Fprintln(w, "    command)")
+		fmt.Fprintln(w, "        local -a commands")
+		fmt.Fprintln(w, "        commands=(")
+		for _, sub := range subcommands {
+			fmt.Fprintf(w, "            %s\n", zshQuote(sub.Name()+":"+sub.Short))
+		}
+		fmt.Fprintln(w, "        )")
+		fmt.Fprintln(w, "        _describe -t commands 'command' commands")
+		fmt.Fprintln(w, "        ;;")
+		fmt.Fprintln(w, "    args)")
+		fmt.Fprintln(w, "        case $line[1] in")
+		for _, sub := range subcommands {
+			fmt.Fprintf(w, "        %s) %s ;;\n", sub.Name(), zshFunction(function, sub))
+		}
+		fmt.Fprintln(w, "        esac")
+		fmt.Fprintln(w, "        ;;")
+		fmt.Fprintln(w, "    esac")
+		fmt.Fprint(w, "}\n\n")
+
+		for _, sub := range subcommands {
+			walk(sub, zshFunction(function, sub))
+		}
+	}
+	walk(root, "_"+root.Name())
+	_, err := fmt.Fprintf(w, "_%s \"$@\"\n", root.Name())
+	return err
+}
+
+// zshFunction returns the name of the completion function of the subcommand
+func zshFunction(parent string, sub *cobra.Command) string {
+	return parent + "_" + strings.Replace(sub.Name(), "-", "_", -1)
+}
+
+// zshFlagSpecs returns the _arguments specifications of the flag (long and short forms)
+func zshFlagSpecs(f *pflag.Flag) []string {
+	exclusion := ""
+	if f.Shorthand != "" {
+		exclusion = "(-" + f.Shorthand + " --" + f.Name + ")"
+	}
+	description := "[" + strings.NewReplacer("[", `\[`, "]", `\]`).Replace(f.Usage) + "]"
+
+	long, short := "--"+f.Name, "-"+f.Shorthand
+	if f.Value.Type() != "bool" {
+		action := ""
+		if functions, ok := f.Annotations[cobra.BashCompCustom]; ok && len(functions) > 0 {
+			// the same function is defined for bash and zsh (like __chromecast_names)
+			action = functions[0]
+		} else if _, ok := f.Annotations[cobra.BashCompFilenameExt]; ok {
+			action = "_files"
+		}
+		long += "="
+		short += "+"
+		description += ":" + f.Name + ":" + action
+	}
+
+	specs := []string{zshQuote(exclusion + long + description)}
+	if f.Shorthand != "" {
+		specs = append(specs, zshQuote(exclusion+short+description))
+	}
+	return specs
+}
+
+// zshQuote returns s between single quotes
+func zshQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGenCompletion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		var buf bytes.Buffer
+		if err := genCompletion(&buf, rootCmd, shell); err != nil {
+			t.Fatalf("%s: unexpected error: %v", shell, err)
+		}
+		if buf.Len() == 0 {
+			t.Errorf("%s: the completion script is empty", shell)
+		}
+		if !strings.Contains(buf.String(), "__names") {
+			t.Errorf("%s: the device names should be completed", shell)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := genCompletion(&buf, rootCmd, "zsh"); err != nil {
+		t.Fatal(err)
+	}
+	zsh := buf.String()
+	if strings.Contains(zsh, "bashcompinit") {
+		t.Error("the zsh completion should not rely on the bash completion")
+	}
+	for _, expected := range []string{"#compdef chromecast", ":name:__chromecast_names'", "_chromecast_device_factory_reset() {", `_chromecast "$@"`} {
+		if !strings.Contains(zsh, expected) {
+			t.Errorf("the zsh completion should contain %q", expected)
+		}
+	}
+
+	if err := genCompletion(&buf, rootCmd, "powershell"); err == nil {
+		t.Error("an error was expected for an unsupported shell")
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/discovery"
+	"github.com/spf13/cobra"
+)
+
+var configFile string
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", defaultConfigFile(), "TOML configuration file (see chromecast --help for its format)")
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		// the arguments are valid
+		rootCmd.SilenceUsage = true
+		return applyDefaults()
+	}
+}
+
+// defaultConfigFile returns ~/.config/chromecast/config.toml (in $XDG_CONFIG_HOME if set)
+func defaultConfigFile() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "chromecast", "config.toml")
+}
+
+// config of the CLI, for instance:
+//
+//	default_device = "kitchen"
+//	timeout = "5s"
+//	volume_step = 0.02
+//
+//	[[devices]]
+//	name = "kitchen"
+//	ip = "192.168.1.20"
+//	volume_step = 0.1
+//
+//	[loaders.plex]
+//	url = "http://192.168.1.10:32400"
+//	token = "..."
+//
+//	[loaders.jellyfin]
+//	url = "http://192.168.1.10:8096"
+//	api_key = "..."
+//	user_id = "..."
+//
+//	[loaders.vimeo]
+//	access_token = "..."
+//
+//	[loaders.styled]
+//	app_id = "ABCD1234"
+//
+// The flags override the config, which overrides the environment variables
+// (CHROMECAST_DEVICE, CHROMECAST_TIMEOUT and CHROMECAST_VOLUME_STEP).
+type config struct {
+	// DefaultDevice is the device to use, like "kitchen" or "ip:192.168.1.20" (see --device)
+	DefaultDevice string `toml:"default_device"`
+	// Timeout like "5s" (see --timeout)
+	Timeout string `toml:"timeout"`
+	// VolumeStep of the volume changes (see --volume-step)
+	VolumeStep float64 `toml:"volume_step"`
+	// Devices are used instead of discovering them on the network
+	Devices []configDevice `toml:"devices"`
+	// Loaders settings, by loader name (see LoaderConfigs)
+	Loaders map[string]map[string]interface{} `toml:"loaders"`
+}
+
+type configDevice struct {
+	Name string `toml:"name"`
+	UUID string `toml:"uuid"`
+	IP   net.IP `toml:"ip"`
+	Port int    `toml:"port"` // 8009 if 0
+	// VolumeStep overrides the volume_step of the config for this device
+	VolumeStep float64 `toml:"volume_step"`
+}
+
+func (cd configDevice) device() *chromecast.Device {
+	port := cd.Port
+	if port == 0 {
+		port = 8009
+	}
+	return discovery.NewDevice(cd.IP, port, []string{"fn=" + cd.Name, "id=" + cd.UUID})
+}
+
+// loadConfig reads the config file (a missing file is not an error)
+func loadConfig() (cfg config, err error) {
+	if configFile == "" {
+		return cfg, nil
+	}
+	b, err := ioutil.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err = toml.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("could not parse config file '%s': %w", configFile, err)
+	}
+	return cfg, nil
+}
+
+// LoaderConfigs returns the settings of the loaders in JSON (see media.SetLoaderConfigs)
+func (cfg config) LoaderConfigs() (map[string]json.RawMessage, error) {
+	configs := make(map[string]json.RawMessage, len(cfg.Loaders))
+	for name, settings := range cfg.Loaders {
+		raw, err := json.Marshal(settings)
+		if err != nil {
+			return nil, fmt.Errorf("invalid settings of the loader '%s': %w", name, err)
+		}
+		configs[name] = raw
+	}
+	return configs, nil
+}
+
+// StaticScanner returns a scanner of the configured devices (nil if there are none)
+func (cfg config) StaticScanner() discovery.Scanner {
+	if len(cfg.Devices) == 0 {
+		return nil
+	}
+	devices := make([]*chromecast.Device, 0, len(cfg.Devices))
+	for _, cd := range cfg.Devices {
+		devices = append(devices, cd.device())
+	}
+	return discovery.Static(devices...)
+}
+
+// loadedConfig is the config read by applyDefaults
+var loadedConfig config
+
+// applyDefaults reads the config file and sets the flags which were not given,
+// from the config or else from the environment variables
+func applyDefaults() error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	loadedConfig = cfg
+
+	flags := rootCmd.PersistentFlags()
+	if !flags.Changed("timeout") {
+		value := cfg.Timeout
+		if value == "" {
+			value = os.Getenv("CHROMECAST_TIMEOUT")
+		}
+		if value != "" {
+			if err := flags.Set("timeout", value); err != nil {
+				return fmt.Errorf("invalid default for --timeout: %w", err)
+			}
+		}
+	}
+
+	// the device given by a flag, else the default device
+	var legacy []string
+	for _, name := range []string{"name", "id", "uuid", "ip"} {
+		if flags.Changed(name) {
+			legacy = append(legacy, "--"+name)
+		}
+	}
+	selector := deviceSelector
+	if flags.Changed("device") {
+		if len(legacy) > 0 {
+			return fmt.Errorf("--device cannot be combined with %s", strings.Join(legacy, ", "))
+		}
+	} else if len(legacy) == 0 {
+		selector = cfg.DefaultDevice
+		if selector == "" {
+			selector = os.Getenv("CHROMECAST_DEVICE")
+		}
+	}
+	if selector == "" {
+		return nil
+	}
+	if err := deviceFinder.Select(selector); err != nil {
+		return fmt.Errorf("invalid device: %w", err)
+	}
+	return nil
+}
+
+// volumeStepSetting returns the step of the volume changes (0 for the step of the device):
+// --volume-step, else the volume_step of the connected device or of the config, else CHROMECAST_VOLUME_STEP
+func volumeStepSetting() float64 {
+	if rootCmd.PersistentFlags().Changed("volume-step") {
+		return volumeStep
+	}
+	if connectedDevice != nil {
+		for _, cd := range loadedConfig.Devices {
+			if cd.VolumeStep > 0 && strings.EqualFold(cd.Name, connectedDevice.Name()) {
+				return cd.VolumeStep
+			}
+		}
+	}
+	if loadedConfig.VolumeStep > 0 {
+		return loadedConfig.VolumeStep
+	}
+	step, _ := strconv.ParseFloat(os.Getenv("CHROMECAST_VOLUME_STEP"), 64)
+	return step
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chromecast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.toml")
+	err = ioutil.WriteFile(path, []byte(`default_device = "kitchen"
+timeout = "5s"
+volume_step = 0.02
+
+[[devices]]
+name = "kitchen"
+ip = "192.168.1.20"
+volume_step = 0.1
+
+[loaders.vimeo]
+access_token = "secret"
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(previous string) { configFile = previous }(configFile)
+	configFile = path
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.DefaultDevice != "kitchen" || cfg.Timeout != "5s" || cfg.VolumeStep != 0.02 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if len(cfg.Devices) != 1 || cfg.Devices[0].IP.String() != "192.168.1.20" || cfg.Devices[0].device().Port != 8009 {
+		t.Errorf("unexpected devices: %+v", cfg.Devices)
+	}
+	loaders, err := cfg.LoaderConfigs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(loaders["vimeo"]) != `{"access_token":"secret"}` {
+		t.Errorf("unexpected loader settings: %s", loaders["vimeo"])
+	}
+
+	configFile = filepath.Join(dir, "missing.toml")
+	if _, err := loadConfig(); err != nil {
+		t.Errorf("a missing config file should be ignored, got %v", err)
+	}
+}
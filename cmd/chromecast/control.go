@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -15,6 +16,7 @@ import (
 	"github.com/oliverpool/go-chromecast/cli/local"
 	"github.com/oliverpool/go-chromecast/command"
 	"github.com/oliverpool/go-chromecast/command/media"
+	"github.com/oliverpool/go-chromecast/command/volume"
 	"github.com/spf13/cobra"
 )
 
@@ -33,10 +35,22 @@ type Progress interface {
 	Close() error
 }
 
+var scaleMembers bool
+
 func init() {
+	controlCmd.Flags().BoolVar(&scaleMembers, "scale-members", false, "Adjust proportionally the volume of each member of a cast group")
 	rootCmd.AddCommand(controlCmd)
 }
 
+// volumeOptions returns the options of the volume changes
+func volumeOptions() []volume.Option {
+	opts := []volume.Option{volume.WithStep(volumeStepSetting())}
+	if scaleMembers {
+		opts = append(opts, volume.ScaleMembers)
+	}
+	return opts
+}
+
 var controlCmd = &cobra.Command{
 	Use:   "control",
 	Short: "Control a chromecast",
@@ -91,6 +105,9 @@ func remote(
 		return fmt.Errorf("could not get a media app: %w", err)
 	}
 
+	events := app.Events()
+	defer app.Close()
+
 	lstatus := local.New(status)
 	// lstatus.UpdateMedia(app.LatestStatus()[0])
 
@@ -119,13 +136,14 @@ func remote(
 			lstatus,
 			logger,
 			command.Launcher{Requester: client}.AmpController(),
+			client,
 		)
 	}()
 
 	// Get loaded item
 	fmt.Print("Waiting for a loaded item...")
 	appStatus := app.LatestStatus()
-	for len(appStatus) == 0 || appStatus[0].Item == nil || appStatus[0].Item.Duration.Seconds() == 0 {
+	for len(appStatus) == 0 || appStatus[0].Item == nil || (appStatus[0].Item.Duration.Seconds() == 0 && !appStatus[0].IsLive()) {
 		select {
 		case <-clientCtx.Done():
 			return fmt.Errorf("interrupted: %v", clientCtx.Err())
@@ -147,9 +165,14 @@ func remote(
 	*session = *cs
 	fmt.Println(" OK")
 
-	fmt.Println("\n Play/Pause: <space>  Seek: ←/→  Volume: ↑/↓/m  Stop: s  Quit: q  Disconnect: <Esc>")
+	fmt.Println("\n Play/Pause: <space>  Seek: ←/→  Volume: ↑/↓/m  Speed: </>  Next/Previous: n/p  Repeat: r  Live edge: l  Stop: s  Quit app: q  Disconnect: <Esc>")
 
-	total := int(appStatus[0].Item.Duration.Seconds())
+	// the statuses received while waiting are outdated
+	for len(events) > 0 {
+		<-events
+	}
+	lstatus.UpdateMedia(appStatus[0])
+	total := lstatus.Total()
 
 	bar := uiprogress.AddBar(total)
 	bar.Width = 40
@@ -158,20 +181,83 @@ func remote(
 	bar.PrependFunc(func(b *uiprogress.Bar) string {
 		return lstatus.PlayerState()
 	})
+	var linkWarning, stateWarning atomic.Value
+	linkWarning.Store("")
+	stateWarning.Store("")
 	bar.AppendFunc(func(b *uiprogress.Bar) string {
-		return lstatus.TimeStatus()
+		if warning := stateWarning.Load().(string); warning != "" {
+			return lstatus.TimeStatus() + warning
+		}
+		return lstatus.TimeStatus() + linkWarning.Load().(string)
 	})
+	if pinger, ok := client.(chromecast.Pinger); ok {
+		go watchLatency(clientCtx, pinger, func(warning string) {
+			linkWarning.Store(warning)
+		})
+	}
+	if notifier, ok := client.(chromecast.StateNotifier); ok {
+		go func() {
+			for state := range notifier.State() {
+				switch state {
+				case chromecast.Connecting, chromecast.Reconnecting:
+					stateWarning.Store("  ⚠ " + state.String() + "…")
+				default:
+					stateWarning.Store("")
+				}
+			}
+		}()
+	}
+
+	if subscriber, ok := client.(chromecast.Subscriber); ok {
+		// volume changed with the remote for instance
+		statuses, stop := command.SubscribeStatus(subscriber)
+		defer stop()
+		go func() {
+			for st := range statuses {
+				lstatus.UpdateReceiver(st)
+			}
+		}()
+	}
 
 	atomic.StoreUint32(&sessionFound, 1)
 
 	go func() {
+		st, err := session.WaitFor(clientCtx, media.Ended)
+		if err != nil {
+			return
+		}
+		uiprogress.Stop()
+		if st.Errored() {
+			fmt.Println("playback stopped on an error")
+		} else {
+			fmt.Println("playback finished")
+		}
+		cancel()
+	}()
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
 		for {
-			app.Status()
-			if len(app.LatestStatus()) > 0 {
-				elapsed := lstatus.UpdateMedia(app.LatestStatus()[0])
-				bar.Set(elapsed)
+			var elapsed int
+			select {
+			case <-clientCtx.Done():
+				return
+			case <-ticker.C:
+				// between two statuses
+				elapsed = lstatus.Elapse(time.Second)
+			case st, ok := <-events:
+				if !ok {
+					return
+				}
+				elapsed = lstatus.UpdateMedia(st)
 			}
-			time.Sleep(1000 * time.Millisecond)
+			if t := lstatus.Total(); t != total {
+				// the window of a live stream moves
+				total = t
+				bar.Total = t
+			}
+			bar.Set(elapsed)
 		}
 	}()
 
@@ -180,18 +266,29 @@ func remote(
 	return nil
 }
 
-func processKeyInputs(ch chan cli.KeyPress, hasSession func() bool, session *media.Session, lstatus *local.Status, logger chromecast.Logger, amp chromecast.AmpController) {
+func processKeyInputs(ch chan cli.KeyPress, hasSession func() bool, session *media.Session, lstatus *local.Status, logger chromecast.Logger, amp chromecast.AmpController, requester chromecast.Requester) {
 
 	forwardFactor := newStreakFactor()
 	backwardFactor := newStreakFactor()
 
+	// the relative seeks are sent one after the other, without blocking the key inputs
+	seeks := make(chan time.Duration, 10)
+	defer close(seeks)
+	go func() {
+		for diff := range seeks {
+			if err := session.SeekBy(diff); err != nil {
+				fmt.Println("could not seek:", err)
+			}
+		}
+	}()
+
 	for c := range ch {
 		switch {
 		case c.Type == cli.Escape:
 			if hasSession() {
 				uiprogress.Stop()
-				fmt.Println("bye")
 			}
+			fmt.Println("disconnected (the app keeps running)")
 			return
 		case c.Type == cli.SpaceBar && hasSession():
 			if lstatus.TogglePlay() {
@@ -214,32 +311,66 @@ func processKeyInputs(ch chan cli.KeyPress, hasSession func() bool, session *med
 				if hasSession() {
 					uiprogress.Stop()
 				}
-				fmt.Println("quit")
-				amp.Quit()
+				if err := amp.Quit(); err != nil {
+					fmt.Println("could not quit the app:", err)
+				} else {
+					fmt.Println("app stopped (back to the backdrop)")
+				}
 				return
 			case 'm':
 				amp.Mute(lstatus.ToggleMute())
+			case 'n':
+				if hasSession() {
+					session.Next()
+				}
+			case 'p':
+				if hasSession() {
+					session.Previous()
+				}
+			case 'r':
+				if hasSession() {
+					session.SetRepeatMode(lstatus.NextRepeatMode())
+				}
+			case 'l':
+				if hasSession() {
+					session.SeekToLiveEdge()
+				}
 			default:
 				logger.Log("msg", "unsupported lowercase", "key", string(c.Key), "type", c.Type)
 			}
+		case c.Type == cli.Symbol && hasSession():
+			switch c.Key {
+			case '<':
+				session.SetPlaybackRate(lstatus.IncrRate(-.25))
+			case '>':
+				session.SetPlaybackRate(lstatus.IncrRate(.25))
+			default:
+				logger.Log("msg", "unsupported symbol", "key", string(c.Key), "type", c.Type)
+			}
 		case c.Type == cli.Arrow:
 			switch c.Key {
 			case cli.Up:
-				amp.SetVolume(lstatus.IncrVolume(.1))
+				if st, err := volume.Up(requester, volumeOptions()...); err == nil {
+					lstatus.UpdateReceiver(st)
+				}
 			case cli.Down:
-				amp.SetVolume(lstatus.IncrVolume(-.1))
+				if st, err := volume.Down(requester, volumeOptions()...); err == nil {
+					lstatus.UpdateReceiver(st)
+				}
 			case cli.Left:
 				if !hasSession() {
 					continue
 				}
 				diff := -time.Duration(backwardFactor()) * 5 * time.Second
-				session.Seek(media.Seek(lstatus.SeekBy(diff)))
+				lstatus.SeekBy(diff)
+				seeks <- diff
 			case cli.Right:
 				if !hasSession() {
 					continue
 				}
 				diff := time.Duration(forwardFactor()) * 10 * time.Second
-				session.Seek(media.Seek(lstatus.SeekBy(diff)))
+				lstatus.SeekBy(diff)
+				seeks <- diff
 			default:
 				logger.Log("msg", "unsupported arrow", "key", c.Key, "type", c.Type)
 			}
@@ -253,12 +384,11 @@ func getMediaApp(client chromecast.Client, status chromecast.Status) (app *media
 	fmt.Print("\nWaiting for a media app...")
 	for {
 		app, err = media.ConnectFromStatus(client, status)
-		switch err {
-		case nil:
+		switch {
+		case err == nil:
 			fmt.Println(" OK")
-			go app.UpdateStatus()
 			return app, nil
-		case chromecast.ErrAppNotFound:
+		case errors.Is(err, chromecast.ErrAppNotFound):
 			time.Sleep(time.Second)
 			fmt.Print(".")
 			status, err = command.Launcher{Requester: client}.Status()
@@ -270,3 +400,30 @@ func getMediaApp(client chromecast.Client, status chromecast.Status) (app *media
 		}
 	}
 }
+
+// degradedLatency above which the link is considered degraded
+const degradedLatency = 500 * time.Millisecond
+
+// watchLatency pings the chromecast every 5s and reports a warning when the link is degraded (empty otherwise)
+func watchLatency(ctx context.Context, pinger chromecast.Pinger, report func(warning string)) {
+	for {
+		pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		rtt, err := pinger.Ping(pingCtx)
+		cancel()
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			report("  ⚠ no answer from the chromecast")
+		case rtt > degradedLatency:
+			report(fmt.Sprintf("  ⚠ slow link (%s)", rtt.Round(time.Millisecond)))
+		default:
+			report("")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
@@ -4,28 +4,74 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	chromecast "github.com/oliverpool/go-chromecast"
 	"github.com/oliverpool/go-chromecast/discovery"
+	"github.com/oliverpool/go-chromecast/discovery/probe"
+	"github.com/oliverpool/go-chromecast/discovery/ssdp"
 	"github.com/oliverpool/go-chromecast/discovery/zeroconf"
+	"github.com/spf13/cobra"
 )
 
+var deviceSelector string
+
 func init() {
+	rootCmd.PersistentFlags().StringVar(&deviceSelector, "device", "", `Select the chromecast: name:"Living Room", uuid:abcd, ip:192.168.1.4:8009 (or without prefix, guessed from the value)`)
 	rootCmd.PersistentFlags().IPVar(&deviceFinder.IP, "ip", nil, "Specify chromecast IP")
 	rootCmd.PersistentFlags().IntVar(&deviceFinder.Port, "port", 8009, "Specify chromecast port (ignored if IP is not set)")
-	rootCmd.PersistentFlags().StringVarP(&deviceFinder.Name, "name", "n", "", "Specify chromecast name (ignored if IP is set)")
+	rootCmd.PersistentFlags().StringVarP(&deviceFinder.Name, "name", "n", "", "Specify chromecast name, case-insensitive part of it is enough (ignored if IP is set)")
+	cobra.MarkFlagCustom(rootCmd.PersistentFlags(), "name", "__chromecast_names") // see bashCompleteNames
+	rootCmd.PersistentFlags().BoolVar(&deviceFinder.ExactName, "exact-name", false, "Require the chromecast name to match exactly")
 	rootCmd.PersistentFlags().StringVar(&deviceFinder.ID, "id", "", "Specify chromecast ID (ignored if IP is set)")
+	rootCmd.PersistentFlags().StringVar(&deviceFinder.ID, "uuid", "", "Specify chromecast UUID, with or without dashes (alias of --id)")
+	rootCmd.PersistentFlags().StringSliceVar(&deviceFinder.Subnets, "subnet", nil, "Only consider the chromecasts within the subnet(s) (like 192.168.1.0/24)")
+	rootCmd.PersistentFlags().StringSliceVar(&deviceFinder.Interfaces, "interface", nil, "Network interface(s) to scan on (all if not set)")
+	rootCmd.PersistentFlags().StringVar(&deviceFinder.CacheFile, "cache", defaultCacheFile(), "File to remember the discovered chromecasts (empty to disable)")
+	rootCmd.PersistentFlags().BoolVar(&deviceFinder.Probe, "probe", false, "Probe the local network(s) for chromecasts if multicast discovery finds nothing (slow)")
+	rootCmd.PersistentFlags().BoolVar(&deviceFinder.PinCert, "pin-cert", false, "Remember the certificate of each chromecast and refuse to connect if it changes (next to the cache file)")
+}
+
+func defaultCacheFile() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "go-chromecast", "devices.json")
 }
 
 type deviceFinderConstraints struct {
-	Name string
-	ID   string
-	IP   net.IP
-	Port int
+	Name      string
+	ExactName bool
+	ID        string
+	IP        net.IP
+	Port      int
+
+	Subnets    []string
+	Interfaces []string
+	CacheFile  string
+	Probe      bool
+	PinCert    bool
 }
 
 var deviceFinder deviceFinderConstraints
 
+// Select sets the constraints of the device selector (see discovery.ParseSelector)
+func (df *deviceFinderConstraints) Select(selector string) error {
+	sel, err := discovery.ParseSelector(selector)
+	if err != nil {
+		return err
+	}
+	df.Name, df.ID, df.IP = sel.Name, sel.ID, sel.IP
+	if sel.Port > 0 {
+		df.Port = sel.Port
+	}
+	return nil
+}
+
 func (df deviceFinderConstraints) GetDevice(ctx context.Context, logger chromecast.Logger) (*chromecast.Device, error) {
 	// If IP is set, return device with corresponding IP
 	if df.IP != nil {
@@ -35,14 +81,127 @@ func (df deviceFinderConstraints) GetDevice(ctx context.Context, logger chromeca
 	// Otherwise search with matchers
 	var matchers []discovery.DeviceMatcher
 	if df.Name != "" {
-		matchers = append(matchers, discovery.WithName(df.Name))
+		if df.ExactName {
+			matchers = append(matchers, discovery.WithName(df.Name))
+		} else {
+			matchers = append(matchers, discovery.WithFuzzyName(df.Name))
+		}
 	}
 	if df.ID != "" {
 		matchers = append(matchers, discovery.WithID(df.ID))
 	}
-	chr, err := discovery.Service{Scanner: zeroconf.Scanner{Logger: logger}}.First(ctx, matchers...)
+	subnet, err := df.SubnetMatcher()
+	if err != nil {
+		return nil, err
+	}
+	if subnet != nil {
+		matchers = append(matchers, subnet)
+	}
+
+	// Configured devices don't require any network traffic
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if static := cfg.StaticScanner(); static != nil {
+		chr, err := discovery.Service{Scanner: static}.First(ctx, matchers...)
+		if err == nil {
+			return chr, nil
+		}
+		logger.Log("step", "config", "msg", "no configured device matched", "err", err)
+	}
+
+	errs := make(chan error, 10)
+	scanner, err := df.Scanner(logger, errs)
+	if err != nil {
+		return nil, err
+	}
+	if df.CacheFile != "" {
+		scanner = discovery.Cache{
+			Scanner: scanner,
+			Store:   discovery.FileStore{Path: df.CacheFile},
+			Logger:  logger,
+		}
+	}
+	chr, err := discovery.Service{Scanner: scanner}.First(ctx, matchers...)
 	if err != nil || chr == nil {
-		return nil, fmt.Errorf("could not find a device: %w", err)
+		return nil, fmt.Errorf("could not find a device: %w%s", err, scanErrorsHint(errs))
 	}
 	return chr, nil
 }
+
+// SubnetMatcher returns a matcher for the requested subnets (nil if none were requested)
+func (df deviceFinderConstraints) SubnetMatcher() (discovery.DeviceMatcher, error) {
+	if len(df.Subnets) == 0 {
+		return nil, nil
+	}
+	networks, err := df.networks()
+	if err != nil {
+		return nil, err
+	}
+	return discovery.WithinNetworks(networks...), nil
+}
+
+func (df deviceFinderConstraints) networks() ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(df.Subnets))
+	for _, cidr := range df.Subnets {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subnet: %w", err)
+		}
+		networks = append(networks, n)
+	}
+	return networks, nil
+}
+
+// Scanner returns a scanner (mDNS and SSDP) restricted to the requested interfaces
+// If Probe is set, the requested subnets (or the local networks) are probed when nothing is found
+// The scan errors are reported on errs (see scanErrorsHint)
+func (df deviceFinderConstraints) Scanner(logger chromecast.Logger, errs chan<- error) (discovery.Scanner, error) {
+	ifaces := make([]net.Interface, 0, len(df.Interfaces))
+	for _, name := range df.Interfaces {
+		iface, err := net.InterfaceByName(name)
+		if err != nil {
+			return nil, fmt.Errorf("unknown interface '%s': %w", name, err)
+		}
+		ifaces = append(ifaces, *iface)
+	}
+	scanner := discovery.Multi(
+		zeroconf.Scanner{Logger: logger, Interfaces: ifaces, Errors: errs},
+		ssdp.Scanner{Logger: logger, Interfaces: ifaces, Errors: errs},
+	)
+	if !df.Probe {
+		return scanner, nil
+	}
+	networks, err := df.networks()
+	if err != nil {
+		return nil, err
+	}
+	return discovery.Fallback(scanner, probe.Scanner{
+		Networks: networks,
+		Logger:   logger,
+		Errors:   errs,
+	}, 2*time.Second), nil
+}
+
+// scanErrorsHint returns the (deduplicated) reported errors, to help understand why no device was found
+func scanErrorsHint(errs <-chan error) string {
+	var msgs []string
+	seen := make(map[string]bool)
+	for {
+		select {
+		case err := <-errs:
+			if !seen[err.Error()] {
+				seen[err.Error()] = true
+				msgs = append(msgs, err.Error())
+			}
+			continue
+		default:
+		}
+		break
+	}
+	if len(msgs) == 0 {
+		return ""
+	}
+	return "\nmulticast might be blocked on this network (try --ip or --probe):\n - " + strings.Join(msgs, "\n - ")
+}
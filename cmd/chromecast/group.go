@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/multizone"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	groupCmd.AddCommand(groupVolumeCmd)
+	rootCmd.AddCommand(groupCmd)
+}
+
+var groupCmd = &cobra.Command{
+	Use:   "group",
+	Short: "Print the members of the cast group (speaker group) and their volume",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger, ctx, cancel := flags()
+		defer cancel()
+
+		client, _, err := GetClientWithStatus(ctx, logger)
+		if err != nil {
+			return fmt.Errorf("could not get a client: %w", err)
+		}
+		defer client.Close()
+
+		st, err := multizone.Controller{Requester: client}.StatusCtx(ctx)
+		if err != nil {
+			return fmt.Errorf("could not get the group status: %w", err)
+		}
+		fmt.Println()
+		for _, m := range st.Devices {
+			fmt.Printf("  %s (%s): %s\n", m.Name, m.ID, volumeString(m.Volume))
+		}
+		return nil
+	},
+}
+
+var groupVolumeCmd = &cobra.Command{
+	Use:   "volume <member> <level>",
+	Short: "Set the volume of a member (ID or case-insensitive part of its name) between 0 and 1",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		level, err := strconv.ParseFloat(args[1], 64)
+		if err != nil || level < 0 || level > 1 {
+			return fmt.Errorf("the level must be between 0 and 1, got %s", args[1])
+		}
+
+		logger, ctx, cancel := flags()
+		defer cancel()
+
+		client, _, err := GetClientWithStatus(ctx, logger)
+		if err != nil {
+			return fmt.Errorf("could not get a client: %w", err)
+		}
+		defer client.Close()
+
+		controller := multizone.Controller{Requester: client}
+		st, err := controller.StatusCtx(ctx)
+		if err != nil {
+			return fmt.Errorf("could not get the group status: %w", err)
+		}
+		member, err := findMember(st.Devices, args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Print("Setting the volume of " + member.Name + "...")
+		updated, err := controller.SetMemberVolumeCtx(ctx, member.ID, chromecast.Volume{Level: &level})
+		if err != nil {
+			return fmt.Errorf("could not set the volume: %w", err)
+		}
+		fmt.Println(" " + volumeString(updated.Volume))
+		return nil
+	},
+}
+
+// findMember returns the member with the ID or whose name contains the query (case-insensitive)
+func findMember(members []multizone.Member, query string) (multizone.Member, error) {
+	for _, m := range members {
+		if m.ID == query {
+			return m, nil
+		}
+	}
+	for _, m := range members {
+		if strings.Contains(strings.ToLower(m.Name), strings.ToLower(query)) {
+			return m, nil
+		}
+	}
+	return multizone.Member{}, fmt.Errorf("no member matching %q in the group", query)
+}
+
+func volumeString(vol *chromecast.Volume) string {
+	if vol == nil || vol.Level == nil {
+		return "unknown volume"
+	}
+	s := fmt.Sprintf("%.0f%%", *vol.Level*100)
+	if vol.Muted != nil && *vol.Muted {
+		s += " (muted)"
+	}
+	return s
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command"
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+// jsonStdout receives the JSON output (in JSON mode, the human-readable text is printed to stderr)
+var jsonStdout io.Writer = os.Stdout
+
+// printJSON prints v as one line of JSON
+func printJSON(v interface{}) error {
+	return json.NewEncoder(jsonStdout).Encode(v)
+}
+
+// jsonState is printed in JSON mode by the commands interacting with a chromecast
+type jsonState struct {
+	Device   *jsonDevice        `json:"device,omitempty"`
+	Receiver *chromecast.Status `json:"receiver,omitempty"`
+	// Loader of the media (by the load command)
+	Loader string `json:"loader,omitempty"`
+	// LatencyMs of the connection (by the status command)
+	LatencyMs int64          `json:"latencyMs,omitempty"`
+	Media     []media.Status `json:"media"`
+}
+
+// newJSONState returns the state of the connected device, of its receiver and of its media app (if any)
+func newJSONState(client chromecast.Client) (jsonState, error) {
+	state := jsonState{
+		Media: []media.Status{},
+	}
+	if connectedDevice != nil {
+		d := newJSONDevice(connectedDevice)
+		state.Device = &d
+	}
+	receiver, err := command.Launcher{Requester: client}.Status()
+	if err != nil {
+		return state, fmt.Errorf("could not get the receiver status: %w", err)
+	}
+	state.Receiver = &receiver
+
+	app, err := media.ConnectFromStatus(client, receiver)
+	if errors.Is(err, chromecast.ErrAppNotFound) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("could not connect to the media app: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), command.DefaultTimeout)
+	defer cancel()
+	st, err := app.StatusCtx(ctx)
+	if err != nil {
+		return state, fmt.Errorf("could not get the media status: %w", err)
+	}
+	state.Media = append(state.Media, st...)
+	return state, nil
+}
+
+// printLoadedJSON prints the state after the media was loaded by the loader (in JSON mode)
+func printLoadedJSON(client chromecast.Client, loader string) error {
+	if !jsonOutput {
+		return nil
+	}
+	state, err := newJSONState(client)
+	if err != nil {
+		return err
+	}
+	state.Loader = loader
+	return printJSON(state)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/oliverpool/go-chromecast/command"
+	"github.com/spf13/cobra"
+)
+
+var launchCustomData string
+
+func init() {
+	launchCmd.Flags().StringVar(&launchCustomData, "custom-data", "", "JSON customData to send to the app")
+	rootCmd.AddCommand(launchCmd)
+}
+
+var launchCmd = &cobra.Command{
+	Use:   "launch <appID>",
+	Short: "Launch an app (like a custom receiver) on the chromecast",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var customData interface{}
+		if launchCustomData != "" {
+			if err := json.Unmarshal([]byte(launchCustomData), &customData); err != nil {
+				return fmt.Errorf("could not parse the custom data: %w", err)
+			}
+		}
+
+		logger, ctx, cancel := flags()
+		defer cancel()
+
+		client, _, err := GetClientWithStatus(ctx, logger)
+		if err != nil {
+			return fmt.Errorf("could not get a client: %w", err)
+		}
+		defer client.Close()
+
+		fmt.Print("Launching " + args[0] + "...")
+		app, err := command.Launcher{Requester: client}.LaunchAppCtx(ctx, args[0], customData)
+		if err != nil {
+			return fmt.Errorf("could not launch the app: %w", err)
+		}
+		fmt.Println(" OK")
+
+		if app.DisplayName != nil {
+			fmt.Printf("  Name: %s\n", *app.DisplayName)
+		}
+		if app.SessionID != nil {
+			fmt.Printf("  Session: %s\n", *app.SessionID)
+		}
+		if app.TransportId != nil {
+			fmt.Printf("  Transport: %s\n", *app.TransportId)
+		}
+		for _, ns := range app.Namespaces {
+			fmt.Printf("  Namespace: %s\n", ns.Name)
+		}
+		return nil
+	},
+}
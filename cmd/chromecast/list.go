@@ -2,9 +2,12 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"time"
 
 	chromecast "github.com/oliverpool/go-chromecast"
-	"github.com/oliverpool/go-chromecast/discovery/zeroconf"
+	"github.com/oliverpool/go-chromecast/discovery"
+	"github.com/oliverpool/go-chromecast/setupapi"
 	"github.com/spf13/cobra"
 )
 
@@ -12,28 +15,81 @@ func init() {
 	rootCmd.AddCommand(listCmd)
 }
 
+type jsonDevice struct {
+	Name       string                 `json:"name"`
+	UUID       string                 `json:"uuid"`
+	IP         string                 `json:"ip"`
+	Port       int                    `json:"port"`
+	Model      string                 `json:"model"`
+	Status     string                 `json:"status"`
+	Properties map[string]string      `json:"properties"`
+	Info       *chromecast.DeviceInfo `json:"info,omitempty"`
+}
+
+func newJSONDevice(d *chromecast.Device) jsonDevice {
+	ip := d.IP.String()
+	if d.Zone != "" {
+		ip += "%" + d.Zone
+	}
+	return jsonDevice{
+		Name:       d.Name(),
+		UUID:       d.ID(),
+		IP:         ip,
+		Port:       d.Port,
+		Model:      d.Type(),
+		Status:     d.Status(),
+		Properties: d.Properties,
+		Info:       d.Info,
+	}
+}
+
 var listCmd = &cobra.Command{
 	Use:   "list",
-	Short: "Print all the chromecast found in the network",
+	Short: "Print all the chromecast found in the network (until the timeout)",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger, ctx, cancel := flags()
 		defer cancel()
 
-		scanner := zeroconf.Scanner{Logger: logger}
-		devices := make(chan *chromecast.Device, 5)
-		seen := make(map[string]bool)
+		errs := make(chan error, 10)
+		scanner, err := deviceFinder.Scanner(logger, errs)
+		if err != nil {
+			return err
+		}
+		if verbose {
+			scanner = setupapi.Scanner{Scanner: scanner, Logger: logger}
+		}
+		var matchers []discovery.DeviceMatcher
+		subnet, err := deviceFinder.SubnetMatcher()
+		if err != nil {
+			return err
+		}
+		if subnet != nil {
+			matchers = append(matchers, subnet)
+		}
 
-		if err := scanner.Scan(ctx, devices); err != nil {
-			return fmt.Errorf("could not start scanner: %w", err)
+		devices, err := discovery.Service{Scanner: scanner}.All(ctx, matchers...)
+		if err != nil {
+			return fmt.Errorf("could not scan: %w", err)
 		}
 
-		for d := range devices {
-			if seen[d.ID()] {
+		if len(devices) == 0 {
+			fmt.Fprintln(os.Stderr, "no chromecast found"+scanErrorsHint(errs))
+		}
+
+		for _, d := range devices {
+			if jsonOutput {
+				// one JSON object per chromecast
+				if err := printJSON(newJSONDevice(d)); err != nil {
+					return err
+				}
 				continue
 			}
-			seen[d.ID()] = true
 			fmt.Printf("- %s [addr=%s; uuid=%s; type=%s; status=%s]\n",
 				d.Name(), d.Addr(), d.ID(), d.Type(), d.Status())
+			if d.Info != nil {
+				fmt.Printf("  build=%s; uptime=%s; ssid=%s; locale=%s; mac=%s\n",
+					d.Info.CastBuildRevision, time.Duration(d.Info.Uptime)*time.Second, d.Info.SSID, d.Info.Locale, d.Info.MacAddress)
+			}
 		}
 		return nil
 	},
@@ -1,65 +1,167 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/oliverpool/go-chromecast"
 
+	"github.com/oliverpool/go-chromecast/command"
 	"github.com/oliverpool/go-chromecast/command/media"
-	"github.com/oliverpool/go-chromecast/command/media/defaultreceiver"
-	"github.com/oliverpool/go-chromecast/command/media/defaultreceiver/tatort"
-	"github.com/oliverpool/go-chromecast/command/media/defaultreceiver/tvnow"
-	defaultvimeo "github.com/oliverpool/go-chromecast/command/media/defaultreceiver/vimeo"
-	"github.com/oliverpool/go-chromecast/command/media/vimeo"
-	"github.com/oliverpool/go-chromecast/command/media/youtube"
-	"github.com/oliverpool/go-chromecast/command/urlreceiver"
+	_ "github.com/oliverpool/go-chromecast/command/media/defaultreceiver"
+	_ "github.com/oliverpool/go-chromecast/command/media/defaultreceiver/jellyfin"
+	_ "github.com/oliverpool/go-chromecast/command/media/defaultreceiver/soundcloud"
+	_ "github.com/oliverpool/go-chromecast/command/media/defaultreceiver/tatort"
+	_ "github.com/oliverpool/go-chromecast/command/media/defaultreceiver/tvnow"
+	_ "github.com/oliverpool/go-chromecast/command/media/defaultreceiver/twitch"
+	_ "github.com/oliverpool/go-chromecast/command/media/defaultreceiver/vimeo"
+	_ "github.com/oliverpool/go-chromecast/command/media/defaultreceiver/ytdlp"
+	_ "github.com/oliverpool/go-chromecast/command/media/plex"
+	_ "github.com/oliverpool/go-chromecast/command/media/styledreceiver"
+	_ "github.com/oliverpool/go-chromecast/command/media/vimeo"
+	_ "github.com/oliverpool/go-chromecast/command/media/youtube"
+	_ "github.com/oliverpool/go-chromecast/command/urlreceiver"
+	"github.com/oliverpool/go-chromecast/localmedia"
 	"github.com/spf13/cobra"
 )
 
 var loadRequestTimeout time.Duration
 
 var useLoader string
-var loaders = []namedLoader{
-	{"tatort", tatort.URLLoader},
-	{"tvnow", tvnow.URLLoader},
-	{"vimeo", vimeo.URLLoader},
-	{"youtube", youtube.URLLoader},
-	{"default.vimeo", defaultvimeo.URLLoader},
-	{"default", defaultreceiver.URLLoader},
-	{"urlreceiver", urlreceiver.URLLoader},
-}
 
 var controlAfterwards bool
+var loadTitle string
+var loadImage string
+var loadLive bool
+var loadLicenseURL string
+var subtitleScale float64
+var loadRetries int
+var concurrentProbe bool
+var dryRun bool
+var followRedirects bool
 
-type namedLoader struct {
-	name   string
-	loader media.URLLoader
+// loadOptions returns the options of the loaded item
+func loadOptions() []media.Option {
+	var options []media.Option
+	if loadRetries > 0 {
+		options = append(options, media.Retry(media.RetryPolicy{Retries: loadRetries, Backoff: time.Second}))
+	}
+	if loadLive {
+		options = append(options, media.Live)
+	}
+	if subtitleScale > 0 {
+		style := media.DefaultTextTrackStyle()
+		style.FontScale = subtitleScale
+		options = append(options, media.WithTextTrackStyle(style))
+	}
+	if loadLicenseURL != "" {
+		options = append(options, media.WithDRM(media.DRM{LicenseURL: loadLicenseURL}))
+	}
+	if loadTitle == "" && loadImage == "" {
+		return options
+	}
+	metadata := media.GenericMediaMetadata{Title: loadTitle}
+	if loadImage != "" {
+		metadata.Images = []media.Image{{URL: loadImage}}
+	}
+	return append(options, media.Metadata(metadata))
 }
 
-func (nl namedLoader) load(client chromecast.Client, status chromecast.Status, rawurl string) (<-chan []byte, error) {
-	loader, err := nl.loader(rawurl)
+// load the URL with one of the registered loaders (see media.RegisterLoader)
+func load(l media.NamedLoader, client chromecast.Client, status chromecast.Status, rawurl string, options ...media.Option) (<-chan []byte, error) {
+	loader, err := l.Loader(rawurl, options...)
 	if err != nil {
 		return nil, err
 	}
 	return loader(client, status)
 }
 
+// jsonCandidate is printed for each candidate loader by --dry-run (in JSON mode)
+type jsonCandidate struct {
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+}
+
+// printCandidates prints the loaders which would be tried (in this order)
+func printCandidates(rawurl string) error {
+	if localmedia.IsLocal(rawurl) {
+		if jsonOutput {
+			return printJSON(jsonCandidate{Name: "local"})
+		}
+		fmt.Println("local file (served on the default receiver)")
+		return nil
+	}
+	candidates := media.Candidates(rawurl)
+	if useLoader != "" {
+		for _, l := range media.Loaders() {
+			if l.Name == useLoader {
+				candidates = []media.NamedLoader{l}
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("no supported loader found for %s", rawurl)
+	}
+	for i, l := range candidates {
+		if jsonOutput {
+			if err := printJSON(jsonCandidate{Name: l.Name, Priority: l.Priority}); err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Printf("%d. %s (priority %d)\n", i+1, l.Name, l.Priority)
+	}
+	return nil
+}
+
+// awaitLoad waits for the reply to the load request.
+// If the chromecast failed to load the media, fallback is true if allowed (to try the next loader)
+// or the error is returned.
+func awaitLoad(logger chromecast.Logger, name string, c <-chan []byte, allowFallback bool) (fallback bool, err error) {
+	select {
+	case payload := <-c:
+		var loadErr chromecast.LoadFailedError
+		if err := command.ResponseError(payload); errors.As(err, &loadErr) {
+			if !allowFallback {
+				return false, err
+			}
+			logger.Log("loader", name, "state", "loaded", "err", err)
+			fmt.Printf("Loading with %s failed: %v\n", name, err)
+			return true, nil
+		}
+	case <-time.After(loadRequestTimeout):
+		logger.Log("loader", name, "err", "load request didn't return after 10s")
+	}
+	return false, nil
+}
+
 func init() {
 	loadCmd.Flags().DurationVarP(&loadRequestTimeout, "request-timeout", "r", 10*time.Second, "Duration to wait for a reply to the load request")
 	var ll []string
-	for _, l := range loaders {
-		ll = append(ll, l.name)
+	for _, l := range media.Loaders() {
+		ll = append(ll, l.Name)
 	}
 	loadCmd.Flags().StringVarP(&useLoader, "loader", "l", "", "Loader to use (supported loaders: "+strings.Join(ll, ", ")+")")
 	loadCmd.Flags().BoolVarP(&controlAfterwards, "control", "c", false, "Launch control afterwards")
+	loadCmd.Flags().StringVar(&loadTitle, "title", "", "Title displayed by the chromecast")
+	loadCmd.Flags().StringVar(&loadImage, "image", "", "URL of the image displayed by the chromecast")
+	loadCmd.Flags().BoolVar(&loadLive, "live", false, "Load the media as a live stream")
+	loadCmd.Flags().IntVar(&loadRetries, "retries", 0, "Number of retries when the chromecast fails to load the media")
+	loadCmd.Flags().Float64Var(&subtitleScale, "subtitle-scale", 0, "Size of the subtitles (1 is the default size)")
+	loadCmd.Flags().StringVar(&loadLicenseURL, "license-url", "", "URL of the license server of a DRM-protected stream")
+	loadCmd.Flags().BoolVar(&concurrentProbe, "concurrent", false, "Probe all the loaders concurrently (the first successful loader of the highest priority is used)")
+	loadCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the loaders which can handle the URL (without loading it)")
+	loadCmd.Flags().BoolVar(&followRedirects, "follow-redirects", false, "Follow the redirects of the URL (like a short link) before choosing the loader")
 	rootCmd.AddCommand(loadCmd)
 }
 
 var loadCmd = &cobra.Command{
-	Use:   "load [url]",
-	Short: "Load a URL",
+	Use:   "load [url|file]",
+	Short: "Load a URL (or a local file, served until the end of the playback)",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		rawurl := args[0]
@@ -67,36 +169,108 @@ var loadCmd = &cobra.Command{
 		logger, ctx, cancel := flags()
 		defer cancel()
 
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		loaderConfigs, err := cfg.LoaderConfigs()
+		if err != nil {
+			return err
+		}
+		media.SetLoaderConfigs(loaderConfigs)
+
+		if followRedirects && !localmedia.IsLocal(rawurl) {
+			resolveCtx, resolveCancel := context.WithTimeout(context.Background(), media.DetectTimeout)
+			resolved, err := media.ResolveRedirects(resolveCtx, http.DefaultClient, rawurl)
+			resolveCancel()
+			if err != nil {
+				logger.Log("url", rawurl, "state", "resolving", "err", err)
+			} else if resolved != rawurl {
+				fmt.Printf("Redirected to %s\n", resolved)
+				rawurl = resolved
+			}
+		}
+
+		if dryRun {
+			return printCandidates(rawurl)
+		}
+
 		client, status, err := GetClientWithStatus(ctx, logger)
 		if err != nil {
 			return fmt.Errorf("could not get a client: %w", err)
 		}
 		defer client.Close()
+		if !status.OnScreen() {
+			fmt.Println("Warning: the TV is in standby or on another input")
+		}
+		if localmedia.IsLocal(rawurl) {
+			return loadLocal(ctx, cancel, logger, client, status, rawurl)
+		}
+		if subtitlesFile != "" {
+			return fmt.Errorf("the subtitles can only be added to a local file")
+		}
+
+		if concurrentProbe && useLoader == "" {
+			probeCtx, probeCancel := context.WithCancel(context.Background())
+			defer probeCancel()
+			for p := range media.ProbeLoaders(probeCtx, media.Candidates(rawurl), rawurl, loadOptions()...) {
+				if p.Err != nil {
+					logger.Log("loader", p.Name, "state", "probing", "err", p.Err)
+					continue
+				}
+				c, err := p.Load(client, status)
+				if err != nil {
+					logger.Log("loader", p.Name, "state", "loading", "err", err)
+					continue
+				}
+				fmt.Printf("Loading with %s\n", p.Name)
+				if fallback, err := awaitLoad(logger, p.Name, c, true); fallback {
+					continue
+				} else if err != nil {
+					return err
+				}
+				probeCancel()
+				if err := printLoadedJSON(client, p.Name); err != nil {
+					return err
+				}
+				if controlAfterwards {
+					return remote(ctx, cancel, logger, client, status)
+				}
+				return nil
+			}
+			return fmt.Errorf("no supported loader found for %s", rawurl)
+		}
 
-		for _, l := range loaders {
+		for _, l := range media.Loaders() {
 			var c <-chan []byte
 			var err error
 
 			if useLoader != "" {
-				if l.name != useLoader {
+				if l.Name != useLoader {
 					continue
 				}
-				c, err = l.load(client, status, rawurl)
+				c, err = load(l, client, status, rawurl, loadOptions()...)
 				if err != nil {
 					return err
 				}
 			} else {
-				c, err = l.load(client, status, rawurl)
+				if !l.CanHandle(rawurl) {
+					continue
+				}
+				c, err = load(l, client, status, rawurl, loadOptions()...)
 				if err != nil {
-					logger.Log("loader", l.name, "state", "loading", "err", err)
+					logger.Log("loader", l.Name, "state", "loading", "err", err)
 					continue
 				}
-				fmt.Printf("Loading with %s\n", l.name)
+				fmt.Printf("Loading with %s\n", l.Name)
+			}
+			if fallback, err := awaitLoad(logger, l.Name, c, useLoader == ""); fallback {
+				continue
+			} else if err != nil {
+				return err
 			}
-			select {
-			case <-c:
-			case <-time.After(loadRequestTimeout):
-				logger.Log("loader", l.name, "err", "load request didn't return after 10s")
+			if err := printLoadedJSON(client, l.Name); err != nil {
+				return err
 			}
 			if controlAfterwards {
 				return remote(ctx, cancel, logger, client, status)
@@ -105,8 +279,8 @@ var loadCmd = &cobra.Command{
 		}
 		if useLoader != "" {
 			var ll []string
-			for _, l := range loaders {
-				ll = append(ll, l.name)
+			for _, l := range media.Loaders() {
+				ll = append(ll, l.Name)
 			}
 			return fmt.Errorf("unknown loader '%s' (supported loaders: %s)", useLoader, strings.Join(ll, ", "))
 		}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+	"github.com/oliverpool/go-chromecast/command/media/defaultreceiver"
+	"github.com/oliverpool/go-chromecast/localmedia"
+)
+
+var servePort int
+var subtitlesFile string
+var transcodeMode string
+
+func init() {
+	loadCmd.Flags().StringVar(&transcodeMode, "transcode", string(localmedia.TranscodeAuto), "Transcode a local file with ffmpeg: auto (if the codecs are not supported), always or never")
+	loadCmd.Flags().StringVar(&subtitlesFile, "subtitles", "", "Local subtitles file (.srt or .vtt) of a local file")
+	loadCmd.Flags().IntVar(&servePort, "serve-port", 0, fmt.Sprintf("Port to serve a local file on (the first available port of %d-%d if 0)", localmedia.DefaultPorts[0], localmedia.DefaultPorts[len(localmedia.DefaultPorts)-1]))
+}
+
+// loadLocal serves the local file and loads it on the default receiver.
+// The file is served until the end of the playback (or until the control is left).
+func loadLocal(
+	initCtx context.Context,
+	initCancel context.CancelFunc,
+	logger chromecast.Logger,
+	client chromecast.Client,
+	status chromecast.Status,
+	path string,
+) error {
+	mode, err := localmedia.ParseTranscodeMode(transcodeMode)
+	if err != nil {
+		return err
+	}
+	var ports []int
+	if servePort > 0 {
+		ports = []int{servePort}
+	}
+	// the file must be reachable on the interface used to reach the chromecast
+	srv, err := localmedia.ServeTranscoded(path, mode, net.ParseIP(bindAddr), ports...)
+	if err != nil {
+		return fmt.Errorf("could not serve '%s': %w", path, err)
+	}
+	defer srv.Close()
+	logger.Log("file", path, "url", srv.URL, "contentType", srv.ContentType, "transcoded", srv.Transcoded)
+	if srv.Transcoded {
+		fmt.Println("Transcoding with ffmpeg (seeking is not supported)")
+	}
+
+	options := loadOptions()
+	if subtitlesFile != "" {
+		u, err := srv.ServeSubtitles(subtitlesFile)
+		if err != nil {
+			return fmt.Errorf("could not serve the subtitles: %w", err)
+		}
+		options = append(options, media.WithSubtitles(u, filepath.Base(subtitlesFile), ""))
+	}
+
+	app, err := defaultreceiver.LaunchAndConnect(client, status)
+	if err != nil {
+		return fmt.Errorf("could not launch the media receiver: %w", err)
+	}
+
+	if loadTitle == "" {
+		options = append([]media.Option{media.Metadata(media.GenericMediaMetadata{Title: filepath.Base(path)})}, options...)
+	}
+	loadCtx, loadCancel := context.WithTimeout(context.Background(), loadRequestTimeout)
+	defer loadCancel()
+	session, err := app.LoadAndGetSessionCtx(loadCtx, media.Item{
+		ContentID:   srv.URL,
+		ContentType: srv.ContentType,
+		StreamType:  "BUFFERED",
+	}, options...)
+	if err != nil {
+		return fmt.Errorf("could not load '%s': %w", path, err)
+	}
+	if err := printLoadedJSON(client, "local"); err != nil {
+		return err
+	}
+
+	if controlAfterwards {
+		return remote(initCtx, initCancel, logger, client, status)
+	}
+
+	fmt.Printf("Serving %s until the end of the playback (Ctrl+C to stop)\n", path)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		select {
+		case <-interrupt:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	_, err = session.WaitFor(ctx, media.Idle)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
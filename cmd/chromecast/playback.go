@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of the playback commands (1 for the other errors, like an unreachable device)
+const (
+	exitNoSession = 2 // no media is loaded on the chromecast
+	exitRejected  = 3 // the receiver rejected the command (like seeking a live stream)
+)
+
+// exitError makes the program exit with a specific code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e exitError) Unwrap() error {
+	return e.err
+}
+
+func init() {
+	seekCmd := playbackCommand("seek [position]", "Seek to a position (like 1m30s, 1:30 or 90) or by an offset (like +10s or -1m)", cobra.ExactArgs(1), func(ctx context.Context, s *media.Session, args []string) error {
+		t, relative, err := parsePosition(args[0])
+		if err != nil {
+			return err
+		}
+		if relative {
+			return s.SeekByCtx(ctx, t)
+		}
+		return s.SeekCtx(ctx, t)
+	})
+	// a negative offset would be parsed as a flag
+	seekCmd.Example = "  chromecast seek 1m30s\n  chromecast seek +10s\n  chromecast seek -- -10s"
+
+	rootCmd.AddCommand(
+		playbackCommand("play", "Resume the playback", cobra.NoArgs, func(ctx context.Context, s *media.Session, args []string) error {
+			return s.PlayCtx(ctx)
+		}),
+		playbackCommand("pause", "Pause the playback", cobra.NoArgs, func(ctx context.Context, s *media.Session, args []string) error {
+			return s.PauseCtx(ctx)
+		}),
+		playbackCommand("stop", "Stop the playback", cobra.NoArgs, func(ctx context.Context, s *media.Session, args []string) error {
+			return s.StopCtx(ctx)
+		}),
+		seekCmd,
+		playbackCommand("next", "Play the next item of the queue", cobra.NoArgs, func(ctx context.Context, s *media.Session, args []string) error {
+			return s.QueueJumpCtx(ctx, 1)
+		}),
+		playbackCommand("prev", "Play the previous item of the queue", cobra.NoArgs, func(ctx context.Context, s *media.Session, args []string) error {
+			return s.QueueJumpCtx(ctx, -1)
+		}),
+	)
+}
+
+// playbackCommand returns a non-interactive command, which performs the action on the current media session
+// and prints the resulting state (as a jsonState with --json).
+// It exits with exitNoSession if no media is loaded and with exitRejected if the receiver rejected the action.
+func playbackCommand(use, short string, args cobra.PositionalArgs, action func(context.Context, *media.Session, []string) error) *cobra.Command {
+	name := strings.Fields(use)[0]
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long: short + fmt.Sprintf(`.
+
+The command is sent to the media playing on the chromecast, then the resulting state is printed
+(like "PLAYING 1m30s/45m0s Big Buck Bunny").
+
+Exit codes: 0 on success, %d if no media is loaded, %d if the receiver rejected the command, 1 otherwise.`, exitNoSession, exitRejected),
+		Args: args,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger, ctx, cancel := flags()
+			defer cancel()
+
+			client, status, err := GetClientWithStatus(ctx, logger)
+			if err != nil {
+				return fmt.Errorf("could not get a client: %w", err)
+			}
+			defer client.Close()
+
+			_, session, err := media.Attach(ctx, client, status)
+			if errors.Is(err, chromecast.ErrAppNotFound) || errors.Is(err, chromecast.ErrNoSession) {
+				return exitError{exitNoSession, errors.New("no media is loaded")}
+			}
+			if err != nil {
+				return fmt.Errorf("could not attach to a media session: %w", err)
+			}
+
+			err = action(ctx, session, args)
+			var reqErr chromecast.RequestError
+			if errors.As(err, &reqErr) {
+				return exitError{exitRejected, fmt.Errorf("%s rejected: %w", name, err)}
+			}
+			if err != nil {
+				return fmt.Errorf("could not %s: %w", name, err)
+			}
+
+			if jsonOutput {
+				state, err := newJSONState(client)
+				if err != nil {
+					return err
+				}
+				return printJSON(state)
+			}
+
+			st, err := session.StatusCtx(ctx)
+			if errors.Is(err, chromecast.ErrNoSession) {
+				// the session ended (after a stop or at the end of the queue)
+				fmt.Println(media.Idle)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("could not get the media status: %w", err)
+			}
+			fmt.Println(playbackState(st))
+			return nil
+		},
+	}
+}
+
+// playbackState returns the state, the position and the title of the media, like
+// PLAYING 1m30s/45m0s Big Buck Bunny
+func playbackState(st media.Status) string {
+	line := string(st.PlayerState) + " " + st.CurrentTime.Round(time.Second).String()
+	if st.Item != nil {
+		if st.Item.Duration.Duration > 0 {
+			line += "/" + st.Item.Duration.Round(time.Second).String()
+		}
+		if title, ok := st.Item.Metadata["title"].(string); ok && title != "" {
+			line += " " + title
+		}
+	}
+	return line
+}
+
+// parsePosition parses an absolute position (like 1m30s, 1:30, 1:02:03 or 90 seconds)
+// or a relative one (like +10s or -1m)
+func parsePosition(arg string) (t time.Duration, relative bool, err error) {
+	relative = strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-")
+	abs := strings.TrimLeft(arg, "+-")
+	switch {
+	case strings.Contains(abs, ":"):
+		for _, part := range strings.Split(abs, ":") {
+			n, err := strconv.ParseUint(part, 10, 32)
+			if err != nil {
+				return 0, false, fmt.Errorf("invalid position '%s'", arg)
+			}
+			t = t*60 + time.Duration(n)*time.Second
+		}
+	default:
+		if seconds, err := strconv.ParseFloat(abs, 64); err == nil {
+			t = time.Duration(seconds * float64(time.Second))
+		} else if t, err = time.ParseDuration(abs); err != nil {
+			return 0, false, fmt.Errorf("invalid position '%s'", arg)
+		}
+	}
+	if strings.HasPrefix(arg, "-") {
+		t = -t
+	}
+	return t, relative, nil
+}
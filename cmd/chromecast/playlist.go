@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/oliverpool/go-chromecast/command"
+	"github.com/oliverpool/go-chromecast/command/media"
+	"github.com/oliverpool/go-chromecast/command/media/defaultreceiver"
+	"github.com/spf13/cobra"
+)
+
+var playlistRepeat bool
+var playlistStart int
+
+func init() {
+	playlistCmd.Flags().BoolVar(&playlistRepeat, "repeat", false, "Restart with the first item after the last one")
+	playlistCmd.Flags().IntVar(&playlistStart, "start", 1, "Number of the item to play first")
+	rootCmd.AddCommand(playlistCmd)
+}
+
+// jsonPlaylistEntry is the resolution of an entry of the playlist (in JSON mode)
+type jsonPlaylistEntry struct {
+	URL    string `json:"url"`
+	Loader string `json:"loader,omitempty"`
+	Items  int    `json:"items"`
+	Error  string `json:"error,omitempty"`
+}
+
+// jsonPlaylist is printed by the playlist command (in JSON mode)
+type jsonPlaylist struct {
+	Entries []jsonPlaylistEntry `json:"entries"`
+	jsonState
+}
+
+var playlistCmd = &cobra.Command{
+	Use:   "playlist <file|url>...",
+	Short: "Queue the URLs (given as arguments or listed in files) on the chromecast",
+	Long: `Queue the URLs (given as arguments or listed in files, one per line) on the chromecast.
+
+Each URL is resolved by the first loader able to queue it on the default media receiver.
+The URLs which could not be resolved are reported and skipped.
+In the files, the empty lines and the lines starting with # are ignored (so .m3u files are supported).`,
+	Example: `  chromecast playlist urls.txt
+  chromecast playlist https://example.com/a.mp4 https://example.com/b.mp4 --repeat`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		urls, err := playlistURLs(args)
+		if err != nil {
+			return err
+		}
+		if len(urls) == 0 {
+			return fmt.Errorf("the playlist is empty")
+		}
+
+		logger, _, _ := flags()
+
+		var (
+			entries []jsonPlaylistEntry
+			items   []media.QueueItem
+			failed  int
+		)
+		for i, rawurl := range urls {
+			entry := jsonPlaylistEntry{URL: rawurl}
+			loader, resolved, err := media.Resolve(rawurl)
+			if err != nil {
+				failed++
+				entry.Error = err.Error()
+				fmt.Printf("%d/%d skipped: %v\n", i+1, len(urls), err)
+			} else {
+				entry.Loader, entry.Items = loader, len(resolved)
+				fmt.Printf("%d/%d %s (%s", i+1, len(urls), rawurl, loader)
+				if len(resolved) > 1 {
+					fmt.Printf(", %d items", len(resolved))
+				}
+				fmt.Println(")")
+				for _, item := range resolved {
+					items = append(items, media.NewQueueItem(item))
+				}
+			}
+			entries = append(entries, entry)
+		}
+		if len(items) == 0 {
+			return fmt.Errorf("none of the %d URLs could be resolved", len(urls))
+		}
+		if playlistStart < 1 || playlistStart > len(items) {
+			return fmt.Errorf("--start must be between 1 and %d", len(items))
+		}
+
+		// the timeout starts after the resolution of the URLs
+		_, ctx, cancel := flags()
+		defer cancel()
+
+		client, status, err := GetClientWithStatus(ctx, logger)
+		if err != nil {
+			return fmt.Errorf("could not get a client: %w", err)
+		}
+		defer client.Close()
+
+		app, err := defaultreceiver.LaunchAndConnect(client, status)
+		if err != nil {
+			return fmt.Errorf("could not launch the media receiver: %w", err)
+		}
+		options := []media.Option{media.StartIndex(playlistStart - 1)}
+		if playlistRepeat {
+			options = append(options, media.WithRepeatMode(media.RepeatAll))
+		}
+		queueCtx, queueCancel := context.WithTimeout(context.Background(), command.DefaultTimeout)
+		defer queueCancel()
+		if _, err = app.QueueLoadAndGetSessionCtx(queueCtx, items, options...); err != nil {
+			return fmt.Errorf("could not load the queue: %w", err)
+		}
+
+		fmt.Printf("%d items queued", len(items))
+		if failed > 0 {
+			fmt.Printf(" (%d URLs skipped)", failed)
+		}
+		fmt.Println()
+
+		if jsonOutput {
+			state, err := newJSONState(client)
+			if err != nil {
+				return err
+			}
+			return printJSON(jsonPlaylist{Entries: entries, jsonState: state})
+		}
+		return nil
+	},
+}
+
+// playlistURLs returns the URLs given as arguments, replacing the files by the URLs they list
+func playlistURLs(args []string) ([]string, error) {
+	var urls []string
+	for _, arg := range args {
+		f, err := os.Stat(arg)
+		if err != nil || f.IsDir() {
+			urls = append(urls, arg)
+			continue
+		}
+		listed, err := readPlaylist(arg)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, listed...)
+	}
+	return urls, nil
+}
+
+// readPlaylist returns the URLs listed in the file (ignoring the empty lines and the comments)
+func readPlaylist(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var urls []string
+	lines := bufio.NewScanner(f)
+	for lines.Scan() {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("could not read the playlist %s: %w", path, err)
+	}
+	return urls, nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"os"
 	"time"
 
@@ -14,13 +15,43 @@ import (
 var rootCmd = &cobra.Command{
 	Use:   "chromecast",
 	Short: "chromecast allows you to interact with a Chromecast",
+	Long: `chromecast allows you to interact with a Chromecast.
+
+The defaults are read from the TOML configuration file ~/.config/chromecast/config.toml
+(in $XDG_CONFIG_HOME/chromecast if set, see --config to use another file).
+For instance:
+
+  default_device = "kitchen"
+  timeout = "5s"
+  volume_step = 0.02
+
+  [[devices]]
+  name = "kitchen"
+  ip = "192.168.1.20"
+  volume_step = 0.1
+
+  [loaders.vimeo]
+  access_token = "..."
+
+The flags override the configuration file, which overrides the environment variables
+CHROMECAST_DEVICE, CHROMECAST_TIMEOUT and CHROMECAST_VOLUME_STEP.`,
 }
 
 var timeout time.Duration
 var verbose bool
+var heartbeatInterval time.Duration
+var heartbeatMaxMissed int
+var bindAddr string
+var recordFile string
+var jsonOutput bool
+var volumeStep float64
 
 func flags() (chromecast.Logger, context.Context, context.CancelFunc) {
 	rootCmd.SilenceUsage = true
+	if jsonOutput {
+		// the messages are printed to stderr (stdout is kept for the JSON output, see jsonStdout)
+		os.Stdout = os.Stderr
+	}
 	logger := log.NopLogger()
 	if verbose {
 		logger = log.New(os.Stdout)
@@ -36,10 +67,20 @@ func flags() (chromecast.Logger, context.Context, context.CancelFunc) {
 func init() {
 	rootCmd.PersistentFlags().DurationVarP(&timeout, "timeout", "t", 10*time.Second, "Duration before stopping looking for chromecast(s) (0 means no timeout)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Print verbose (debug) output")
+	rootCmd.PersistentFlags().DurationVar(&heartbeatInterval, "heartbeat", 5*time.Second, "Interval between the PINGs sent to the chromecast")
+	rootCmd.PersistentFlags().IntVar(&heartbeatMaxMissed, "heartbeat-missed", 3, "Number of PINGs without PONG before reconnecting")
+	rootCmd.PersistentFlags().StringVar(&recordFile, "record", "", "File to record the exchanged messages to (one JSON object per line)")
+	rootCmd.PersistentFlags().Float64Var(&volumeStep, "volume-step", 0, "Step of the volume changes (0 for the step of the chromecast)")
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Print the result as JSON (the other messages are printed to stderr)")
+	rootCmd.PersistentFlags().StringVar(&bindAddr, "bind", "", "Local IP address to connect from (to go through a specific interface, like a VPN)")
 }
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
@@ -1,24 +1,49 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/daemon"
+	"github.com/oliverpool/go-chromecast/discovery"
+	"github.com/oliverpool/go-chromecast/localmedia"
 	"github.com/spf13/cobra"
 )
 
+var serveListen string
+
 func init() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", "", "Address to serve the REST API controlling the chromecasts on (like :8011), instead of serving a folder")
 	rootCmd.AddCommand(serveCmd)
 }
 
 var serveCmd = &cobra.Command{
-	Use:   "serve",
-	Short: "Serve the current folder",
-	Args:  cobra.MaximumNArgs(1),
+	Use:   "serve [folder|file]",
+	Short: "Serve the current folder (or the REST API controlling the chromecasts with --listen)",
+	Long: `Serve the current folder (or the given folder or file) to the chromecasts.
+
+With --listen, serve a REST API instead, keeping a connection to the chromecasts of the network:
+  GET  /devices
+  GET  /devices/{uuid}/status
+  POST /devices/{uuid}/load     {"url": "...", "loader": "...", "title": "..."}
+  POST /devices/{uuid}/play     (as well as /pause and /stop)
+  POST /devices/{uuid}/seek     {"position": 90}
+  POST /devices/{uuid}/volume   {"level": 0.5, "muted": false}
+  GET  /events                  server-sent events of the discovery and of the status changes`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if serveListen != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("--listen does not serve any folder")
+			}
+			return serveAPI(serveListen)
+		}
 		file := "..."
 		folder := "."
 		if len(args) > 0 {
@@ -34,7 +59,7 @@ var serveCmd = &cobra.Command{
 			}
 		}
 
-		ip, err := getOutboundIP()
+		ip, err := localmedia.OutboundIP()
 		if err != nil {
 			return err
 		}
@@ -62,6 +87,78 @@ var serveCmd = &cobra.Command{
 	},
 }
 
+// serveAPI serves the REST API of the daemon until interrupted
+func serveAPI(addr string) error {
+	logger, _, _ := flags()
+
+	scanner, err := deviceFinder.Scanner(logger, nil)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if static := cfg.StaticScanner(); static != nil {
+		scanner = discovery.Multi(static, scanner)
+	}
+	subnet, err := deviceFinder.SubnetMatcher()
+	if err != nil {
+		return err
+	}
+	var matchers []discovery.DeviceMatcher
+	if subnet != nil {
+		matchers = append(matchers, subnet)
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	srv := &daemon.Server{
+		Scanner: scanner,
+		Connect: func(ctx context.Context, d *chromecast.Device) (chromecast.Client, error) {
+			return ConnectedClient(ctx, d.Addr(), deviceFinder.TLSConfig(d), logger)
+		},
+		Matchers: matchers,
+		Logger:   logger,
+	}
+	ran := make(chan error, 1)
+	go func() {
+		ran <- srv.Run(ctx)
+	}()
+
+	httpSrv := &http.Server{Handler: srv}
+	served := make(chan error, 1)
+	go func() {
+		served <- httpSrv.Serve(listener)
+	}()
+	fmt.Printf("Serving the REST API on http://%s/devices\n", listener.Addr())
+	fmt.Println("Type Ctrl+C to stop")
+
+	select {
+	case err = <-served:
+	case err = <-ran:
+		httpSrv.Close()
+		return err
+	case <-interrupt:
+	}
+	httpSrv.Close()
+	cancel()
+	<-ran
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
 func allowOneURI(uri string, h http.Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/"+uri {
@@ -71,15 +168,3 @@ func allowOneURI(uri string, h http.Handler) http.HandlerFunc {
 		h.ServeHTTP(w, r)
 	}
 }
-
-// Get preferred outbound ip of this machine
-// from https://stackoverflow.com/a/37382208
-func getOutboundIP() (net.IP, error) {
-	conn, err := net.Dial("udp", "1.1.1.1:80")
-	if err != nil {
-		return nil, err
-	}
-	defer conn.Close()
-
-	return conn.LocalAddr().(*net.UDPAddr).IP, nil
-}
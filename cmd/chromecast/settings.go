@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/setupapi"
+	"github.com/spf13/cobra"
+)
+
+var setupToken string
+var yesReally bool
+
+func init() {
+	deviceCmd.PersistentFlags().StringVar(&setupToken, "token", "", "Local authorization token (to use the HTTPS setup API of the recent firmwares)")
+	deviceFactoryResetCmd.Flags().BoolVar(&yesReally, "yes-really", false, "Confirm the factory reset (all the settings will be lost)")
+	deviceCmd.AddCommand(deviceInfoCmd, deviceRenameCmd, deviceTimezoneCmd, deviceLocaleCmd, deviceRebootCmd, deviceFactoryResetCmd)
+	rootCmd.AddCommand(deviceCmd)
+}
+
+var deviceCmd = &cobra.Command{
+	Use:   "device",
+	Short: "Read and change the settings of the chromecast (setup API)",
+}
+
+// setupClient finds the device and returns a client for its setup API
+func setupClient(ctx context.Context, logger chromecast.Logger) (setupapi.Client, error) {
+	d, err := deviceFinder.GetDevice(ctx, logger)
+	if err != nil {
+		return setupapi.Client{}, err
+	}
+	if setupToken != "" {
+		return setupapi.NewSecure(d, setupToken), nil
+	}
+	return setupapi.New(d), nil
+}
+
+var deviceInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Print the name, locale and timezone of the chromecast",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger, ctx, cancel := flags()
+		defer cancel()
+
+		client, err := setupClient(ctx, logger)
+		if err != nil {
+			return err
+		}
+		settings, err := client.Settings(ctx)
+		if err != nil {
+			return fmt.Errorf("could not get the settings: %w", err)
+		}
+		fmt.Printf("Name: %s\n", settings.Name)
+		fmt.Printf("Locale: %s\n", settings.Locale)
+		fmt.Printf("Timezone: %s\n", settings.Timezone)
+		return nil
+	},
+}
+
+// settingCmd changes a setting with the given setter
+func settingCmd(use, short string, set func(client setupapi.Client, ctx context.Context, value string) error) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger, ctx, cancel := flags()
+			defer cancel()
+
+			client, err := setupClient(ctx, logger)
+			if err != nil {
+				return err
+			}
+			if err = set(client, ctx, args[0]); err != nil {
+				return fmt.Errorf("could not change the setting: %w", err)
+			}
+			fmt.Println("OK")
+			return nil
+		},
+	}
+}
+
+var deviceRenameCmd = settingCmd("rename <name>", "Rename the chromecast", setupapi.Client.SetName)
+
+var deviceTimezoneCmd = settingCmd("timezone <timezone>", "Change the timezone of the chromecast (like Europe/Paris)", setupapi.Client.SetTimezone)
+
+var deviceLocaleCmd = settingCmd("locale <locale>", "Change the locale of the chromecast (like en-US)", setupapi.Client.SetLocale)
+
+var deviceRebootCmd = &cobra.Command{
+	Use:   "reboot",
+	Short: "Reboot the chromecast",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger, ctx, cancel := flags()
+		defer cancel()
+
+		client, err := setupClient(ctx, logger)
+		if err != nil {
+			return err
+		}
+		if err = client.Reboot(ctx); err != nil {
+			return fmt.Errorf("could not reboot: %w", err)
+		}
+		fmt.Println("Rebooting")
+		return nil
+	},
+}
+
+var deviceFactoryResetCmd = &cobra.Command{
+	Use:   "factory-reset",
+	Short: "Reset the chromecast to its factory settings (requires --yes-really)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !yesReally {
+			return fmt.Errorf("all the settings of the chromecast will be lost: add --yes-really to confirm")
+		}
+		logger, ctx, cancel := flags()
+		defer cancel()
+
+		client, err := setupClient(ctx, logger)
+		if err != nil {
+			return err
+		}
+		if err = client.FactoryReset(ctx); err != nil {
+			return fmt.Errorf("could not reset: %w", err)
+		}
+		fmt.Println("Resetting")
+		return nil
+	},
+}
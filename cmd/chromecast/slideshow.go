@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	"github.com/oliverpool/go-chromecast/command/media/defaultreceiver"
+	"github.com/oliverpool/go-chromecast/command/media/slideshow"
+	"github.com/oliverpool/go-chromecast/localmedia"
+	"github.com/spf13/cobra"
+)
+
+var slideDuration time.Duration
+var slideLoop bool
+
+func init() {
+	slideshowCmd.Flags().DurationVarP(&slideDuration, "duration", "d", slideshow.DefaultDuration, "Duration of each slide")
+	slideshowCmd.Flags().BoolVar(&slideLoop, "loop", false, "Restart with the first image after the last one")
+	rootCmd.AddCommand(slideshowCmd)
+}
+
+var slideshowCmd = &cobra.Command{
+	Use:   "slideshow <dir|url>...",
+	Short: "Show the images of a local folder (or of the given URLs) one after the other",
+	Long: `Show the images of a local folder (or of the given URLs) one after the other.
+
+The slideshow stops after the last image (unless --loop is set), on Ctrl+C or when the --timeout given explicitly expires.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		urls := args
+		if f, err := os.Stat(args[0]); err == nil && f.IsDir() {
+			if len(args) > 1 {
+				return fmt.Errorf("only one folder can be shown")
+			}
+			urls, err = serveImages(args[0])
+			if err != nil {
+				return err
+			}
+		}
+
+		logger, ctx, cancel := flags()
+		defer cancel()
+
+		client, status, err := GetClientWithStatus(ctx, logger)
+		if err != nil {
+			return fmt.Errorf("could not get a client: %w", err)
+		}
+		defer client.Close()
+
+		app, err := defaultreceiver.LaunchAndConnect(client, status)
+		if err != nil {
+			return fmt.Errorf("could not launch the media receiver: %w", err)
+		}
+
+		// the default timeout is meant for the discovery: the slideshow is only limited by an explicit --timeout
+		showCtx := context.Background()
+		if cmd.Flag("timeout").Changed {
+			showCtx = ctx
+		}
+		showCtx, showCancel := context.WithCancel(showCtx)
+		defer showCancel()
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		defer signal.Stop(interrupt)
+		go func() {
+			select {
+			case <-interrupt:
+				showCancel()
+			case <-showCtx.Done():
+			}
+		}()
+
+		fmt.Printf("Showing %d images (Ctrl+C to stop)\n", len(urls))
+		err = slideshow.Slideshow{
+			URLs:     urls,
+			Duration: slideDuration,
+			Loop:     slideLoop,
+		}.Run(showCtx, app)
+		if showCtx.Err() != nil {
+			// stopped by Ctrl+C or by the timeout
+			return nil
+		}
+		return err
+	},
+}
+
+// serveImages serves the folder (in the background) and returns the URLs of its images
+func serveImages(folder string) ([]string, error) {
+	files, err := ioutil.ReadDir(folder)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, f := range files {
+		if !f.IsDir() && slideshow.ContentType(f.Name()) != "" {
+			names = append(names, f.Name())
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no image found in %s", folder)
+	}
+	sort.Strings(names)
+
+	ip, err := localmedia.OutboundIP()
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		return nil, err
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	go http.Serve(listener, http.FileServer(http.Dir(folder)))
+
+	urls := make([]string, len(names))
+	for i, name := range names {
+		urls[i] = fmt.Sprintf("http://%s:%d/%s", ip, port, url.PathEscape(name))
+	}
+	return urls, nil
+}
@@ -2,12 +2,17 @@ package main
 
 import (
 	"fmt"
+	"time"
 
+	chromecast "github.com/oliverpool/go-chromecast"
 	"github.com/oliverpool/go-chromecast/command/media"
 	"github.com/spf13/cobra"
 )
 
+var statusWatch bool
+
 func init() {
+	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, "Print a line (or a JSON object) whenever the receiver or media status changes, until interrupted")
 	rootCmd.AddCommand(statusCmd)
 }
 
@@ -23,8 +28,29 @@ var statusCmd = &cobra.Command{
 			return fmt.Errorf("could not get a client: %w", err)
 		}
 		defer client.Close()
+		if statusWatch {
+			return watchStatus(logger, client, status)
+		}
+		if jsonOutput {
+			state, err := newJSONState(client)
+			if err != nil {
+				return err
+			}
+			if pinger, ok := client.(chromecast.Pinger); ok {
+				if rtt, err := pinger.Ping(ctx); err == nil {
+					state.LatencyMs = rtt.Milliseconds()
+				}
+			}
+			return printJSON(state)
+		}
 		fmt.Println("\n", status.String())
 
+		if pinger, ok := client.(chromecast.Pinger); ok {
+			if rtt, err := pinger.Ping(ctx); err == nil {
+				fmt.Printf("  Latency: %s\n", rtt.Round(time.Millisecond))
+			}
+		}
+
 		// Get media app
 		fmt.Print("\nLooking for a media app...")
 		app, err := media.ConnectFromStatus(client, status)
@@ -2,7 +2,13 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
+	gonet "net"
+	"os"
+	"path/filepath"
+	"time"
 
 	chromecast "github.com/oliverpool/go-chromecast"
 	"github.com/oliverpool/go-chromecast/client"
@@ -12,6 +18,23 @@ import (
 	"github.com/oliverpool/go-chromecast/net"
 )
 
+// TLSConfig returns the TLS configuration to connect to the device (nil to accept any certificate)
+// If PinCert is set, the certificates are pinned by device ID (or address if unknown) next to the cache file
+func (df deviceFinderConstraints) TLSConfig(d *chromecast.Device) *tls.Config {
+	if !df.PinCert || df.CacheFile == "" {
+		return nil
+	}
+	id := d.ID()
+	if id == "" {
+		id = d.Addr()
+	}
+	store := net.FilePinStore{Path: filepath.Join(filepath.Dir(df.CacheFile), "certificates.json")}
+	return net.Pinned(store, id)
+}
+
+// connectedDevice is the device found by GetClientWithStatus
+var connectedDevice *chromecast.Device
+
 func GetClientWithStatus(ctx context.Context, logger chromecast.Logger) (chromecast.Client, chromecast.Status, error) {
 	// Find device
 	fmt.Print("Searching device...")
@@ -19,11 +42,12 @@ func GetClientWithStatus(ctx context.Context, logger chromecast.Logger) (chromec
 	if err != nil {
 		return nil, chromecast.Status{}, err
 	}
+	connectedDevice = chr
 	fmt.Println(" " + chr.Addr() + " OK")
 
 	// Connect client
 	fmt.Print("Connecting client...")
-	client, err := ConnectedClient(ctx, chr.Addr(), logger)
+	client, err := ConnectedClient(ctx, chr.Addr(), deviceFinder.TLSConfig(chr), logger)
 	if err != nil {
 		return nil, chromecast.Status{}, fmt.Errorf("could not connect to client: %w", err)
 	}
@@ -42,23 +66,89 @@ func GetClientWithStatus(ctx context.Context, logger chromecast.Logger) (chromec
 }
 
 // ConnectedClient will create a client and keep it connected
-func ConnectedClient(ctx context.Context, addr string, logger chromecast.Logger) (*client.Client, error) {
-	conn, err := net.Dial(ctx, addr)
+// If the connection drops, it is reestablished with an exponential backoff
+// (see OnReconnect of the client to be notified)
+// The TLS configuration can be nil (see net.DialTLS)
+func ConnectedClient(ctx context.Context, addr string, config *tls.Config, logger chromecast.Logger) (*client.Client, error) {
+	dialer, err := localDialer()
 	if err != nil {
 		return nil, err
 	}
 
-	serializer := gogoprotobuf.Serializer{
+	// the hooks of conn wait for the client to be created
+	var c *client.Client
+	ready := make(chan struct{})
+
+	conn := &net.ReconnectingConn{
+		Dial: func(ctx context.Context) (io.ReadWriteCloser, error) {
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			return net.DialWith(ctx, dialer, addr, config)
+		},
+		WriteTimeout: 10 * time.Second,
+		OnError: func(err error) {
+			logger.Log("step", "reconnect", "err", err)
+		},
+		OnDisconnect: func() {
+			logger.Log("step", "disconnected", "addr", addr)
+			<-ready
+			c.Reconnecting()
+		},
+		OnReconnect: func() {
+			logger.Log("step", "reconnected", "addr", addr)
+			<-ready
+			c.Reconnected()
+		},
+	}
+	if err := conn.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	var wire chromecast.Serializer = &gogoprotobuf.Serializer{
 		Conn:   conn,
 		Logger: logger,
 	}
-	c := client.New(&serializer, logger)
+	var record *os.File
+	if recordFile != "" {
+		record, err = os.OpenFile(recordFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("could not open the record file: %w", err)
+		}
+		wire = &client.RecordingSerializer{Serializer: wire, W: record}
+	}
+
+	serializer := client.NewQueuedSerializer(wire, 0, client.ErrorWhenFull, logger)
+	c = client.New(serializer, logger)
+	close(ready)
 	c.AfterClose = append(c.AfterClose, func() {
-		command.Close.Send(c)
+		serializer.Close()
 		conn.Close()
+		if record != nil {
+			record.Close()
+		}
 	})
 
-	go heartbeat.RespondToPing(c)
+	go heartbeat.Heartbeat{
+		Interval:  heartbeatInterval,
+		MaxMissed: heartbeatMaxMissed,
+		OnDead: func() {
+			logger.Log("step", "heartbeat", "err", "no PONG received, reconnecting")
+			conn.Drop()
+		},
+	}.Run(context.Background(), c)
 
 	return c, command.Connect.Send(c)
 }
+
+// localDialer returns the dialer bound to the --bind address (nil if not set)
+func localDialer() (net.ContextDialer, error) {
+	if bindAddr == "" {
+		return nil, nil
+	}
+	ip := gonet.ParseIP(bindAddr)
+	if ip == nil {
+		return nil, fmt.Errorf("could not parse the bind address %q", bindAddr)
+	}
+	return &gonet.Dialer{LocalAddr: &gonet.TCPAddr{IP: ip}}, nil
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command"
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+// jsonStatusEvent is printed by status --watch on each change (in JSON mode)
+type jsonStatusEvent struct {
+	Time     time.Time          `json:"time"`
+	Receiver *chromecast.Status `json:"receiver,omitempty"`
+	Media    *media.Status      `json:"media,omitempty"`
+}
+
+// watchStatus prints the receiver and media statuses whenever they change, until interrupted.
+// The media app is followed when another one is launched.
+func watchStatus(logger chromecast.Logger, client chromecast.Client, status chromecast.Status) error {
+	subscriber, ok := client.(chromecast.Subscriber)
+	if !ok {
+		return fmt.Errorf("the client does not support the status updates")
+	}
+	receiverStatuses, stop := command.SubscribeStatus(subscriber)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		select {
+		case <-interrupt:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var (
+		app           *media.App
+		events        <-chan media.Status
+		transportID   string
+		lastReceiver  string
+		lastMediaByID = make(map[int]string)
+	)
+	defer func() {
+		if app != nil {
+			app.Close()
+		}
+	}()
+
+	onReceiver := func(st chromecast.Status) error {
+		if key := jsonKey(st); key != lastReceiver {
+			lastReceiver = key
+			if err := printStatusEvent(jsonStatusEvent{Time: time.Now(), Receiver: &st}); err != nil {
+				return err
+			}
+		}
+
+		destination, err := st.FirstDestinationSupporting(media.Namespace)
+		if err != nil {
+			destination = ""
+		}
+		if destination == transportID {
+			return nil
+		}
+		// the media app changed
+		transportID = destination
+		if app != nil {
+			app.Close()
+			app, events = nil, nil
+		}
+		if destination == "" {
+			return nil
+		}
+		if app, err = media.ConnectFromStatus(client, st); err != nil {
+			logger.Log("step", "watch", "transportId", destination, "err", err)
+			return nil
+		}
+		events = app.Events()
+		// the Events only receive the changes
+		go app.Status()
+		return nil
+	}
+
+	if err := onReceiver(status); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case st, ok := <-receiverStatuses:
+			if !ok {
+				return fmt.Errorf("the connection was closed")
+			}
+			if err := onReceiver(st); err != nil {
+				return err
+			}
+		case st, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			// the position changes continuously
+			withoutTime := st
+			withoutTime.CurrentTime = media.Seconds{}
+			if key := jsonKey(withoutTime); key != lastMediaByID[st.SessionID] {
+				lastMediaByID[st.SessionID] = key
+				if err := printStatusEvent(jsonStatusEvent{Time: time.Now(), Media: &st}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// jsonKey returns the JSON representation of v, to detect the changes
+func jsonKey(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// printStatusEvent prints the event on one line
+func printStatusEvent(e jsonStatusEvent) error {
+	if jsonOutput {
+		return printJSON(e)
+	}
+	prefix := e.Time.Format("15:04:05")
+	if e.Receiver != nil {
+		fmt.Println(prefix, "receiver:", receiverSummary(*e.Receiver))
+	}
+	if e.Media != nil {
+		line := playbackState(*e.Media)
+		if e.Media.IdleReason != "" {
+			line += " (" + string(e.Media.IdleReason) + ")"
+		}
+		fmt.Println(prefix, "media:", line)
+	}
+	return nil
+}
+
+// receiverSummary returns the running applications and the volume on one line
+func receiverSummary(st chromecast.Status) string {
+	var apps []string
+	for _, app := range st.Applications {
+		if app == nil {
+			continue
+		}
+		name := ""
+		if app.DisplayName != nil {
+			name = *app.DisplayName
+		}
+		if app.AppID != nil {
+			name += " [" + *app.AppID + "]"
+		}
+		if app.StatusText != nil && *app.StatusText != "" {
+			name += " " + *app.StatusText
+		}
+		apps = append(apps, strings.TrimSpace(name))
+	}
+	summary := "no application"
+	if len(apps) > 0 {
+		summary = strings.Join(apps, ", ")
+	}
+	if st.Volume != nil && st.Volume.Level != nil {
+		summary += fmt.Sprintf("; volume %.2f", *st.Volume.Level)
+		if st.Volume.Muted != nil && *st.Volume.Muted {
+			summary += " (muted)"
+		}
+	}
+	if !st.OnScreen() {
+		summary += "; TV in standby or on another input"
+	}
+	return summary
+}
@@ -1,11 +1,17 @@
 package command
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	chromecast "github.com/oliverpool/go-chromecast"
 )
 
+// LaunchPollInterval is the interval between the status requests, while waiting for a launched app
+const LaunchPollInterval = 500 * time.Millisecond
+
 type App struct {
 	Envelope chromecast.Envelope
 	Client   chromecast.Client
@@ -18,10 +24,10 @@ func LaunchAndConnect(client chromecast.Client, id string, statuses ...chromecas
 	}
 	app := st.AppWithID(id)
 	if app == nil {
-		return nil, fmt.Errorf("the launched app could not be found")
+		return nil, fmt.Errorf("the launched app could not be found: %w", chromecast.ErrAppNotFound)
 	}
 	if app.TransportId == nil {
-		return nil, fmt.Errorf("the app has no transportId")
+		return nil, chromecast.ErrNoTransport
 	}
 	return ConnectTo(client, *app.TransportId)
 }
@@ -39,6 +45,8 @@ func ConnectFromStatus(client chromecast.Client, st chromecast.Status, namespace
 	return a, nil
 }
 
+// ConnectTo returns an App sending to the destination
+// The CONNECT is sent explicitly, for the clients which do not manage the virtual connections
 func ConnectTo(client chromecast.Client, destination string) (*App, error) {
 	a := &App{
 		Envelope: chromecast.Envelope{
@@ -49,3 +57,58 @@ func ConnectTo(client chromecast.Client, destination string) (*App, error) {
 	}
 	return a, Connect.SendTo(client, destination)
 }
+
+// LaunchAndConnect launches the app, waits for its transport to support the namespace
+// and connects to it (waiting at most DefaultTimeout, see LaunchAndConnectCtx)
+func (l Launcher) LaunchAndConnect(appID, namespace string) (*App, error) {
+	ctx, cancel := defaultContext()
+	defer cancel()
+	return l.LaunchAndConnectCtx(ctx, appID, namespace)
+}
+
+// LaunchAndConnectCtx launches the app, waits until the status contains its session
+// with the namespace (the session or its transportId are not always present right after the launch)
+// and connects to it.
+// The Requester must be a chromecast.Client.
+func (l Launcher) LaunchAndConnectCtx(ctx context.Context, appID, namespace string) (*App, error) {
+	client, ok := l.Requester.(chromecast.Client)
+	if !ok {
+		return nil, fmt.Errorf("the requester must be a chromecast.Client to connect to the app")
+	}
+	app, err := l.LaunchAppCtx(ctx, appID, nil)
+	if errors.Is(err, chromecast.ErrAppNotFound) {
+		// the app is not always in the status right after the launch
+		app, err = nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not launch app: %w", err)
+	}
+	for app == nil || app.TransportId == nil || !supports(app, namespace) {
+		select {
+		case <-ctx.Done():
+			return nil, chromecast.ErrRequestTimeout
+		case <-time.After(LaunchPollInterval):
+		}
+		st, err := l.StatusCtx(ctx)
+		if err != nil {
+			return nil, err
+		}
+		app = st.AppWithID(appID)
+	}
+
+	a, err := ConnectTo(client, *app.TransportId)
+	if err != nil {
+		return nil, err
+	}
+	a.Envelope.Namespace = namespace
+	return a, nil
+}
+
+func supports(app *chromecast.ApplicationSession, namespace string) bool {
+	for _, ns := range app.Namespaces {
+		if ns != nil && ns.Name == namespace {
+			return true
+		}
+	}
+	return false
+}
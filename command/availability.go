@@ -0,0 +1,54 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// AppAvailability returns whether the apps are available on the chromecast (by app ID),
+// like the YouTube receiver (waiting at most DefaultTimeout)
+func AppAvailability(requester chromecast.Requester, appIDs ...string) (map[string]bool, error) {
+	ctx, cancel := defaultContext()
+	defer cancel()
+	return AppAvailabilityCtx(ctx, requester, appIDs...)
+}
+
+// AppAvailabilityCtx returns whether the apps are available on the chromecast (by app ID)
+// (chromecast.ErrRequestTimeout if ctx is done before)
+func AppAvailabilityCtx(ctx context.Context, requester chromecast.Requester, appIDs ...string) (map[string]bool, error) {
+	env := chromecast.Envelope{
+		Source:      DefaultSource,
+		Destination: DefaultDestination,
+		Namespace:   ReceiverNamespace,
+	}
+	pay := Map{
+		"type":  "GET_APP_AVAILABILITY",
+		"appId": appIDs,
+	}
+
+	payload, err := Request(ctx, requester, env, pay)
+	if err != nil {
+		return nil, err
+	}
+	if payload == nil {
+		return nil, fmt.Errorf("could not get app availability: %w", chromecast.ErrEmptyPayload)
+	}
+	if err = ResponseError(payload); err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Availability map[string]string `json:"availability"`
+	}
+	if err = json.Unmarshal(payload, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal into availability: %s", err)
+	}
+	available := make(map[string]bool, len(appIDs))
+	for _, id := range appIDs {
+		available[id] = response.Availability[id] == "APP_AVAILABLE"
+	}
+	return available, nil
+}
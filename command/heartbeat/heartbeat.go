@@ -1,17 +1,30 @@
 package heartbeat
 
 import (
+	"context"
+	"time"
+
 	"github.com/oliverpool/go-chromecast"
 	"github.com/oliverpool/go-chromecast/command"
 )
 
-func RespondToPing(client chromecast.Client) {
-	pingEnvelope := chromecast.Envelope{
-		Source:      "Tr@n$p0rt-0",
-		Destination: "Tr@n$p0rt-0",
-		Namespace:   "urn:x-cast:com.google.cast.tp.heartbeat",
-	}
+// Namespace of the heartbeat messages
+const Namespace = "urn:x-cast:com.google.cast.tp.heartbeat"
+
+var pingEnvelope = chromecast.Envelope{
+	Source:      "Tr@n$p0rt-0",
+	Destination: "Tr@n$p0rt-0",
+	Namespace:   Namespace,
+}
 
+var senderEnvelope = chromecast.Envelope{
+	Source:      command.DefaultSource,
+	Destination: command.DefaultDestination,
+	Namespace:   Namespace,
+}
+
+// RespondToPing answers the PINGs of the chromecast with a PONG (until the client is closed)
+func RespondToPing(client chromecast.Client) {
 	ch := make(chan []byte, 1)
 	client.Listen(pingEnvelope, "PING", ch)
 
@@ -19,3 +32,65 @@ func RespondToPing(client chromecast.Client) {
 		client.Send(pingEnvelope, command.Type("PONG"))
 	}
 }
+
+// Heartbeat keeps the connection alive by sending PINGs and answering the PINGs of the chromecast
+type Heartbeat struct {
+	// Interval between the PINGs (5s if 0)
+	Interval time.Duration
+	// MaxMissed is the number of PINGs without PONG after which the connection is considered dead (3 if 0)
+	MaxMissed int
+	// OnDead is called when the connection is considered dead (can be nil)
+	// The count of missed PONGs is then reset
+	OnDead func()
+}
+
+// Run sends the PINGs and answers the PINGs (until ctx is done or the client is closed)
+func (h Heartbeat) Run(ctx context.Context, client chromecast.Client) {
+	interval := h.Interval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	maxMissed := h.MaxMissed
+	if maxMissed <= 0 {
+		maxMissed = 3
+	}
+
+	pings := make(chan []byte, 1)
+	client.Listen(pingEnvelope, "PING", pings)
+	pongs := make(chan []byte, 1)
+	client.Listen(chromecast.Envelope{
+		Source:      senderEnvelope.Destination,
+		Destination: senderEnvelope.Source,
+		Namespace:   Namespace,
+	}, "PONG", pongs)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	missed := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-pings:
+			if !ok {
+				return
+			}
+			client.Send(pingEnvelope, command.Type("PONG"))
+		case _, ok := <-pongs:
+			if !ok {
+				return
+			}
+			missed = 0
+		case <-ticker.C:
+			if missed >= maxMissed {
+				missed = 0
+				if h.OnDead != nil {
+					h.OnDead()
+				}
+			}
+			missed++
+			client.Send(senderEnvelope, command.Type("PING"))
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package heartbeat_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/heartbeat"
+)
+
+// silentClient records the sent messages and never answers
+type silentClient struct {
+	mu        sync.Mutex
+	sent      []string
+	listeners map[string]chan<- []byte
+}
+
+func (c *silentClient) Listen(env chromecast.Envelope, responseType string, ch chan<- []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.listeners == nil {
+		c.listeners = make(map[string]chan<- []byte)
+	}
+	c.listeners[responseType] = ch
+}
+
+func (c *silentClient) Send(env chromecast.Envelope, payload interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, env.Source+">"+env.Destination)
+	return nil
+}
+
+func (c *silentClient) Request(env chromecast.Envelope, payload chromecast.IdentifiablePayload) (<-chan []byte, error) {
+	return nil, nil
+}
+
+func (c *silentClient) Close() error {
+	return nil
+}
+
+func TestHeartbeatDead(t *testing.T) {
+	client := &silentClient{}
+	dead := make(chan struct{}, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	go heartbeat.Heartbeat{
+		Interval:  time.Millisecond,
+		MaxMissed: 2,
+		OnDead: func() {
+			select {
+			case dead <- struct{}{}:
+			default:
+			}
+		},
+	}.Run(ctx, client)
+
+	select {
+	case <-dead:
+	case <-ctx.Done():
+		t.Fatal("the connection should have been reported dead")
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if len(client.sent) < 2 || client.sent[0] != "sender-0>receiver-0" {
+		t.Errorf("PINGs to the receiver expected, got %v", client.sent)
+	}
+}
+
+func TestHeartbeatAnswersPing(t *testing.T) {
+	client := &silentClient{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		heartbeat.Heartbeat{Interval: time.Hour}.Run(ctx, client)
+		close(done)
+	}()
+
+	for {
+		client.mu.Lock()
+		ping := client.listeners["PING"]
+		client.mu.Unlock()
+		if ping != nil {
+			ping <- []byte(`{"type":"PING"}`)
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	for {
+		client.mu.Lock()
+		n := len(client.sent)
+		client.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if client.sent[0] != "Tr@n$p0rt-0>Tr@n$p0rt-0" {
+		t.Errorf("a PONG on the transport envelope was expected, got %v", client.sent)
+	}
+}
@@ -1,33 +1,49 @@
 package command
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 
 	chromecast "github.com/oliverpool/go-chromecast"
 )
 
+// ReceiverNamespace is the namespace of the receiver (platform) messages
+const ReceiverNamespace = "urn:x-cast:com.google.cast.receiver"
+
 type Launcher struct {
 	Requester chromecast.Requester
 }
 
 func (l Launcher) statusRequest(pay chromecast.IdentifiablePayload) (st chromecast.Status, err error) {
+	ctx, cancel := defaultContext()
+	defer cancel()
+	return l.statusRequestCtx(ctx, pay)
+}
+
+func (l Launcher) statusRequestCtx(ctx context.Context, pay chromecast.IdentifiablePayload) (st chromecast.Status, err error) {
 	env := chromecast.Envelope{
 		Source:      DefaultSource,
 		Destination: DefaultDestination,
-		Namespace:   "urn:x-cast:com.google.cast.receiver",
+		Namespace:   ReceiverNamespace,
 	}
 
-	response, err := l.Requester.Request(env, pay)
+	payload, err := Request(ctx, l.Requester, env, pay)
 	if err != nil {
 		return st, err
 	}
-
-	payload := <-response
 	if payload == nil {
-		return st, fmt.Errorf("empty status payload")
+		return st, fmt.Errorf("could not get status: %w", chromecast.ErrEmptyPayload)
+	}
+	if err = ResponseError(payload); err != nil {
+		return st, err
 	}
 
+	return unmarshalStatus(payload)
+}
+
+func unmarshalStatus(payload []byte) (st chromecast.Status, err error) {
 	sr := chromecast.StatusResponse{
 		Status: &st,
 	}
@@ -40,15 +56,65 @@ func (l Launcher) statusRequest(pay chromecast.IdentifiablePayload) (st chromeca
 	return st, err
 }
 
+// SubscribeStatus returns a channel receiving the unsolicited receiver statuses
+// (when the volume is changed with the remote for instance), instead of polling
+// A slow receiver only misses intermediate statuses (the latest one is always delivered).
+// The channel is closed when stop is called or when the client is closed.
+func SubscribeStatus(subscriber chromecast.Subscriber) (statuses <-chan chromecast.Status, stop func()) {
+	payloads := make(chan []byte, 1)
+	subscriber.Subscribe(ReceiverNamespace, "RECEIVER_STATUS", payloads)
+
+	ch := make(chan chromecast.Status, 1)
+	go func() {
+		defer close(ch)
+		for payload := range payloads {
+			st, err := unmarshalStatus(payload)
+			if err != nil {
+				continue
+			}
+			// keep only the latest status
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- st
+		}
+	}()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			subscriber.Unsubscribe(ReceiverNamespace, "RECEIVER_STATUS", payloads)
+		})
+	}
+}
+
+// Status returns the receiver status (waiting at most DefaultTimeout)
 func (l Launcher) Status() (st chromecast.Status, err error) {
+	ctx, cancel := defaultContext()
+	defer cancel()
+	return l.StatusCtx(ctx)
+}
+
+// StatusCtx returns the receiver status (chromecast.ErrRequestTimeout if ctx is done before)
+func (l Launcher) StatusCtx(ctx context.Context) (st chromecast.Status, err error) {
 	pay := Map{
 		"type": "GET_STATUS",
 	}
-	return l.statusRequest(pay)
+	return l.statusRequestCtx(ctx, pay)
 }
 
 // Launch will launch the given app, except if it is found running in one of the optional statuses
+// (waiting at most DefaultTimeout)
 func (l Launcher) Launch(appID string, statuses ...chromecast.Status) (st chromecast.Status, err error) {
+	ctx, cancel := defaultContext()
+	defer cancel()
+	return l.LaunchCtx(ctx, appID, statuses...)
+}
+
+// LaunchCtx will launch the given app, except if it is found running in one of the optional statuses
+// (chromecast.ErrRequestTimeout if ctx is done before)
+func (l Launcher) LaunchCtx(ctx context.Context, appID string, statuses ...chromecast.Status) (st chromecast.Status, err error) {
 	for _, st := range statuses {
 		app := st.AppWithID(appID)
 		if app != nil {
@@ -59,9 +125,39 @@ func (l Launcher) Launch(appID string, statuses ...chromecast.Status) (st chrome
 		"type":  "LAUNCH",
 		"appId": appID,
 	}
-	return l.statusRequest(pay)
+	return l.statusRequestCtx(ctx, pay)
 }
 
+// LaunchApp launches the given app (even if it is already running), with optional customData,
+// and returns its application session (waiting at most DefaultTimeout)
+func (l Launcher) LaunchApp(appID string, customData interface{}) (*chromecast.ApplicationSession, error) {
+	ctx, cancel := defaultContext()
+	defer cancel()
+	return l.LaunchAppCtx(ctx, appID, customData)
+}
+
+// LaunchAppCtx launches the given app, with optional customData, and returns its application session
+// (chromecast.ErrRequestTimeout if ctx is done before)
+func (l Launcher) LaunchAppCtx(ctx context.Context, appID string, customData interface{}) (*chromecast.ApplicationSession, error) {
+	pay := Map{
+		"type":  "LAUNCH",
+		"appId": appID,
+	}
+	if customData != nil {
+		pay["customData"] = customData
+	}
+	st, err := l.statusRequestCtx(ctx, pay)
+	if err != nil {
+		return nil, err
+	}
+	app := st.AppWithID(appID)
+	if app == nil {
+		return nil, fmt.Errorf("the launched app could not be found: %w", chromecast.ErrAppNotFound)
+	}
+	return app, nil
+}
+
+// Stop stops the app in the foreground (see StopSession)
 func (l Launcher) Stop() (st chromecast.Status, err error) {
 	pay := Map{
 		"type": "STOP",
@@ -69,6 +165,32 @@ func (l Launcher) Stop() (st chromecast.Status, err error) {
 	return l.statusRequest(pay)
 }
 
+// QuitApp stops the app in the foreground, to return to the backdrop
+// (unlike closing the client, which only disconnects)
+// It returns the status unchanged if only the backdrop is running.
+func (l Launcher) QuitApp() (st chromecast.Status, err error) {
+	st, err = l.Status()
+	if err != nil {
+		return st, err
+	}
+	for _, app := range st.Applications {
+		if app == nil || app.SessionID == nil || (app.IsIdleScreen != nil && *app.IsIdleScreen) {
+			continue
+		}
+		return l.StopSession(*app.SessionID)
+	}
+	return st, nil
+}
+
+// StopSession stops only the app of the given session (ApplicationSession.SessionID)
+func (l Launcher) StopSession(sessionID string) (st chromecast.Status, err error) {
+	pay := Map{
+		"type":      "STOP",
+		"sessionId": sessionID,
+	}
+	return l.statusRequest(pay)
+}
+
 func (l Launcher) SetVolume(level float64) (st chromecast.Status, err error) {
 	vol := chromecast.Volume{
 		Level: &level,
@@ -110,6 +232,6 @@ func (a ampLauncher) SetVolume(level float64) error {
 }
 
 func (a ampLauncher) Quit() error {
-	_, err := a.Launcher.Stop()
+	_, err := a.Launcher.QuitApp()
 	return err
 }
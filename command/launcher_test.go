@@ -1,8 +1,172 @@
 package command_test
 
 import (
+	"encoding/json"
+	"testing"
+
 	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/client"
 	"github.com/oliverpool/go-chromecast/command"
+	"github.com/oliverpool/go-chromecast/log"
 )
 
 var _ chromecast.AmpController = command.Launcher{}.AmpController()
+
+// cannedRequester records the request and answers with the response
+type cannedRequester struct {
+	request  map[string]interface{}
+	response string
+}
+
+func (r *cannedRequester) Request(env chromecast.Envelope, payload chromecast.IdentifiablePayload) (<-chan []byte, error) {
+	b, _ := json.Marshal(payload)
+	json.Unmarshal(b, &r.request)
+	ch := make(chan []byte, 1)
+	ch <- []byte(r.response)
+	return ch, nil
+}
+
+func TestLaunchApp(t *testing.T) {
+	requester := &cannedRequester{
+		response: `{"type":"RECEIVER_STATUS","status":{"applications":[{"appId":"ABCD1234","sessionId":"s-1","transportId":"t-1"}]}}`,
+	}
+	app, err := command.Launcher{Requester: requester}.LaunchApp("ABCD1234", map[string]string{"user": "me"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *app.SessionID != "s-1" || *app.TransportId != "t-1" {
+		t.Errorf("unexpected application session: %+v", app)
+	}
+	if requester.request["type"] != "LAUNCH" || requester.request["appId"] != "ABCD1234" || requester.request["customData"] == nil {
+		t.Errorf("unexpected request: %v", requester.request)
+	}
+
+	requester.response = `{"type":"LAUNCH_ERROR","reason":"NOT_FOUND"}`
+	if _, err = (command.Launcher{Requester: requester}).LaunchApp("ABCD1234", nil); err != (chromecast.LaunchError{Reason: "NOT_FOUND"}) {
+		t.Errorf("a LaunchError was expected, got %v", err)
+	}
+}
+
+func TestAppAvailability(t *testing.T) {
+	requester := &cannedRequester{
+		response: `{"responseType":"GET_APP_AVAILABILITY","availability":{"233637DE":"APP_AVAILABLE","ABCD1234":"APP_UNAVAILABLE"}}`,
+	}
+	available, err := command.AppAvailability(requester, "233637DE", "ABCD1234", "CC1AD845")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !available["233637DE"] || available["ABCD1234"] || available["CC1AD845"] {
+		t.Errorf("unexpected availability: %v", available)
+	}
+	if ids, ok := requester.request["appId"].([]interface{}); !ok || len(ids) != 3 {
+		t.Errorf("the app IDs should have been requested, got %v", requester.request)
+	}
+}
+
+func TestStopSession(t *testing.T) {
+	requester := &cannedRequester{
+		response: `{"type":"RECEIVER_STATUS","status":{"applications":[{"appId":"E8C28D3C","sessionId":"backdrop"}]}}`,
+	}
+	st, err := command.Launcher{Requester: requester}.StopSession("media")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requester.request["type"] != "STOP" || requester.request["sessionId"] != "media" {
+		t.Errorf("unexpected request: %v", requester.request)
+	}
+	if st.AppWithID("E8C28D3C") == nil {
+		t.Errorf("the backdrop should still be running: %+v", st)
+	}
+}
+
+// launchingClient answers the LAUNCH with the launched status, and the next GET_STATUS with the transport
+type launchingClient struct {
+	cannedRequester
+	launched  string
+	connected []string
+}
+
+func (c *launchingClient) Request(env chromecast.Envelope, payload chromecast.IdentifiablePayload) (<-chan []byte, error) {
+	if c.request == nil {
+		c.response = c.launched
+	} else {
+		c.response = `{"type":"RECEIVER_STATUS","status":{"applications":[{"appId":"ABCD1234","sessionId":"s-1","transportId":"t-1","namespaces":[{"name":"urn:x-cast:com.example"}]}]}}`
+	}
+	return c.cannedRequester.Request(env, payload)
+}
+
+func (c *launchingClient) Send(env chromecast.Envelope, payload interface{}) error {
+	c.connected = append(c.connected, env.Destination)
+	return nil
+}
+
+func (c *launchingClient) Listen(env chromecast.Envelope, responseType string, ch chan<- []byte) {}
+
+func (c *launchingClient) Close() error {
+	return nil
+}
+
+func TestLaunchAndConnect(t *testing.T) {
+	cc := map[string]string{
+		"without transport": `{"type":"RECEIVER_STATUS","status":{"applications":[{"appId":"ABCD1234","sessionId":"s-1"}]}}`,
+		"without app":       `{"type":"RECEIVER_STATUS","status":{"applications":[]}}`,
+	}
+	for name, launched := range cc {
+		t.Run(name, func(t *testing.T) {
+			client := &launchingClient{launched: launched}
+			app, err := command.Launcher{Requester: client}.LaunchAndConnect("ABCD1234", "urn:x-cast:com.example")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if app.Envelope.Destination != "t-1" || app.Envelope.Namespace != "urn:x-cast:com.example" {
+				t.Errorf("unexpected envelope: %+v", app.Envelope)
+			}
+			if len(client.connected) != 1 || client.connected[0] != "t-1" {
+				t.Errorf("a CONNECT to the transport was expected, got %v", client.connected)
+			}
+		})
+	}
+}
+
+func TestSubscribeStatus(t *testing.T) {
+	serializer := &pipeSerializer{in: make(chan []byte, 1)}
+	c := client.New(serializer, log.NopLogger())
+	defer c.Close()
+
+	statuses, stop := command.SubscribeStatus(c)
+	serializer.in <- []byte(`{"type":"RECEIVER_STATUS","requestId":0,"status":{"volume":{"level":0.7}}}`)
+	st := <-statuses
+	if st.Volume == nil || *st.Volume.Level != 0.7 {
+		t.Errorf("unexpected status: %+v", st)
+	}
+
+	stop()
+	if _, ok := <-statuses; ok {
+		t.Error("the channel should be closed after stop")
+	}
+}
+
+// pipeSerializer receives the broadcasted receiver payloads of in
+type pipeSerializer struct {
+	in chan []byte
+}
+
+func (s *pipeSerializer) Receive() (chromecast.Envelope, []byte, error) {
+	return chromecast.Envelope{Source: "receiver-0", Destination: "*", Namespace: command.ReceiverNamespace}, <-s.in, nil
+}
+
+func (s *pipeSerializer) Send(env chromecast.Envelope, payload []byte) error {
+	return nil
+}
+
+func TestQuitApp(t *testing.T) {
+	requester := &cannedRequester{
+		response: `{"type":"RECEIVER_STATUS","status":{"applications":[{"appId":"E8C28D3C","sessionId":"backdrop","isIdleScreen":true},{"appId":"CC1AD845","sessionId":"media","isIdleScreen":false}]}}`,
+	}
+	if _, err := (command.Launcher{Requester: requester}).QuitApp(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requester.request["type"] != "STOP" || requester.request["sessionId"] != "media" {
+		t.Errorf("only the media app should be stopped, got %v", requester.request)
+	}
+}
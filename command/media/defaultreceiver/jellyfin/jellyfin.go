@@ -0,0 +1,283 @@
+// Package jellyfin loads the items of a Jellyfin server on the default receiver
+package jellyfin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+	"github.com/oliverpool/go-chromecast/command/media/defaultreceiver"
+)
+
+// Server of the items (the API key can be created in the dashboard of the server)
+type Server struct {
+	URL    string `json:"url"` // like http://192.168.1.10:8096
+	APIKey string `json:"api_key"`
+	UserID string `json:"user_id"` // to resume the playback of this user (optional)
+}
+
+// DefaultServer is used by the URLLoader
+// (if its URL is empty, the settings of the "jellyfin" loader are used, see media.LoaderConfig)
+var DefaultServer Server
+
+// defaultServer returns the DefaultServer or the configured one
+func defaultServer() (Server, error) {
+	if DefaultServer.URL != "" {
+		return DefaultServer, nil
+	}
+	var s Server
+	_, err := media.LoaderConfig("jellyfin", &s)
+	return s, err
+}
+
+// ticksPerSecond of the Jellyfin durations (100ns)
+const ticksPerSecond = 10000000
+
+type App struct {
+	*media.App
+}
+
+func LaunchAndConnect(client chromecast.Client, statuses ...chromecast.Status) (App, error) {
+	app, err := defaultreceiver.LaunchAndConnect(client, statuses...)
+	return App{app}, err
+}
+
+func (a App) Load(item media.Item, options ...media.Option) (<-chan []byte, error) {
+	return a.App.Load(item, options...)
+}
+
+func init() {
+	media.RegisterMatchingLoader("jellyfin", media.SitePriority, URLLoader, CanHandle)
+	media.RegisterResolver("jellyfin", Resolve)
+}
+
+// CanHandle returns true for the URLs of an item of the Jellyfin web app (if a server is configured)
+func CanHandle(rawurl string) bool {
+	if _, err := ExtractID(rawurl); err != nil {
+		return false
+	}
+	s, err := defaultServer()
+	return err == nil && s.URL != ""
+}
+
+func URLLoader(rawurl string, options ...media.Option) (func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error), error) {
+	item, resume, err := resolve(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if resume > 0 {
+		// the given options may override the resume position
+		options = append([]media.Option{media.Seek(resume)}, options...)
+	}
+	return func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error) {
+		app, err := LaunchAndConnect(client, statuses...)
+		if err != nil {
+			return nil, err
+		}
+		return app.Load(item, options...)
+	}, nil
+}
+
+// Resolve returns the item of the URL (to queue it, from the start)
+func Resolve(rawurl string) ([]media.Item, error) {
+	item, _, err := resolve(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return []media.Item{item}, nil
+}
+
+// resolve returns the item of the URL on the configured server, with the playback position of the user
+func resolve(rawurl string) (media.Item, time.Duration, error) {
+	id, err := ExtractID(rawurl)
+	if err != nil {
+		return media.Item{}, 0, err
+	}
+	server, err := defaultServer()
+	if err != nil {
+		return media.Item{}, 0, err
+	}
+	if server.URL == "" || server.APIKey == "" {
+		return media.Item{}, 0, fmt.Errorf("no jellyfin server configured (url and api key are required)")
+	}
+	return server.Item(id)
+}
+
+// ExtractID returns the item id of a URL of the Jellyfin web app
+// (like http://192.168.1.10:8096/web/index.html#!/details?id=abc&serverId=def)
+func ExtractID(rawurl string) (string, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", fmt.Errorf("could not parse url '%s': %v", rawurl, err)
+	}
+	if !strings.HasPrefix(u.Path, "/web/") {
+		return "", fmt.Errorf("unsupported path: %s", u.Path)
+	}
+	// the route of the web app is in the fragment: !/details?id=... (or /details?id=...)
+	route := strings.TrimPrefix(u.Fragment, "!")
+	if !strings.HasPrefix(route, "/details?") {
+		return "", fmt.Errorf("could not find the details of an item inside URL: %s", rawurl)
+	}
+	query, err := url.ParseQuery(strings.TrimPrefix(route, "/details?"))
+	if err != nil {
+		return "", fmt.Errorf("could not parse the route '%s': %v", u.Fragment, err)
+	}
+	id := query.Get("id")
+	if id == "" {
+		return "", fmt.Errorf("could not find the id of an item inside URL: %s", rawurl)
+	}
+	return id, nil
+}
+
+type item struct {
+	ID                string            `json:"Id"`
+	Name              string            `json:"Name"`
+	Type              string            `json:"Type"`
+	MediaType         string            `json:"MediaType"`
+	SeriesName        string            `json:"SeriesName"`
+	Album             string            `json:"Album"`
+	AlbumArtist       string            `json:"AlbumArtist"`
+	ParentIndexNumber int               `json:"ParentIndexNumber"`
+	IndexNumber       int               `json:"IndexNumber"`
+	PremiereDate      string            `json:"PremiereDate"`
+	ImageTags         map[string]string `json:"ImageTags"`
+	UserData          struct {
+		PlaybackPositionTicks int64 `json:"PlaybackPositionTicks"`
+	} `json:"UserData"`
+	MediaSources []struct {
+		ID                   string `json:"Id"`
+		Container            string `json:"Container"`
+		SupportsDirectStream bool   `json:"SupportsDirectStream"`
+	} `json:"MediaSources"`
+}
+
+// Item resolves the stream of the item and returns the playback position of the user
+func (s Server) Item(id string) (media.Item, time.Duration, error) {
+	path := "/Items/" + url.PathEscape(id)
+	if s.UserID != "" {
+		path = "/Users/" + url.PathEscape(s.UserID) + path
+	}
+	var it item
+	if err := s.getJSON(path, &it); err != nil {
+		return media.Item{}, 0, fmt.Errorf("could not get the item '%s': %v", id, err)
+	}
+	if len(it.MediaSources) == 0 {
+		return media.Item{}, 0, fmt.Errorf("no media source found for the item '%s'", id)
+	}
+	resume := time.Duration(it.UserData.PlaybackPositionTicks) * time.Second / ticksPerSecond
+	return it.mediaItem(s), resume, nil
+}
+
+// containerTypes are the containers which the receivers can play directly
+var containerTypes = map[string]string{
+	"mp4":  "video/mp4",
+	"m4v":  "video/mp4",
+	"webm": "video/webm",
+	"mp3":  "audio/mpeg",
+	"m4a":  "audio/mp4",
+	"aac":  "audio/aac",
+	"flac": "audio/flac",
+	"ogg":  "audio/ogg",
+}
+
+func (it item) mediaItem(s Server) media.Item {
+	source := it.MediaSources[0]
+	kind := "Videos"
+	if it.MediaType == "Audio" {
+		kind = "Audio"
+	}
+	query := url.Values{
+		"mediaSourceId": {source.ID},
+		"api_key":       {s.APIKey},
+	}
+
+	var contentID, contentType string
+	if ct, ok := containerTypes[source.Container]; ok && source.SupportsDirectStream {
+		query.Set("static", "true")
+		contentID = s.url("/"+kind+"/"+url.PathEscape(it.ID)+"/stream."+source.Container, query)
+		contentType = ct
+	} else {
+		// transcoded by the server
+		if kind == "Audio" {
+			query.Set("AudioCodec", "aac")
+		} else {
+			query.Set("VideoCodec", "h264")
+			query.Set("AudioCodec", "aac")
+		}
+		contentID = s.url("/"+kind+"/"+url.PathEscape(it.ID)+"/master.m3u8", query)
+		contentType = "application/x-mpegurl"
+	}
+
+	return media.Item{
+		ContentID:   contentID,
+		ContentType: contentType,
+		StreamType:  "BUFFERED",
+		Metadata:    it.metadata(s),
+	}
+}
+
+func (it item) metadata(s Server) interface{} {
+	var images []media.Image
+	if tag, ok := it.ImageTags["Primary"]; ok {
+		images = []media.Image{{URL: s.url("/Items/"+url.PathEscape(it.ID)+"/Images/Primary", url.Values{"tag": {tag}})}}
+	}
+	switch it.Type {
+	case "Episode":
+		return media.TvShowMediaMetadata{
+			SeriesTitle:     it.SeriesName,
+			Title:           it.Name,
+			Season:          it.ParentIndexNumber,
+			Episode:         it.IndexNumber,
+			Images:          images,
+			OriginalAirdate: it.PremiereDate,
+		}
+	case "Movie":
+		return media.MovieMediaMetadata{
+			Title:       it.Name,
+			Images:      images,
+			ReleaseDate: it.PremiereDate,
+		}
+	case "Audio":
+		return media.MusicTrackMediaMetadata{
+			Title:       it.Name,
+			AlbumName:   it.Album,
+			AlbumArtist: it.AlbumArtist,
+			TrackNumber: it.IndexNumber,
+			DiscNumber:  it.ParentIndexNumber,
+			Images:      images,
+		}
+	default:
+		return media.GenericMediaMetadata{
+			Title:       it.Name,
+			Images:      images,
+			ReleaseDate: it.PremiereDate,
+		}
+	}
+}
+
+func (s Server) url(path string, query url.Values) string {
+	return strings.TrimRight(s.URL, "/") + path + "?" + query.Encode()
+}
+
+func (s Server) getJSON(path string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(s.URL, "/")+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Emby-Token", s.APIKey)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
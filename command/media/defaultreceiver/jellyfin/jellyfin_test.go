@@ -0,0 +1,88 @@
+package jellyfin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func TestExtractID(t *testing.T) {
+	cc := []struct {
+		url string
+		id  string
+	}{
+		{"http://192.168.1.10:8096/web/index.html#!/details?id=abc123&serverId=def", "abc123"},
+		{"https://jellyfin.example.com/web/#/details?id=abc456&context=home", "abc456"},
+	}
+	for _, c := range cc {
+		id, err := ExtractID(c.url)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.url, err)
+		}
+		if id != c.id {
+			t.Errorf("%s: got id '%s' instead of '%s'", c.url, id, c.id)
+		}
+	}
+
+	for _, u := range []string{
+		"https://jellyfin.example.com/web/index.html#!/home.html",
+		"https://example.com/details?id=abc",
+	} {
+		if _, err := ExtractID(u); err == nil {
+			t.Errorf("%s: an error was expected", u)
+		}
+	}
+}
+
+func TestServerItem(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Emby-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/Users/u1/Items/ep1":
+			w.Write([]byte(`{"Id":"ep1","Name":"Pilot","Type":"Episode","MediaType":"Video","SeriesName":"Show","ParentIndexNumber":1,"IndexNumber":2,
+				"ImageTags":{"Primary":"t1"},"UserData":{"PlaybackPositionTicks":900000000},
+				"MediaSources":[{"Id":"src1","Container":"mkv","SupportsDirectStream":true}]}`))
+		case "/Items/mv1":
+			w.Write([]byte(`{"Id":"mv1","Name":"Movie","Type":"Movie","MediaType":"Video",
+				"MediaSources":[{"Id":"src2","Container":"mp4","SupportsDirectStream":true}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	item, resume, err := Server{URL: ts.URL, APIKey: "secret", UserID: "u1"}.Item("ep1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resume != 90*time.Second {
+		t.Errorf("unexpected resume position: %s", resume)
+	}
+	if item.ContentType != "application/x-mpegurl" || item.ContentID != ts.URL+"/Videos/ep1/master.m3u8?AudioCodec=aac&VideoCodec=h264&api_key=secret&mediaSourceId=src1" {
+		t.Errorf("a mkv should be transcoded to HLS, got %+v", item)
+	}
+	m, ok := item.Metadata.(media.TvShowMediaMetadata)
+	if !ok || m.SeriesTitle != "Show" || m.Season != 1 || m.Episode != 2 || len(m.Images) != 1 {
+		t.Errorf("unexpected metadata: %+v", item.Metadata)
+	}
+
+	item, resume, err = Server{URL: ts.URL, APIKey: "secret"}.Item("mv1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resume != 0 {
+		t.Errorf("unexpected resume position: %s", resume)
+	}
+	if item.ContentType != "video/mp4" || item.ContentID != ts.URL+"/Videos/mv1/stream.mp4?api_key=secret&mediaSourceId=src2&static=true" {
+		t.Errorf("a mp4 should be streamed directly, got %+v", item)
+	}
+	if _, ok := item.Metadata.(media.MovieMediaMetadata); !ok {
+		t.Errorf("unexpected metadata: %+v", item.Metadata)
+	}
+}
@@ -15,6 +15,17 @@ func LaunchAndConnect(client chromecast.Client, statuses ...chromecast.Status) (
 	return media.LaunchAndConnect(client, ID, statuses...)
 }
 
+func init() {
+	media.RegisterMatchingLoader("default", media.DefaultPriority, URLLoader, CanHandle)
+	media.RegisterResolver("default", Resolve)
+}
+
+// CanHandle returns true for the URLs with a supported content-type
+func CanHandle(rawurl string) bool {
+	_, err := ExtractType(rawurl)
+	return err == nil
+}
+
 func URLLoader(rawurl string, options ...media.Option) (func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error), error) {
 	contentType, err := ExtractType(rawurl)
 	if err != nil {
@@ -33,6 +44,19 @@ func URLLoader(rawurl string, options ...media.Option) (func(client chromecast.C
 	}, nil
 }
 
+// Resolve returns the item of the URL (to queue it)
+func Resolve(rawurl string) ([]media.Item, error) {
+	contentType, err := ExtractType(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return []media.Item{{
+		ContentID:   rawurl,
+		ContentType: contentType,
+		StreamType:  "BUFFERED",
+	}}, nil
+}
+
 func ExtractType(rawurl string) (string, error) {
 	u, err := url.Parse(rawurl)
 	if err != nil {
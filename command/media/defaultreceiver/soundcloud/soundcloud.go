@@ -0,0 +1,274 @@
+package soundcloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+	"github.com/oliverpool/go-chromecast/command/media/defaultreceiver"
+)
+
+const apiURL = "https://api-v2.soundcloud.com"
+
+type App struct {
+	*media.App
+}
+
+func LaunchAndConnect(client chromecast.Client, statuses ...chromecast.Status) (App, error) {
+	app, err := defaultreceiver.LaunchAndConnect(client, statuses...)
+	return App{app}, err
+}
+
+// Load the tracks (as a queue if there are many)
+func (a App) Load(items []media.Item, options ...media.Option) (<-chan []byte, error) {
+	if len(items) == 1 {
+		return a.App.Load(items[0], options...)
+	}
+	queue := make([]media.QueueItem, len(items))
+	for i, item := range items {
+		queue[i] = media.NewQueueItem(item)
+	}
+	return a.App.QueueLoad(queue, options...)
+}
+
+func init() {
+	media.RegisterMatchingLoader("soundcloud", media.SitePriority, URLLoader, CanHandle)
+	media.RegisterResolver("soundcloud", ExtractItems)
+}
+
+// hosts of the supported URLs
+var hosts = map[string]struct{}{
+	"www.soundcloud.com": struct{}{},
+	"soundcloud.com":     struct{}{},
+	"m.soundcloud.com":   struct{}{},
+}
+
+// CanHandle returns true for the URLs of a track or a playlist on SoundCloud
+func CanHandle(rawurl string) bool {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return false
+	}
+	_, ok := hosts[u.Host]
+	return ok
+}
+
+func URLLoader(rawurl string, options ...media.Option) (func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error), error) {
+	items, err := ExtractItems(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error) {
+		app, err := LaunchAndConnect(client, statuses...)
+		if err != nil {
+			return nil, err
+		}
+		return app.Load(items, options...)
+	}, nil
+}
+
+// ExtractItems resolves a track or a playlist URL to the items of its audio streams
+func ExtractItems(rawurl string) ([]media.Item, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse url '%s': %v", rawurl, err)
+	}
+	if _, ok := hosts[u.Host]; !ok {
+		return nil, fmt.Errorf("unsupported host: %s", u.Host)
+	}
+
+	clientID, err := fetchClientID()
+	if err != nil {
+		return nil, fmt.Errorf("could not find a client_id: %v", err)
+	}
+
+	var resolved resource
+	if err = getJSON(apiURL+"/resolve?"+url.Values{"url": {rawurl}, "client_id": {clientID}}.Encode(), &resolved); err != nil {
+		return nil, fmt.Errorf("could not resolve '%s': %v", rawurl, err)
+	}
+	tracks := []track{resolved.track}
+	if resolved.Kind == "playlist" {
+		tracks, err = completeTracks(resolved.Tracks, clientID)
+		if err != nil {
+			return nil, err
+		}
+	} else if resolved.Kind != "track" {
+		return nil, fmt.Errorf("unsupported kind: %s", resolved.Kind)
+	}
+
+	var items []media.Item
+	for _, t := range tracks {
+		item, err := t.item(clientID)
+		if err != nil {
+			// not streamable (like a preview only)
+			continue
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no streamable track found inside URL: %s", rawurl)
+	}
+	return items, nil
+}
+
+type resource struct {
+	Kind string `json:"kind"`
+	track
+	Tracks []track `json:"tracks"`
+}
+
+type track struct {
+	ID         int    `json:"id"`
+	Title      string `json:"title"`
+	ArtworkURL string `json:"artwork_url"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Media struct {
+		Transcodings []transcoding `json:"transcodings"`
+	} `json:"media"`
+}
+
+type transcoding struct {
+	URL    string `json:"url"`
+	Format struct {
+		Protocol string `json:"protocol"`
+		MimeType string `json:"mime_type"`
+	} `json:"format"`
+}
+
+// completeTracks fetches the tracks of a playlist which only have an ID
+func completeTracks(tracks []track, clientID string) ([]track, error) {
+	var ids []string
+	for _, t := range tracks {
+		if t.Title == "" {
+			ids = append(ids, strconv.Itoa(t.ID))
+		}
+	}
+	if len(ids) == 0 {
+		return tracks, nil
+	}
+	var fetched []track
+	if err := getJSON(apiURL+"/tracks?"+url.Values{"ids": {strings.Join(ids, ",")}, "client_id": {clientID}}.Encode(), &fetched); err != nil {
+		return nil, fmt.Errorf("could not fetch the tracks of the playlist: %v", err)
+	}
+	byID := make(map[int]track, len(fetched))
+	for _, t := range fetched {
+		byID[t.ID] = t
+	}
+	for i, t := range tracks {
+		if f, ok := byID[t.ID]; ok && t.Title == "" {
+			tracks[i] = f
+		}
+	}
+	return tracks, nil
+}
+
+// bestTranscoding prefers a progressive stream (supported by all the receivers)
+func bestTranscoding(transcodings []transcoding) (transcoding, bool) {
+	for _, protocol := range []string{"progressive", "hls"} {
+		for _, t := range transcodings {
+			if t.Format.Protocol == protocol && !strings.Contains(t.Format.MimeType, "ogg") {
+				return t, true
+			}
+		}
+	}
+	return transcoding{}, false
+}
+
+func (t track) metadata() media.MusicTrackMediaMetadata {
+	metadata := media.MusicTrackMediaMetadata{
+		Title:  t.Title,
+		Artist: t.User.Username,
+	}
+	if t.ArtworkURL != "" {
+		// the default artwork is 100x100
+		metadata.Images = []media.Image{{URL: strings.Replace(t.ArtworkURL, "-large.", "-t500x500.", 1)}}
+	}
+	return metadata
+}
+
+func (t track) item(clientID string) (media.Item, error) {
+	tr, ok := bestTranscoding(t.Media.Transcodings)
+	if !ok {
+		return media.Item{}, fmt.Errorf("no supported stream for the track %d", t.ID)
+	}
+	var stream struct {
+		URL string `json:"url"`
+	}
+	if err := getJSON(tr.URL+"?"+url.Values{"client_id": {clientID}}.Encode(), &stream); err != nil {
+		return media.Item{}, fmt.Errorf("could not get the stream of the track %d: %v", t.ID, err)
+	}
+	contentType := "audio/mpeg"
+	if tr.Format.Protocol == "hls" {
+		contentType = "application/x-mpegurl"
+	}
+	return media.Item{
+		ContentID:   stream.URL,
+		ContentType: contentType,
+		StreamType:  "BUFFERED",
+		Metadata:    t.metadata(),
+	}, nil
+}
+
+var scriptRegexp = regexp.MustCompile(`<script crossorigin src="([^"]+)"`)
+var clientIDRegexp = regexp.MustCompile(`client_id[:=]"?([0-9a-zA-Z]{32})`)
+
+// fetchClientID extracts the public client_id from the scripts of the website
+func fetchClientID() (string, error) {
+	page, err := get("https://soundcloud.com/")
+	if err != nil {
+		return "", err
+	}
+	scripts := scriptRegexp.FindAllStringSubmatch(page, -1)
+	// the client_id is usually in one of the last scripts
+	for i := len(scripts) - 1; i >= 0; i-- {
+		script, err := get(scripts[i][1])
+		if err != nil {
+			continue
+		}
+		if id := extractClientID(script); id != "" {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("not found in the %d scripts", len(scripts))
+}
+
+func extractClientID(script string) string {
+	if m := clientIDRegexp.FindStringSubmatch(script); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+func get(rawurl string) (string, error) {
+	resp, err := http.Get(rawurl)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status for '%s': %s", rawurl, resp.Status)
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	return string(b), err
+}
+
+func getJSON(rawurl string, v interface{}) error {
+	resp, err := http.Get(rawurl)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,60 @@
+package soundcloud
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestExtractClientID(t *testing.T) {
+	script := `var a=1;e.exports={client_id:"abcdefghijABCDEFGHIJ0123456789xy",env:"production"}`
+	if got := extractClientID(script); got != "abcdefghijABCDEFGHIJ0123456789xy" {
+		t.Errorf("unexpected client_id: '%s'", got)
+	}
+	if got := extractClientID("var a=1"); got != "" {
+		t.Errorf("no client_id expected, got '%s'", got)
+	}
+}
+
+func TestResolvedTrack(t *testing.T) {
+	body := `{"kind":"track","id":42,"title":"Song","artwork_url":"https://i1.sndcdn.com/artworks-000-large.jpg","user":{"username":"Band"},
+		"media":{"transcodings":[
+			{"url":"https://api-v2.soundcloud.com/media/42/hls-opus","format":{"protocol":"hls","mime_type":"audio/ogg; codecs=\"opus\""}},
+			{"url":"https://api-v2.soundcloud.com/media/42/hls","format":{"protocol":"hls","mime_type":"audio/mpeg"}},
+			{"url":"https://api-v2.soundcloud.com/media/42/progressive","format":{"protocol":"progressive","mime_type":"audio/mpeg"}}
+		]}}`
+	var r resource
+	if err := json.Unmarshal([]byte(body), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Kind != "track" || r.ID != 42 {
+		t.Fatalf("unexpected resource: %+v", r)
+	}
+
+	tr, ok := bestTranscoding(r.Media.Transcodings)
+	if !ok || tr.URL != "https://api-v2.soundcloud.com/media/42/progressive" {
+		t.Errorf("the progressive stream should be preferred, got %+v", tr)
+	}
+	tr, ok = bestTranscoding(r.Media.Transcodings[:2])
+	if !ok || tr.URL != "https://api-v2.soundcloud.com/media/42/hls" {
+		t.Errorf("the mp3 HLS stream should be preferred to opus, got %+v", tr)
+	}
+
+	m := r.metadata()
+	if m.Title != "Song" || m.Artist != "Band" || len(m.Images) != 1 || m.Images[0].URL != "https://i1.sndcdn.com/artworks-000-t500x500.jpg" {
+		t.Errorf("unexpected metadata: %+v", m)
+	}
+}
+
+func TestOnlineExtractItems(t *testing.T) {
+	if os.Getenv("TEST_ONLINE") == "" {
+		t.Skip("online test skipped")
+	}
+	items, err := ExtractItems("https://soundcloud.com/forss/flickermood")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].ContentID == "" {
+		t.Errorf("unexpected items: %+v", items)
+	}
+}
@@ -31,6 +31,26 @@ func (a App) Load(id string, options ...media.Option) (<-chan []byte, error) {
 	return a.App.Load(item, options...)
 }
 
+func init() {
+	media.RegisterMatchingLoader("tvnow", media.SitePriority, URLLoader, CanHandle)
+}
+
+// hosts of the supported URLs
+var hosts = map[string]struct{}{
+	"www.tvnow.de": struct{}{},
+	"tvnow.de":     struct{}{},
+}
+
+// CanHandle returns true for the URLs of a video on TVNOW
+func CanHandle(rawurl string) bool {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return false
+	}
+	_, ok := hosts[u.Host]
+	return ok
+}
+
 func URLLoader(rawurl string, options ...media.Option) (func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error), error) {
 	id, err := ExtractID(rawurl)
 	if err != nil {
@@ -50,11 +70,6 @@ func ExtractID(rawurl string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("could not parse url '%s': %v", rawurl, err)
 	}
-
-	hosts := map[string]struct{}{
-		"www.tvnow.de": struct{}{},
-		"tvnow.de":     struct{}{},
-	}
 	if _, ok := hosts[u.Host]; !ok {
 		return "", fmt.Errorf("unsupported host: %s", u.Host)
 	}
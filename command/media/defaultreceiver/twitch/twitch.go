@@ -0,0 +1,184 @@
+package twitch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+	"github.com/oliverpool/go-chromecast/command/media/defaultreceiver"
+)
+
+// clientID of the public web player
+const clientID = "kimne78kx3ncx6brgo4mv6wki5h1ko"
+
+const gqlURL = "https://gql.twitch.tv/gql"
+
+const accessTokenQuery = `query PlaybackAccessToken($login: String!, $isLive: Boolean!, $vodID: ID!, $isVod: Boolean!) {
+  streamPlaybackAccessToken(channelName: $login, params: {platform: "web", playerBackend: "mediaplayer", playerType: "site"}) @include(if: $isLive) { value signature }
+  videoPlaybackAccessToken(id: $vodID, params: {platform: "web", playerBackend: "mediaplayer", playerType: "site"}) @include(if: $isVod) { value signature }
+}`
+
+type App struct {
+	*media.App
+}
+
+func LaunchAndConnect(client chromecast.Client, statuses ...chromecast.Status) (App, error) {
+	app, err := defaultreceiver.LaunchAndConnect(client, statuses...)
+	return App{app}, err
+}
+
+// Load the HLS playlist (live channel or VOD)
+func (a App) Load(playlist string, live bool, options ...media.Option) (<-chan []byte, error) {
+	item := media.Item{
+		ContentID:   playlist,
+		ContentType: "application/x-mpegurl",
+		StreamType:  "BUFFERED",
+	}
+	if live {
+		item.StreamType = "LIVE"
+	}
+	return a.App.Load(item, options...)
+}
+
+func init() {
+	media.RegisterMatchingLoader("twitch", media.SitePriority, URLLoader, CanHandle)
+}
+
+// CanHandle returns true for the URLs of a channel or a video on Twitch
+func CanHandle(rawurl string) bool {
+	_, err := parseURL(rawurl)
+	return err == nil
+}
+
+func URLLoader(rawurl string, options ...media.Option) (func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error), error) {
+	v, err := parseURL(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	playlist, err := v.playlist()
+	if err != nil {
+		return nil, err
+	}
+	return func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error) {
+		app, err := LaunchAndConnect(client, statuses...)
+		if err != nil {
+			return nil, err
+		}
+		return app.Load(playlist, v.channel != "", options...)
+	}, nil
+}
+
+// video is either a live channel or a VOD
+type video struct {
+	channel string
+	vodID   string
+}
+
+func parseURL(rawurl string) (video, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return video{}, fmt.Errorf("could not parse url '%s': %v", rawurl, err)
+	}
+
+	hosts := map[string]struct{}{
+		"www.twitch.tv": struct{}{},
+		"twitch.tv":     struct{}{},
+		"m.twitch.tv":   struct{}{},
+	}
+	if _, ok := hosts[u.Host]; !ok {
+		return video{}, fmt.Errorf("unsupported host: %s", u.Host)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case len(parts) == 2 && parts[0] == "videos" && parts[1] != "":
+		return video{vodID: parts[1]}, nil
+	case len(parts) == 1 && parts[0] != "":
+		return video{channel: strings.ToLower(parts[0])}, nil
+	}
+	return video{}, fmt.Errorf("could not find a channel or a video inside URL: %s", rawurl)
+}
+
+type accessToken struct {
+	Value     string `json:"value"`
+	Signature string `json:"signature"`
+}
+
+// playlist returns the URL of the HLS playlist (with an access token)
+func (v video) playlist() (string, error) {
+	body, err := json.Marshal(v.tokenRequest())
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodPost, gqlURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Client-ID", clientID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("could not fetch the access token: %v", err)
+	}
+	defer resp.Body.Close()
+
+	token, err := extractToken(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("could not extract the access token: %v", err)
+	}
+	return v.usherURL(token), nil
+}
+
+func (v video) tokenRequest() map[string]interface{} {
+	return map[string]interface{}{
+		"query": accessTokenQuery,
+		"variables": map[string]interface{}{
+			"isLive": v.channel != "",
+			"login":  v.channel,
+			"isVod":  v.vodID != "",
+			"vodID":  v.vodID,
+		},
+	}
+}
+
+func extractToken(body io.Reader) (accessToken, error) {
+	var response struct {
+		Data struct {
+			Stream *accessToken `json:"streamPlaybackAccessToken"`
+			Video  *accessToken `json:"videoPlaybackAccessToken"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(body).Decode(&response); err != nil {
+		return accessToken{}, err
+	}
+	if len(response.Errors) > 0 {
+		return accessToken{}, fmt.Errorf("%s", response.Errors[0].Message)
+	}
+	switch {
+	case response.Data.Stream != nil:
+		return *response.Data.Stream, nil
+	case response.Data.Video != nil:
+		return *response.Data.Video, nil
+	}
+	return accessToken{}, fmt.Errorf("no access token (offline channel or unknown video?)")
+}
+
+func (v video) usherURL(token accessToken) string {
+	query := url.Values{
+		"sig":              {token.Signature},
+		"token":            {token.Value},
+		"allow_source":     {"true"},
+		"allow_audio_only": {"true"},
+	}
+	if v.channel != "" {
+		return "https://usher.ttvnw.net/api/channel/hls/" + url.PathEscape(v.channel) + ".m3u8?" + query.Encode()
+	}
+	return "https://usher.ttvnw.net/vod/" + url.PathEscape(v.vodID) + ".m3u8?" + query.Encode()
+}
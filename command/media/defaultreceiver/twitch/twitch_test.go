@@ -0,0 +1,63 @@
+package twitch
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestURLParsing(t *testing.T) {
+	cc := []struct {
+		url      string
+		expected video
+	}{
+		{url: "https://www.twitch.tv/SomeChannel", expected: video{channel: "somechannel"}},
+		{url: "https://m.twitch.tv/somechannel/", expected: video{channel: "somechannel"}},
+		{url: "https://www.twitch.tv/videos/123456789?t=1h2m", expected: video{vodID: "123456789"}},
+	}
+	for _, c := range cc {
+		got, err := parseURL(c.url)
+		if err != nil {
+			t.Errorf("got unexpected error for '%s': %v", c.url, err)
+		}
+		if got != c.expected {
+			t.Errorf("got '%+v', expected '%+v' for '%s'", got, c.expected, c.url)
+		}
+	}
+
+	for _, rawurl := range []string{"https://www.youtube.com/somechannel", "https://www.twitch.tv/", "https://www.twitch.tv/somechannel/clip/abc"} {
+		if _, err := parseURL(rawurl); err == nil {
+			t.Errorf("an error was expected for '%s'", rawurl)
+		}
+	}
+}
+
+func TestTokenParsing(t *testing.T) {
+	body := strings.NewReader(`{"data":{"streamPlaybackAccessToken":{"value":"{\"channel\":\"somechannel\"}","signature":"abc123","__typename":"PlaybackAccessToken"}}}`)
+	token, err := extractToken(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := video{channel: "somechannel"}.usherURL(token)
+	expected := "https://usher.ttvnw.net/api/channel/hls/somechannel.m3u8?allow_audio_only=true&allow_source=true&sig=abc123&token=%7B%22channel%22%3A%22somechannel%22%7D"
+	if got != expected {
+		t.Errorf("got '%s', expected '%s'", got, expected)
+	}
+
+	if _, err = extractToken(strings.NewReader(`{"data":{"streamPlaybackAccessToken":null}}`)); err == nil {
+		t.Error("an error was expected for an offline channel")
+	}
+}
+
+func TestOnlinePlaylist(t *testing.T) {
+	if os.Getenv("TEST_ONLINE") == "" {
+		t.Skip("online test skipped")
+	}
+	got, err := video{vodID: "1"}.playlist()
+	if err != nil {
+		t.Errorf("got unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(got, "https://usher.ttvnw.net/vod/1.m3u8?") {
+		t.Errorf("unexpected playlist: %s", got)
+	}
+}
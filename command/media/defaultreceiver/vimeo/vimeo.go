@@ -33,6 +33,16 @@ func (a App) Load(id string, options ...media.Option) (<-chan []byte, error) {
 	return a.App.Load(item, options...)
 }
 
+func init() {
+	media.RegisterMatchingLoader("default.vimeo", media.DefaultPriority+1, URLLoader, CanHandle)
+}
+
+// CanHandle returns true for the http(s) URLs (the page may embed a vimeo video)
+func CanHandle(rawurl string) bool {
+	u, err := url.Parse(rawurl)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
+
 func URLLoader(rawurl string, options ...media.Option) (func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error), error) {
 	iframe, err := ExtractIframe(rawurl)
 	if err != nil {
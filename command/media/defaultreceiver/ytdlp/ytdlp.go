@@ -0,0 +1,155 @@
+// Package ytdlp resolves the URLs of (nearly) any video website with yt-dlp, if it is installed
+package ytdlp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+	"github.com/oliverpool/go-chromecast/command/media/defaultreceiver"
+)
+
+// Command to run (looked up in the PATH)
+var Command = "yt-dlp"
+
+// Format selected by yt-dlp: a single file (video and audio) over http(s) is preferred,
+// since the receivers can not merge separate streams
+var Format = "best[protocol^=http][vcodec!=none][acodec!=none]/best[protocol^=m3u8]/best"
+
+type App struct {
+	*media.App
+}
+
+func LaunchAndConnect(client chromecast.Client, statuses ...chromecast.Status) (App, error) {
+	app, err := defaultreceiver.LaunchAndConnect(client, statuses...)
+	return App{app}, err
+}
+
+func (a App) Load(item media.Item, options ...media.Option) (<-chan []byte, error) {
+	return a.App.Load(item, options...)
+}
+
+func init() {
+	media.RegisterMatchingLoader("ytdlp", media.DefaultPriority-1, URLLoader, CanHandle)
+	media.RegisterResolver("ytdlp", Resolve)
+}
+
+// CanHandle returns true for the http(s) URLs, if yt-dlp is installed
+func CanHandle(rawurl string) bool {
+	u, err := url.Parse(rawurl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	_, err = exec.LookPath(Command)
+	return err == nil
+}
+
+func URLLoader(rawurl string, options ...media.Option) (func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error), error) {
+	item, err := ExtractItem(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error) {
+		app, err := LaunchAndConnect(client, statuses...)
+		if err != nil {
+			return nil, err
+		}
+		return app.Load(item, options...)
+	}, nil
+}
+
+// Resolve returns the item extracted by yt-dlp (to queue it)
+func Resolve(rawurl string) ([]media.Item, error) {
+	item, err := ExtractItem(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return []media.Item{item}, nil
+}
+
+// ExtractItem runs yt-dlp to get the direct URL of the media and its metadata
+func ExtractItem(rawurl string) (media.Item, error) {
+	path, err := exec.LookPath(Command)
+	if err != nil {
+		return media.Item{}, fmt.Errorf("%s is not installed: %v", Command, err)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(path, "-j", "--no-playlist", "-f", Format, rawurl)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		return media.Item{}, fmt.Errorf("%s could not resolve '%s': %v (%s)", Command, rawurl, err, strings.TrimSpace(stderr.String()))
+	}
+	return parseInfo(&stdout)
+}
+
+type info struct {
+	URL       string `json:"url"`
+	Ext       string `json:"ext"`
+	Protocol  string `json:"protocol"`
+	IsLive    bool   `json:"is_live"`
+	Title     string `json:"title"`
+	Uploader  string `json:"uploader"`
+	Thumbnail string `json:"thumbnail"`
+}
+
+func parseInfo(r io.Reader) (media.Item, error) {
+	var i info
+	if err := json.NewDecoder(r).Decode(&i); err != nil {
+		return media.Item{}, fmt.Errorf("could not parse the output of %s: %v", Command, err)
+	}
+	if i.URL == "" {
+		return media.Item{}, fmt.Errorf("%s did not return a direct URL", Command)
+	}
+	contentType := contentType(i)
+	if contentType == "" {
+		return media.Item{}, fmt.Errorf("unsupported format: %s (%s)", i.Ext, i.Protocol)
+	}
+
+	item := media.Item{
+		ContentID:   i.URL,
+		ContentType: contentType,
+		StreamType:  "BUFFERED",
+	}
+	if i.IsLive {
+		item.StreamType = "LIVE"
+	}
+	metadata := media.GenericMediaMetadata{
+		Title:    i.Title,
+		Subtitle: i.Uploader,
+	}
+	if i.Thumbnail != "" {
+		metadata.Images = []media.Image{{URL: i.Thumbnail}}
+	}
+	item.Metadata = metadata
+	return item, nil
+}
+
+func contentType(i info) string {
+	if strings.HasPrefix(i.Protocol, "m3u8") {
+		return "application/x-mpegurl"
+	}
+	if i.Protocol == "http_dash_segments" {
+		return "application/dash+xml"
+	}
+	switch i.Ext {
+	case "mp4":
+		return "video/mp4"
+	case "webm":
+		return "video/webm"
+	case "m4a":
+		return "audio/mp4"
+	case "mp3":
+		return "audio/mpeg"
+	case "ogg", "opus":
+		return "audio/ogg"
+	default:
+		return ""
+	}
+}
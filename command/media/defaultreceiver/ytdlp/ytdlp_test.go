@@ -0,0 +1,36 @@
+package ytdlp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func TestParseInfo(t *testing.T) {
+	output := `{"id":"abc","title":"A video","uploader":"Someone","thumbnail":"https://example.com/thumb.jpg","url":"https://cdn.example.com/video.mp4?sig=1","ext":"mp4","protocol":"https","is_live":false}`
+	item, err := parseInfo(strings.NewReader(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ContentID != "https://cdn.example.com/video.mp4?sig=1" || item.ContentType != "video/mp4" || item.StreamType != "BUFFERED" {
+		t.Errorf("unexpected item: %+v", item)
+	}
+	metadata, ok := item.Metadata.(media.GenericMediaMetadata)
+	if !ok || metadata.Title != "A video" || metadata.Subtitle != "Someone" || len(metadata.Images) != 1 {
+		t.Errorf("unexpected metadata: %+v", item.Metadata)
+	}
+
+	live := `{"url":"https://cdn.example.com/live/index.m3u8","ext":"mp4","protocol":"m3u8_native","is_live":true}`
+	item, err = parseInfo(strings.NewReader(live))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ContentType != "application/x-mpegurl" || item.StreamType != "LIVE" {
+		t.Errorf("unexpected live item: %+v", item)
+	}
+
+	if _, err = parseInfo(strings.NewReader(`{"url":"https://cdn.example.com/video.flv","ext":"flv","protocol":"https"}`)); err == nil {
+		t.Error("an unsupported format should be rejected")
+	}
+}
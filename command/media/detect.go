@@ -0,0 +1,142 @@
+package media
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/oliverpool/go-chromecast/command"
+)
+
+// DetectTimeout of the requests made by AutoDetect
+const DetectTimeout = 5 * time.Second
+
+// maxManifestSize read to detect a live stream
+const maxManifestSize = 1 << 20
+
+const (
+	hlsContentType  = "application/x-mpegurl"
+	dashContentType = "application/dash+xml"
+)
+
+// AutoDetect fills the ContentType (and StreamType) of the loaded item if they are empty,
+// by requesting the ContentID URL (HEAD, and GET of the HLS/DASH manifests to detect live streams)
+// The item is left unchanged if the detection fails.
+func AutoDetect() Option {
+	return func(c command.Map) {
+		item, ok := c["media"].(Item)
+		if !ok || item.ContentType != "" {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), DetectTimeout)
+		defer cancel()
+		contentType, streamType, err := DetectContentType(ctx, http.DefaultClient, item.ContentID)
+		if err != nil {
+			return
+		}
+		item.ContentType = contentType
+		if item.StreamType == "" {
+			item.StreamType = streamType
+		}
+		c["media"] = item
+	}
+}
+
+// DetectContentType returns the content type of the URL and its stream type (BUFFERED or LIVE)
+func DetectContentType(ctx context.Context, client *http.Client, rawurl string) (contentType, streamType string, err error) {
+	req, err := http.NewRequest(http.MethodHead, rawurl, nil)
+	if err != nil {
+		return "", "", err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", "", fmt.Errorf("could not request the content type: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", "", fmt.Errorf("could not request the content type: %s", resp.Status)
+	}
+	contentType, _, err = mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return "", "", fmt.Errorf("could not parse the content type: %w", err)
+	}
+
+	switch contentType {
+	case "application/vnd.apple.mpegurl", "audio/mpegurl", "audio/x-mpegurl", hlsContentType:
+		contentType = hlsContentType
+	case dashContentType:
+	default:
+		return contentType, "BUFFERED", nil
+	}
+	live, err := isLiveManifest(ctx, client, rawurl, contentType, true)
+	if err != nil || !live {
+		return contentType, "BUFFERED", nil
+	}
+	return contentType, "LIVE", nil
+}
+
+// isLiveManifest returns true for a DASH manifest of type dynamic, or a HLS playlist without ENDLIST
+// (the first variant of a HLS master playlist is checked if follow is set)
+func isLiveManifest(ctx context.Context, client *http.Client, rawurl, contentType string, follow bool) (bool, error) {
+	req, err := http.NewRequest(http.MethodGet, rawurl, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return false, fmt.Errorf("could not get the manifest: %s", resp.Status)
+	}
+	body := io.LimitReader(resp.Body, maxManifestSize)
+
+	if contentType == dashContentType {
+		manifest, err := ioutil.ReadAll(body)
+		if err != nil {
+			return false, err
+		}
+		return strings.Contains(string(manifest), `type="dynamic"`), nil
+	}
+
+	var variant string
+	master := false
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-ENDLIST"):
+			return false, nil
+		case strings.HasPrefix(line, "#EXT-X-STREAM-INF"):
+			master = true
+		case master && variant == "" && line != "" && !strings.HasPrefix(line, "#"):
+			variant = line
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return false, err
+	}
+	if !master {
+		return true, nil
+	}
+	if variant == "" || !follow {
+		return false, nil
+	}
+	base, err := url.Parse(rawurl)
+	if err != nil {
+		return false, err
+	}
+	ref, err := url.Parse(variant)
+	if err != nil {
+		return false, err
+	}
+	return isLiveManifest(ctx, client, base.ResolveReference(ref).String(), contentType, false)
+}
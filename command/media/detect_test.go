@@ -0,0 +1,57 @@
+package media_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/oliverpool/go-chromecast/command"
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func TestDetectContentType(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/movie", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "video/mp4")
+	})
+	mux.HandleFunc("/live/master", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+		w.Write([]byte("#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=1280000\nlow.m3u8\n"))
+	})
+	mux.HandleFunc("/live/low.m3u8", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+		w.Write([]byte("#EXTM3U\n#EXTINF:10,\nsegment1.ts\n"))
+	})
+	mux.HandleFunc("/vod.mpd", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/dash+xml")
+		w.Write([]byte(`<MPD type="static"></MPD>`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cases := []struct {
+		path                    string
+		contentType, streamType string
+	}{
+		{"/movie", "video/mp4", "BUFFERED"},
+		{"/live/master", "application/x-mpegurl", "LIVE"},
+		{"/vod.mpd", "application/dash+xml", "BUFFERED"},
+	}
+	for _, c := range cases {
+		contentType, streamType, err := media.DetectContentType(context.Background(), server.Client(), server.URL+c.path)
+		if err != nil {
+			t.Errorf("%s: %v", c.path, err)
+			continue
+		}
+		if contentType != c.contentType || streamType != c.streamType {
+			t.Errorf("%s: got %s %s, expected %s %s", c.path, contentType, streamType, c.contentType, c.streamType)
+		}
+	}
+
+	payload := command.Map{"media": media.Item{ContentID: server.URL + "/movie"}}
+	media.AutoDetect()(payload)
+	if item := payload["media"].(media.Item); item.ContentType != "video/mp4" || item.StreamType != "BUFFERED" {
+		t.Errorf("the item should have been completed, got %+v", item)
+	}
+}
@@ -0,0 +1,75 @@
+package media
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/oliverpool/go-chromecast/command"
+)
+
+// Protection systems supported by the CAF receivers
+const (
+	Widevine  = "widevine"
+	PlayReady = "playready"
+	ClearKey  = "clearkey"
+)
+
+// DRM describes how the receiver should get the license of a protected stream
+// It is sent in the customData of the media, where the CAF receivers look for it
+// (to configure the playbackConfig of the player)
+type DRM struct {
+	LicenseURL string `json:"licenseUrl"`
+	// ProtectionSystem like Widevine (guessed by the receiver if empty)
+	ProtectionSystem string `json:"protectionSystem,omitempty"`
+	// LicenseHeaders are added to the license requests (like an authorization token)
+	LicenseHeaders map[string]string `json:"licenseHeaders,omitempty"`
+}
+
+// WithDRM sets the license information of the loaded item
+func WithDRM(drm DRM) Option {
+	return func(c command.Map) {
+		if item, ok := c["media"].(Item); ok {
+			item.DRM = &drm
+			c["media"] = item
+		}
+	}
+}
+
+func (i Item) MarshalJSON() ([]byte, error) {
+	type alias Item
+	if i.DRM == nil {
+		return json.Marshal(alias(i))
+	}
+	data, err := drmCustomData(i.CustomData, *i.DRM)
+	if err != nil {
+		return nil, err
+	}
+	i.CustomData = data
+	return json.Marshal(alias(i))
+}
+
+// drmCustomData adds the fields of the DRM to the custom data (which must be a JSON object)
+func drmCustomData(customData interface{}, drm DRM) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if customData != nil {
+		b, err := json.Marshal(customData)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(b, &fields); err != nil {
+			return nil, fmt.Errorf("the customData must be an object to add the DRM: %w", err)
+		}
+	}
+	b, err := json.Marshal(drm)
+	if err != nil {
+		return nil, err
+	}
+	var drmFields map[string]json.RawMessage
+	if err = json.Unmarshal(b, &drmFields); err != nil {
+		return nil, err
+	}
+	for k, v := range drmFields {
+		fields[k] = v
+	}
+	return fields, nil
+}
@@ -0,0 +1,78 @@
+package media
+
+// eventsBuffer is the number of statuses kept for a slow reader of Events
+// (the oldest ones are dropped)
+const eventsBuffer = 4
+
+// Events returns a channel receiving the media statuses, as soon as they are received
+// (unsolicited MEDIA_STATUS as well as the responses to the requests), instead of polling LatestStatus.
+// It starts UpdateStatus if needed.
+// The channel is closed on Close.
+func (a *App) Events() <-chan Status {
+	ch := make(chan Status, eventsBuffer)
+
+	a.mu.Lock()
+	if a.eventsClosed {
+		a.mu.Unlock()
+		close(ch)
+		return ch
+	}
+	a.events = append(a.events, ch)
+	start := !a.updating
+	a.updating = true
+	a.mu.Unlock()
+
+	if start {
+		go a.UpdateStatus()
+	}
+	return ch
+}
+
+// stopEvents closes a channel returned by Events
+func (a *App) stopEvents(ch <-chan Status) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, c := range a.events {
+		if c == ch {
+			a.events = append(a.events[:i], a.events[i+1:]...)
+			close(c)
+			return
+		}
+	}
+}
+
+// Close closes the channels returned by Events
+func (a *App) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.eventsClosed {
+		return nil
+	}
+	a.eventsClosed = true
+	for _, ch := range a.events {
+		close(ch)
+	}
+	a.events = nil
+	return nil
+}
+
+// publish forwards the statuses to the Events channels (a.mu must be held)
+func (a *App) publish(st []Status) {
+	for _, ch := range a.events {
+		for _, s := range st {
+			select {
+			case ch <- s:
+			default:
+				// drop the oldest status
+				select {
+				case <-ch:
+				default:
+				}
+				select {
+				case ch <- s:
+				default:
+				}
+			}
+		}
+	}
+}
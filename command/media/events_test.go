@@ -0,0 +1,74 @@
+package media_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func TestEvents(t *testing.T) {
+	client := &rebootedClient{transportID: "t1", sessionID: 3}
+	transportID := "t1"
+	app, err := media.ConnectFromStatus(client, chromecast.Status{
+		Applications: []*chromecast.ApplicationSession{{
+			TransportId: &transportID,
+			Namespaces:  []*chromecast.Namespace{{Name: media.Namespace}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := app.Events()
+
+	if _, err = app.Status(); err != nil {
+		t.Fatal(err)
+	}
+	st := <-events
+	if st.SessionID != 3 || st.PlayerState != media.Playing {
+		t.Errorf("unexpected status: %+v", st)
+	}
+
+	app.Close()
+	if _, ok := <-events; ok {
+		t.Error("the events channel should be closed")
+	}
+	if _, ok := <-app.Events(); ok {
+		t.Error("the events channel should be closed after Close")
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	client := &rebootedClient{transportID: "t1", sessionID: 3}
+	transportID := "t1"
+	app, err := media.ConnectFromStatus(client, chromecast.Status{
+		Applications: []*chromecast.ApplicationSession{{
+			TransportId: &transportID,
+			Namespaces:  []*chromecast.Namespace{{Name: media.Namespace}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = app.Status(); err != nil {
+		t.Fatal(err)
+	}
+	session, err := app.CurrentSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if st, err := session.WaitFor(ctx, media.Playing); err != nil || st.SessionID != 3 {
+		t.Errorf("the session should already be playing, got %+v (%v)", st, err)
+	}
+	if _, err := session.WaitFor(ctx, media.Finished); err != context.DeadlineExceeded {
+		t.Errorf("the wait should have timed out, got %v", err)
+	}
+	if media.Ended.Matches(media.Status{PlayerState: media.Playing}) || !media.Ended.Matches(media.Status{PlayerState: media.Idle, IdleReason: media.IdleError}) {
+		t.Error("Ended should only match the finished or errored media")
+	}
+}
@@ -0,0 +1,35 @@
+package media_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func TestLiveSeekableRange(t *testing.T) {
+	payload := `{"mediaSessionId":1,"currentTime":95.5,"media":{"streamType":"LIVE","duration":null},"liveSeekableRange":{"start":30,"end":100,"isMovingWindow":true}}`
+	var st media.Status
+	if err := json.Unmarshal([]byte(payload), &st); err != nil {
+		t.Fatal(err)
+	}
+	if !st.IsLive() {
+		t.Error("the status should be live")
+	}
+	r := st.LiveSeekableRange
+	if r == nil {
+		t.Fatal("the seekable range should be parsed")
+	}
+	if r.Start.Duration != 30*time.Second || r.End.Duration != 100*time.Second || !r.IsMovingWindow {
+		t.Errorf("unexpected seekable range: %+v", *r)
+	}
+
+	var buffered media.Status
+	if err := json.Unmarshal([]byte(`{"mediaSessionId":1,"media":{"streamType":"BUFFERED","duration":120}}`), &buffered); err != nil {
+		t.Fatal(err)
+	}
+	if buffered.IsLive() {
+		t.Error("a buffered status should not be live")
+	}
+}
@@ -0,0 +1,35 @@
+package media
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+var (
+	loaderConfigsMu sync.Mutex
+	loaderConfigs   map[string]json.RawMessage
+)
+
+// SetLoaderConfigs sets the settings of the loaders by name (usually read from a configuration file),
+// like {"vimeo": {"access_token": "..."}}
+func SetLoaderConfigs(configs map[string]json.RawMessage) {
+	loaderConfigsMu.Lock()
+	defer loaderConfigsMu.Unlock()
+	loaderConfigs = configs
+}
+
+// LoaderConfig decodes the settings of the loader into v (usually called by the URLLoader).
+// It returns false if the loader has no settings.
+func LoaderConfig(name string, v interface{}) (bool, error) {
+	loaderConfigsMu.Lock()
+	raw, ok := loaderConfigs[name]
+	loaderConfigsMu.Unlock()
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("could not parse the settings of the loader '%s': %w", name, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,31 @@
+package media_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func TestLoaderConfig(t *testing.T) {
+	defer media.SetLoaderConfigs(nil)
+	media.SetLoaderConfigs(map[string]json.RawMessage{
+		"test.config":  json.RawMessage(`{"token":"secret"}`),
+		"test.invalid": json.RawMessage(`[]`),
+	})
+
+	var settings struct {
+		Token string `json:"token"`
+	}
+	ok, err := media.LoaderConfig("test.config", &settings)
+	if !ok || err != nil || settings.Token != "secret" {
+		t.Errorf("unexpected settings: %+v (%v, %v)", settings, ok, err)
+	}
+
+	if ok, err = media.LoaderConfig("test.missing", &settings); ok || err != nil {
+		t.Errorf("a missing loader should have no settings (%v, %v)", ok, err)
+	}
+	if _, err = media.LoaderConfig("test.invalid", &settings); err == nil {
+		t.Error("an error was expected for invalid settings")
+	}
+}
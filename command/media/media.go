@@ -1,6 +1,7 @@
 package media
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"sync"
@@ -19,6 +20,15 @@ type App struct {
 
 	mu           sync.Mutex
 	latestStatus []Status
+	statusTime   time.Time
+	updating     bool
+	renamed      map[int]int
+	// destination re-discovered by Restore (the Envelope is read without lock)
+	destination  string
+	events       []chan Status
+	eventsClosed bool
+	inflight     *statusCall
+	pushedAt     time.Time
 }
 
 func LaunchAndConnect(client chromecast.Client, id string, statuses ...chromecast.Status) (*App, error) {
@@ -27,9 +37,7 @@ func LaunchAndConnect(client chromecast.Client, id string, statuses ...chromecas
 		return nil, err
 	}
 	a.Envelope.Namespace = Namespace
-	return &App{
-		App: a,
-	}, nil
+	return newApp(a), nil
 }
 
 func ConnectFromStatus(client chromecast.Client, st chromecast.Status) (*App, error) {
@@ -37,28 +45,99 @@ func ConnectFromStatus(client chromecast.Client, st chromecast.Status) (*App, er
 	if err != nil {
 		return nil, err
 	}
-	return &App{
-		App: a,
-	}, nil
+	return newApp(a), nil
+}
+
+// FromTransport returns the App communicating through the transport, which fills
+// the source and destination of the envelopes (like a client.Transport)
+func FromTransport(transport chromecast.Client) *App {
+	return newApp(&command.App{
+		Envelope: chromecast.Envelope{Namespace: Namespace},
+		Client:   transport,
+	})
 }
 
 type Item struct {
 	ContentID   string `json:"contentId"`
 	StreamType  string `json:"streamType"`
 	ContentType string `json:"contentType"`
+	// ContentURL is played instead of the ContentID by the receivers which support it
+	// (when the ContentID is an identifier specific to the receiver)
+	ContentURL string `json:"contentUrl,omitempty"`
+	// Metadata displayed by the receiver (like a GenericMediaMetadata, see the Metadata option)
+	Metadata interface{} `json:"metadata,omitempty"`
+	// CustomData is passed to the receiver along with the media (required by some receivers)
+	CustomData interface{} `json:"customData,omitempty"`
+	// Tracks of the media, like side-loaded subtitles (see WithSubtitles)
+	Tracks []Track `json:"tracks,omitempty"`
+	// TextTrackStyle of the subtitles (see WithTextTrackStyle)
+	TextTrackStyle *TextTrackStyle `json:"textTrackStyle,omitempty"`
+	// DRM of a protected stream (added to the CustomData, see WithDRM)
+	DRM *DRM `json:"-"`
 }
 
 type Status struct {
 	SessionID              int                    `json:"mediaSessionId"`
 	PlaybackRate           float64                `json:"playbackRate"`
-	PlayerState            string                 `json:"playerState"`
+	PlayerState            PlayerState            `json:"playerState"`
 	CurrentTime            Seconds                `json:"currentTime"`
 	SupportedMediaCommands int                    `json:"supportedMediaCommands"`
 	Volume                 *chromecast.Volume     `json:"volume,omitempty"`
 	Item                   *ItemStatus            `json:"media"`
 	CustomData             map[string]interface{} `json:"customData"`
-	RepeatMode             string                 `json:"repeatMode"`
-	IdleReason             string                 `json:"idleReason"`
+	RepeatMode             RepeatMode             `json:"repeatMode"`
+	IdleReason             IdleReason             `json:"idleReason"`
+	// CurrentItemID is the item of the queue being played
+	CurrentItemID int `json:"currentItemId,omitempty"`
+	// PreloadedItemID is the next item of the queue, already loaded
+	PreloadedItemID int `json:"preloadedItemId,omitempty"`
+	// LiveSeekableRange is the window which can be seeked, for a live stream
+	LiveSeekableRange *SeekableRange `json:"liveSeekableRange,omitempty"`
+}
+
+// PlayerState of a media session
+type PlayerState string
+
+const (
+	Idle      PlayerState = "IDLE"
+	Buffering PlayerState = "BUFFERING"
+	Playing   PlayerState = "PLAYING"
+	Paused    PlayerState = "PAUSED"
+)
+
+// IdleReason explains why the player is IDLE
+type IdleReason string
+
+const (
+	IdleFinished    IdleReason = "FINISHED"
+	IdleCancelled   IdleReason = "CANCELLED"
+	IdleError       IdleReason = "ERROR"
+	IdleInterrupted IdleReason = "INTERRUPTED"
+)
+
+// Finished returns true if the playback reached the end of the media
+func (s Status) Finished() bool {
+	return s.PlayerState == Idle && s.IdleReason == IdleFinished
+}
+
+// Errored returns true if the playback stopped because of an error
+func (s Status) Errored() bool {
+	return s.PlayerState == Idle && s.IdleReason == IdleError
+}
+
+// IsLive returns true for a live stream
+func (s Status) IsLive() bool {
+	return s.LiveSeekableRange != nil || (s.Item != nil && s.Item.StreamType == "LIVE")
+}
+
+// SeekableRange of a live stream
+type SeekableRange struct {
+	Start Seconds `json:"start"`
+	End   Seconds `json:"end"`
+	// IsMovingWindow is true if the start moves forward with the live edge
+	IsMovingWindow bool `json:"isMovingWindow"`
+	// IsLiveDone is true when the live stream has ended
+	IsLiveDone bool `json:"isLiveDone"`
 }
 
 type statusResponse struct {
@@ -90,7 +169,7 @@ func (s Seconds) MarshalJSON() (b []byte, err error) {
 
 // FOR DEBUG ONLY!
 func (a *App) syncedRequestDEBUG(payload chromecast.IdentifiablePayload) error {
-	response, err := a.Client.Request(a.Envelope, payload)
+	response, err := a.Client.Request(a.envelope(), payload)
 	if err != nil {
 		return err
 	}
@@ -99,21 +178,111 @@ func (a *App) syncedRequestDEBUG(payload chromecast.IdentifiablePayload) error {
 }
 
 func (a *App) request(payload chromecast.IdentifiablePayload) (<-chan []byte, error) {
-	return a.Client.Request(a.Envelope, payload)
+	return a.Client.Request(a.envelope(), payload)
 }
 
 func (a *App) setStatus(st []Status) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	a.latestStatus = st
+	a.statusTime = time.Now()
+	a.publish(st)
+}
+
+// mergeStatus replaces the statuses of the same sessions (and keeps the others)
+func (a *App) mergeStatus(st []Status) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	merged := make([]Status, 0, len(a.latestStatus)+len(st))
+	for _, previous := range a.latestStatus {
+		replaced := false
+		for _, s := range st {
+			if s.SessionID == previous.SessionID {
+				replaced = true
+			}
+		}
+		if !replaced {
+			merged = append(merged, previous)
+		}
+	}
+	a.latestStatus = append(merged, st...)
+	a.statusTime = time.Now()
+	a.publish(st)
 }
 
+// extrapolatedTime returns the current time of the media session,
+// according to the latest status and the time elapsed since
+func (a *App) extrapolatedTime(sessionID int) (time.Duration, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, st := range a.latestStatus {
+		if st.SessionID != sessionID {
+			continue
+		}
+		current := st.CurrentTime.Duration
+		if st.PlayerState == Playing {
+			rate := st.PlaybackRate
+			if rate == 0 {
+				rate = 1
+			}
+			current += time.Duration(float64(time.Since(a.statusTime)) * rate)
+		}
+		return current, true
+	}
+	return 0, false
+}
+
+// CurrentSession returns the first media session of the latest status
+// (which is fetched if it is not known yet, like after ConnectFromStatus)
 func (a *App) CurrentSession() (*Session, error) {
+	a.mu.Lock()
+	known := a.latestStatus != nil
+	a.mu.Unlock()
+	if !known {
+		if _, err := a.Status(); err != nil {
+			return nil, err
+		}
+	}
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	return a.firstSession(a.latestStatus)
 }
 
+// Attach connects to the media app already running on the receiver (launched by another sender
+// or before a restart) and returns its current session, without reloading the content
+func Attach(ctx context.Context, client chromecast.Client, st chromecast.Status) (*App, *Session, error) {
+	app, err := ConnectFromStatus(client, st)
+	if err != nil {
+		return nil, nil, err
+	}
+	statuses, err := app.StatusCtx(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get the media status: %w", err)
+	}
+	session, err := app.firstSession(statuses)
+	if err != nil {
+		return nil, nil, err
+	}
+	return app, session, nil
+}
+
+// Session returns the media session with the given ID, if it is in the latest status
+// (chromecast.ErrNoSession otherwise)
+func (a *App) Session(mediaSessionID int) (*Session, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, status := range a.latestStatus {
+		if status.SessionID == mediaSessionID {
+			return &Session{
+				App: a,
+				ID:  mediaSessionID,
+			}, nil
+		}
+	}
+	return nil, chromecast.ErrNoSession
+}
+
 func (a *App) LatestStatus() []Status {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -129,7 +298,7 @@ func (a *App) firstSession(st []Status) (*Session, error) {
 			}, nil
 		}
 	}
-	return nil, fmt.Errorf("no valid SessionId has been found in the status")
+	return nil, chromecast.ErrNoSession
 }
 
 // Option to customize the loading
@@ -145,13 +314,52 @@ func Seek(t time.Duration) func(command.Map) {
 	}
 }
 
-func CustomData(data interface{}) func(command.Map) {
+// Live loads the item as a live stream
+func Live(c command.Map) {
+	if item, ok := c["media"].(Item); ok {
+		item.StreamType = "LIVE"
+		c["media"] = item
+	}
+}
+
+// WithCustomData adds the data to the request (LOAD or a session command like PLAY, PAUSE, SEEK...),
+// for the receivers which require it
+func WithCustomData(data interface{}) func(command.Map) {
 	return func(c command.Map) {
 		c["customData"] = data
 	}
 }
 
-func (a App) Load(item Item, options ...Option) (<-chan []byte, error) {
+// CustomData adds the data to the request
+//
+// Deprecated: use WithCustomData
+func CustomData(data interface{}) func(command.Map) {
+	return WithCustomData(data)
+}
+
+// Load sends a LOAD request (see the Retry option to retry on LOAD_FAILED,
+// the retries are then bounded by command.DefaultTimeout and the last failure is always sent on the channel)
+func (a *App) Load(item Item, options ...Option) (<-chan []byte, error) {
+	payload := loadPayload(item, options)
+	policy, ok := popRetryPolicy(payload)
+	if !ok {
+		return a.Client.Request(a.envelope(), payload)
+	}
+	ch := make(chan []byte, 1)
+	go func() {
+		defer close(ch)
+		ctx, cancel := context.WithTimeout(context.Background(), command.DefaultTimeout)
+		defer cancel()
+		body, err := a.loadWithRetry(ctx, payload, policy)
+		if err != nil {
+			body = loadFailedPayload(err)
+		}
+		ch <- body
+	}()
+	return ch, nil
+}
+
+func loadPayload(item Item, options []Option) command.Map {
 	payload := command.Map{
 		"type":  "LOAD",
 		"media": item,
@@ -159,15 +367,32 @@ func (a App) Load(item Item, options ...Option) (<-chan []byte, error) {
 	for _, opt := range options {
 		opt(payload)
 	}
-	return a.Client.Request(a.Envelope, payload)
+	return payload
 }
 
+// LoadAndGetSession loads the item and returns its session (waiting at most command.DefaultTimeout)
 func (a *App) LoadAndGetSession(item Item, options ...Option) (*Session, error) {
-	response, err := a.Load(item, options...)
+	ctx, cancel := context.WithTimeout(context.Background(), command.DefaultTimeout)
+	defer cancel()
+	return a.LoadAndGetSessionCtx(ctx, item, options...)
+}
+
+// LoadAndGetSessionCtx loads the item and returns its session (chromecast.ErrRequestTimeout if ctx is done before)
+func (a *App) LoadAndGetSessionCtx(ctx context.Context, item Item, options ...Option) (*Session, error) {
+	payload := loadPayload(item, options)
+	var body []byte
+	var err error
+	if policy, ok := popRetryPolicy(payload); ok {
+		body, err = a.loadWithRetry(ctx, payload, policy)
+	} else {
+		body, err = command.Request(ctx, a.Client, a.envelope(), payload)
+	}
 	if err != nil {
 		return nil, err
 	}
-	body := <-response
+	if err = command.ResponseError(body); err != nil {
+		return nil, err
+	}
 	s, err := unmarshalStatus(body)
 	if err != nil {
 		return nil, err
@@ -176,13 +401,66 @@ func (a *App) LoadAndGetSession(item Item, options ...Option) (*Session, error)
 	return a.firstSession(s.Status)
 }
 
+// Status returns the media status (waiting at most command.DefaultTimeout)
 func (a *App) Status() ([]Status, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), command.DefaultTimeout)
+	defer cancel()
+	return a.StatusCtx(ctx)
+}
+
+// StatusThrottle during which the statuses pushed by the receiver are considered fresh enough:
+// StatusCtx returns the latest status instead of sending a GET_STATUS
+const StatusThrottle = time.Second
+
+// statusCall is a GET_STATUS in flight, shared by the concurrent callers of StatusCtx
+type statusCall struct {
+	done   chan struct{}
+	status []Status
+	err    error
+}
+
+// StatusCtx returns the media status (chromecast.ErrRequestTimeout if ctx is done before)
+// If a GET_STATUS is already in flight, its result is shared.
+// If a status was pushed by the receiver less than StatusThrottle ago, it is returned without request.
+func (a *App) StatusCtx(ctx context.Context) ([]Status, error) {
+	a.mu.Lock()
+	if !a.pushedAt.IsZero() && time.Since(a.pushedAt) < StatusThrottle {
+		defer a.mu.Unlock()
+		return a.latestStatus, nil
+	}
+	call := a.inflight
+	if call != nil {
+		a.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.status, call.err
+		case <-ctx.Done():
+			return nil, chromecast.ErrRequestTimeout
+		}
+	}
+	call = &statusCall{done: make(chan struct{})}
+	a.inflight = call
+	a.mu.Unlock()
+
+	call.status, call.err = a.fetchStatus(ctx)
+
+	a.mu.Lock()
+	a.inflight = nil
+	a.mu.Unlock()
+	close(call.done)
+	return call.status, call.err
+}
+
+// fetchStatus sends a GET_STATUS (without coalescing nor throttling)
+func (a *App) fetchStatus(ctx context.Context) ([]Status, error) {
 	payload := command.Map{"type": "GET_STATUS"}
-	response, err := a.Client.Request(a.Envelope, payload)
+	body, err := command.Request(ctx, a.Client, a.envelope(), payload)
 	if err != nil {
 		return nil, err
 	}
-	body := <-response
+	if err = command.ResponseError(body); err != nil {
+		return nil, err
+	}
 
 	s, err := unmarshalStatus(body)
 	if err == nil {
@@ -191,12 +469,18 @@ func (a *App) Status() ([]Status, error) {
 	return s.Status, err
 }
 
+// UpdateStatus listens to the MEDIA_STATUS messages to update the latest status (until the client is closed)
 func (a *App) UpdateStatus() {
+	a.mu.Lock()
+	a.updating = true
+	a.mu.Unlock()
+
 	ch := make(chan []byte, 1)
+	appEnv := a.envelope()
 	env := chromecast.Envelope{
-		Source:      a.Envelope.Destination,
-		Destination: a.Envelope.Source,
-		Namespace:   a.Envelope.Namespace,
+		Source:      appEnv.Destination,
+		Destination: appEnv.Source,
+		Namespace:   appEnv.Namespace,
 	}
 	a.Client.Listen(env, "MEDIA_STATUS", ch)
 
@@ -205,6 +489,9 @@ func (a *App) UpdateStatus() {
 		if err != nil {
 			continue
 		}
+		a.mu.Lock()
+		a.pushedAt = time.Now()
+		a.mu.Unlock()
 		a.setStatus(s.Status)
 	}
 }
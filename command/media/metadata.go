@@ -0,0 +1,126 @@
+package media
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/oliverpool/go-chromecast/command"
+)
+
+// Types of metadata (metadataType)
+const (
+	GenericMetadataType    = 0
+	MovieMetadataType      = 1
+	TvShowMetadataType     = 2
+	MusicTrackMetadataType = 3
+	PhotoMetadataType      = 4
+)
+
+// Image of a media (like a poster or an album cover)
+type Image struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// GenericMediaMetadata describes any media
+type GenericMediaMetadata struct {
+	Title       string  `json:"title,omitempty"`
+	Subtitle    string  `json:"subtitle,omitempty"`
+	Images      []Image `json:"images,omitempty"`
+	ReleaseDate string  `json:"releaseDate,omitempty"` // ISO 8601
+}
+
+func (m GenericMediaMetadata) MarshalJSON() ([]byte, error) {
+	type alias GenericMediaMetadata
+	return marshalMetadata(GenericMetadataType, alias(m))
+}
+
+// MovieMediaMetadata describes a movie
+type MovieMediaMetadata struct {
+	Title       string  `json:"title,omitempty"`
+	Subtitle    string  `json:"subtitle,omitempty"`
+	Studio      string  `json:"studio,omitempty"`
+	Images      []Image `json:"images,omitempty"`
+	ReleaseDate string  `json:"releaseDate,omitempty"` // ISO 8601
+}
+
+func (m MovieMediaMetadata) MarshalJSON() ([]byte, error) {
+	type alias MovieMediaMetadata
+	return marshalMetadata(MovieMetadataType, alias(m))
+}
+
+// TvShowMediaMetadata describes an episode of a TV show
+type TvShowMediaMetadata struct {
+	SeriesTitle     string  `json:"seriesTitle,omitempty"`
+	Title           string  `json:"title,omitempty"`
+	Season          int     `json:"season,omitempty"`
+	Episode         int     `json:"episode,omitempty"`
+	Images          []Image `json:"images,omitempty"`
+	OriginalAirdate string  `json:"originalAirdate,omitempty"` // ISO 8601
+}
+
+func (m TvShowMediaMetadata) MarshalJSON() ([]byte, error) {
+	type alias TvShowMediaMetadata
+	return marshalMetadata(TvShowMetadataType, alias(m))
+}
+
+// MusicTrackMediaMetadata describes a music track
+type MusicTrackMediaMetadata struct {
+	Title       string  `json:"title,omitempty"`
+	AlbumName   string  `json:"albumName,omitempty"`
+	AlbumArtist string  `json:"albumArtist,omitempty"`
+	Artist      string  `json:"artist,omitempty"`
+	Composer    string  `json:"composer,omitempty"`
+	TrackNumber int     `json:"trackNumber,omitempty"`
+	DiscNumber  int     `json:"discNumber,omitempty"`
+	Images      []Image `json:"images,omitempty"`
+	ReleaseDate string  `json:"releaseDate,omitempty"` // ISO 8601
+}
+
+func (m MusicTrackMediaMetadata) MarshalJSON() ([]byte, error) {
+	type alias MusicTrackMediaMetadata
+	return marshalMetadata(MusicTrackMetadataType, alias(m))
+}
+
+// PhotoMediaMetadata describes a photo
+type PhotoMediaMetadata struct {
+	Title            string  `json:"title,omitempty"`
+	Artist           string  `json:"artist,omitempty"`
+	Location         string  `json:"location,omitempty"`
+	Latitude         float64 `json:"latitude,omitempty"`
+	Longitude        float64 `json:"longitude,omitempty"`
+	Width            int     `json:"width,omitempty"`
+	Height           int     `json:"height,omitempty"`
+	CreationDateTime string  `json:"creationDateTime,omitempty"` // ISO 8601
+}
+
+func (m PhotoMediaMetadata) MarshalJSON() ([]byte, error) {
+	type alias PhotoMediaMetadata
+	return marshalMetadata(PhotoMetadataType, alias(m))
+}
+
+// marshalMetadata adds the metadataType to the fields of the metadata
+func marshalMetadata(metadataType int, metadata interface{}) ([]byte, error) {
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err = json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	fields["metadataType"] = json.RawMessage(strconv.Itoa(metadataType))
+	return json.Marshal(fields)
+}
+
+// Metadata sets the metadata of the loaded item (like a GenericMediaMetadata),
+// displayed by the receiver
+func Metadata(metadata interface{}) Option {
+	return func(c command.Map) {
+		if item, ok := c["media"].(Item); ok {
+			item.Metadata = metadata
+			c["media"] = item
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package media_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/oliverpool/go-chromecast/command"
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func TestMetadata(t *testing.T) {
+	payload := command.Map{"media": media.Item{ContentID: "http://example.com/song.mp3"}}
+	media.Metadata(media.MusicTrackMediaMetadata{
+		Title:  "Song",
+		Artist: "Band",
+		Images: []media.Image{{URL: "http://example.com/cover.jpg"}},
+	})(payload)
+
+	b, err := json.Marshal(payload["media"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"contentId":"http://example.com/song.mp3","streamType":"","contentType":"","metadata":{"artist":"Band","images":[{"url":"http://example.com/cover.jpg"}],"metadataType":3,"title":"Song"}}`
+	if string(b) != expected {
+		t.Errorf("unexpected JSON:\n%s\nexpected:\n%s", b, expected)
+	}
+}
+
+func TestCustomData(t *testing.T) {
+	b, err := json.Marshal(media.Item{ContentID: "42", CustomData: map[string]string{"token": "secret"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"contentId":"42","streamType":"","contentType":"","customData":{"token":"secret"}}`
+	if string(b) != expected {
+		t.Errorf("unexpected JSON:\n%s\nexpected:\n%s", b, expected)
+	}
+
+	payload := command.Map{"type": "PLAY"}
+	media.WithCustomData(map[string]string{"token": "secret"})(payload)
+	if data, ok := payload["customData"].(map[string]string); !ok || data["token"] != "secret" {
+		t.Errorf("the custom data should be added to the command, got %v", payload)
+	}
+}
+
+func TestDRM(t *testing.T) {
+	payload := command.Map{"media": media.Item{ContentID: "http://example.com/stream.mpd", CustomData: map[string]string{"token": "secret"}}}
+	media.WithDRM(media.DRM{
+		LicenseURL:       "https://example.com/license",
+		ProtectionSystem: media.Widevine,
+		LicenseHeaders:   map[string]string{"Authorization": "Bearer 42"},
+	})(payload)
+
+	b, err := json.Marshal(payload["media"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"contentId":"http://example.com/stream.mpd","streamType":"","contentType":"","customData":{"licenseHeaders":{"Authorization":"Bearer 42"},"licenseUrl":"https://example.com/license","protectionSystem":"widevine","token":"secret"}}`
+	if string(b) != expected {
+		t.Errorf("unexpected JSON:\n%s\nexpected:\n%s", b, expected)
+	}
+}
+
+func TestTextTrackStyle(t *testing.T) {
+	payload := command.Map{"media": media.Item{ContentID: "42"}}
+	style := media.DefaultTextTrackStyle()
+	style.FontScale = 1.5
+	media.WithTextTrackStyle(style)(payload)
+
+	b, err := json.Marshal(payload["media"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"contentId":"42","streamType":"","contentType":"","textTrackStyle":{"fontScale":1.5,"foregroundColor":"#FFFFFFFF","backgroundColor":"#00000000","edgeType":"OUTLINE","edgeColor":"#000000FF"}}`
+	if string(b) != expected {
+		t.Errorf("unexpected JSON:\n%s\nexpected:\n%s", b, expected)
+	}
+}
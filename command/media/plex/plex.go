@@ -0,0 +1,267 @@
+// Package plex loads the media of a Plex Media Server on the Plex receiver
+package plex
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+const ID = "9AC194DC"
+
+// Server of the media (the token can be found by following
+// https://support.plex.tv/articles/204059436-finding-an-authentication-token-x-plex-token/)
+type Server struct {
+	URL   string `json:"url"` // like http://192.168.1.10:32400
+	Token string `json:"token"`
+}
+
+// DefaultServer is used by the URLLoader
+// (if its URL is empty, the settings of the "plex" loader are used, see media.LoaderConfig)
+var DefaultServer Server
+
+// defaultServer returns the DefaultServer or the configured one
+func defaultServer() (Server, error) {
+	if DefaultServer.URL != "" {
+		return DefaultServer, nil
+	}
+	var s Server
+	_, err := media.LoaderConfig("plex", &s)
+	return s, err
+}
+
+type App struct {
+	*media.App
+}
+
+func LaunchAndConnect(client chromecast.Client, statuses ...chromecast.Status) (App, error) {
+	app, err := media.LaunchAndConnect(client, ID, statuses...)
+	return App{app}, err
+}
+
+func (a App) Load(item media.Item, options ...media.Option) (<-chan []byte, error) {
+	return a.App.Load(item, options...)
+}
+
+func init() {
+	media.RegisterMatchingLoader("plex", media.SitePriority, URLLoader, CanHandle)
+}
+
+// CanHandle returns true for the URLs of a media of Plex (if a server is configured)
+func CanHandle(rawurl string) bool {
+	if _, err := ExtractKey(rawurl); err != nil {
+		return false
+	}
+	s, err := defaultServer()
+	return err == nil && s.URL != ""
+}
+
+func URLLoader(rawurl string, options ...media.Option) (func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error), error) {
+	key, err := ExtractKey(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	server, err := defaultServer()
+	if err != nil {
+		return nil, err
+	}
+	if server.URL == "" || server.Token == "" {
+		return nil, fmt.Errorf("no plex server configured (url and token are required)")
+	}
+	item, err := server.Item(key)
+	if err != nil {
+		return nil, err
+	}
+	return func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error) {
+		app, err := LaunchAndConnect(client, statuses...)
+		if err != nil {
+			return nil, err
+		}
+		return app.Load(item, options...)
+	}, nil
+}
+
+// ExtractKey returns the metadata key (like /library/metadata/123) of
+// a URL of the Plex web app (like https://app.plex.tv/desktop/#!/server/abc/details?key=%2Flibrary%2Fmetadata%2F123)
+// or of a plex://123 URL
+func ExtractKey(rawurl string) (string, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", fmt.Errorf("could not parse url '%s': %v", rawurl, err)
+	}
+	if u.Scheme == "plex" {
+		id := u.Host + u.Path
+		if _, err := strconv.Atoi(id); err != nil {
+			return "", fmt.Errorf("unsupported plex id: %s", id)
+		}
+		return "/library/metadata/" + id, nil
+	}
+	if u.Host != "app.plex.tv" && !strings.HasPrefix(u.Path, "/web/") {
+		return "", fmt.Errorf("unsupported host: %s", u.Host)
+	}
+	// the route of the web app is in the fragment: !/server/<id>/details?key=...
+	i := strings.Index(u.Fragment, "?")
+	if !strings.HasPrefix(u.Fragment, "!/server/") || i < 0 {
+		return "", fmt.Errorf("could not find the details of a media inside URL: %s", rawurl)
+	}
+	query, err := url.ParseQuery(u.Fragment[i+1:])
+	if err != nil {
+		return "", fmt.Errorf("could not parse the route '%s': %v", u.Fragment, err)
+	}
+	key := query.Get("key")
+	if !strings.HasPrefix(key, "/library/metadata/") {
+		return "", fmt.Errorf("could not find the key of a media inside URL: %s", rawurl)
+	}
+	return key, nil
+}
+
+type identity struct {
+	MediaContainer struct {
+		MachineIdentifier string `json:"machineIdentifier"`
+		Version           string `json:"version"`
+	} `json:"MediaContainer"`
+}
+
+type metadataContainer struct {
+	MediaContainer struct {
+		Metadata []metadata `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+type metadata struct {
+	Key              string `json:"key"`
+	Type             string `json:"type"`
+	Title            string `json:"title"`
+	GrandparentTitle string `json:"grandparentTitle"`
+	ParentTitle      string `json:"parentTitle"`
+	Thumb            string `json:"thumb"`
+	Media            []struct {
+		Part []struct {
+			Key string `json:"key"`
+		} `json:"Part"`
+	} `json:"Media"`
+}
+
+// Item resolves the media of the metadata key, with the custom data expected by the Plex receiver
+func (s Server) Item(key string) (media.Item, error) {
+	var id identity
+	if err := s.getJSON("/identity", &id); err != nil {
+		return media.Item{}, fmt.Errorf("could not get the identity of the server: %v", err)
+	}
+	var mc metadataContainer
+	if err := s.getJSON(key, &mc); err != nil {
+		return media.Item{}, fmt.Errorf("could not get the metadata of '%s': %v", key, err)
+	}
+	if len(mc.MediaContainer.Metadata) == 0 {
+		return media.Item{}, fmt.Errorf("no metadata found for '%s'", key)
+	}
+	m := mc.MediaContainer.Metadata[0]
+	if len(m.Media) == 0 || len(m.Media[0].Part) == 0 {
+		return media.Item{}, fmt.Errorf("no playable part found for '%s'", key)
+	}
+	customData, err := s.customData(key, id)
+	if err != nil {
+		return media.Item{}, err
+	}
+
+	item := media.Item{
+		ContentID:   key,
+		ContentType: "video",
+		StreamType:  "BUFFERED",
+		ContentURL:  s.DirectPlayURL(m.Media[0].Part[0].Key),
+		CustomData:  customData,
+	}
+	var images []media.Image
+	if m.Thumb != "" {
+		images = []media.Image{{URL: s.DirectPlayURL(m.Thumb)}}
+	}
+	switch m.Type {
+	case "track":
+		item.ContentType = "music"
+		item.Metadata = media.MusicTrackMediaMetadata{
+			Title:     m.Title,
+			Artist:    m.GrandparentTitle,
+			AlbumName: m.ParentTitle,
+			Images:    images,
+		}
+	case "episode":
+		item.Metadata = media.GenericMediaMetadata{
+			Title:    m.Title,
+			Subtitle: m.GrandparentTitle,
+			Images:   images,
+		}
+	default:
+		item.Metadata = media.GenericMediaMetadata{
+			Title:  m.Title,
+			Images: images,
+		}
+	}
+	return item, nil
+}
+
+// DirectPlayURL returns the URL of a resource of the server (like a part or a thumbnail),
+// authenticated with the token
+func (s Server) DirectPlayURL(path string) string {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return strings.TrimRight(s.URL, "/") + path + sep + url.Values{"X-Plex-Token": {s.Token}}.Encode()
+}
+
+// customData describes the server to the Plex receiver, which fetches the media itself
+func (s Server) customData(key string, id identity) (map[string]interface{}, error) {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the server url '%s': %v", s.URL, err)
+	}
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		host, port = u.Host, "32400"
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported port of the server: %s", port)
+	}
+	return map[string]interface{}{
+		"offset":       0,
+		"directPlay":   true,
+		"directStream": true,
+		"containerKey": key,
+		"server": map[string]interface{}{
+			"machineIdentifier": id.MediaContainer.MachineIdentifier,
+			"version":           id.MediaContainer.Version,
+			"transcoderVideo":   true,
+			"protocol":          u.Scheme,
+			"address":           host,
+			"port":              p,
+			"accessToken":       s.Token,
+			"user":              map[string]interface{}{"username": ""},
+		},
+	}, nil
+}
+
+func (s Server) getJSON(path string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(s.URL, "/")+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Plex-Token", s.Token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
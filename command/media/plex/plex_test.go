@@ -0,0 +1,82 @@
+package plex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func TestExtractKey(t *testing.T) {
+	cc := []struct {
+		url string
+		key string
+	}{
+		{"https://app.plex.tv/desktop/#!/server/abc123/details?key=%2Flibrary%2Fmetadata%2F123", "/library/metadata/123"},
+		{"http://192.168.1.10:32400/web/index.html#!/server/abc123/details?key=%2Flibrary%2Fmetadata%2F45&context=home", "/library/metadata/45"},
+		{"plex://678", "/library/metadata/678"},
+	}
+	for _, c := range cc {
+		key, err := ExtractKey(c.url)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.url, err)
+		}
+		if key != c.key {
+			t.Errorf("%s: got key '%s' instead of '%s'", c.url, key, c.key)
+		}
+	}
+
+	for _, u := range []string{
+		"https://example.com/#!/server/abc/details?key=%2Flibrary%2Fmetadata%2F1",
+		"https://app.plex.tv/desktop/#!/media/tv.plex.provider.discover",
+		"plex://abc",
+	} {
+		if _, err := ExtractKey(u); err == nil {
+			t.Errorf("%s: an error was expected", u)
+		}
+	}
+}
+
+func TestServerItem(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Plex-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/identity":
+			w.Write([]byte(`{"MediaContainer":{"machineIdentifier":"abc123","version":"1.32.0"}}`))
+		case "/library/metadata/123":
+			w.Write([]byte(`{"MediaContainer":{"Metadata":[{"key":"/library/metadata/123","type":"episode","title":"Pilot","grandparentTitle":"Show",
+				"thumb":"/library/metadata/123/thumb/1","Media":[{"Part":[{"key":"/library/parts/9/file.mkv"}]}]}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	s := Server{URL: ts.URL, Token: "secret"}
+	item, err := s.Item("/library/metadata/123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ContentID != "/library/metadata/123" || item.ContentType != "video" {
+		t.Errorf("unexpected item: %+v", item)
+	}
+	if item.ContentURL != ts.URL+"/library/parts/9/file.mkv?X-Plex-Token=secret" {
+		t.Errorf("unexpected direct play url: %s", item.ContentURL)
+	}
+	m, ok := item.Metadata.(media.GenericMediaMetadata)
+	if !ok || m.Title != "Pilot" || m.Subtitle != "Show" || len(m.Images) != 1 {
+		t.Errorf("unexpected metadata: %+v", item.Metadata)
+	}
+	server := item.CustomData.(map[string]interface{})["server"].(map[string]interface{})
+	if server["machineIdentifier"] != "abc123" || server["accessToken"] != "secret" || server["protocol"] != "http" || server["address"] != "127.0.0.1" {
+		t.Errorf("unexpected server data: %+v", server)
+	}
+
+	if _, err := (Server{URL: ts.URL, Token: "wrong"}).Item("/library/metadata/123"); err == nil {
+		t.Error("an error was expected with a wrong token")
+	}
+}
@@ -0,0 +1,76 @@
+package media
+
+import (
+	"context"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// Probe is the result of a loader for a URL
+type Probe struct {
+	NamedLoader
+	// Load the resolved media (nil if Err is not nil)
+	Load func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error)
+	Err  error
+}
+
+// ProbeLoaders resolves the URL with all the loaders concurrently (they must be sorted like Loaders()).
+//
+// The probes of a priority are sent as soon as all the loaders of a higher priority are done
+// (a loader does not need to wait for the slow loaders of a lower priority).
+// For the same priority, the first done is sent first.
+//
+// The channel is closed when all the probes are sent, or when ctx is done
+// (the remaining probes are then discarded: cancel ctx as soon as a probe was loaded).
+func ProbeLoaders(ctx context.Context, loaders []NamedLoader, rawurl string, options ...Option) <-chan Probe {
+	type result struct {
+		group int
+		probe Probe
+	}
+
+	// the loaders of the same priority form a group
+	var sizes []int
+	results := make(chan result, len(loaders))
+	for i, l := range loaders {
+		if i == 0 || l.Priority != loaders[i-1].Priority {
+			sizes = append(sizes, 0)
+		}
+		group := len(sizes) - 1
+		sizes[group]++
+		go func(l NamedLoader) {
+			load, err := l.Loader(rawurl, options...)
+			results <- result{group: group, probe: Probe{NamedLoader: l, Load: load, Err: err}}
+		}(l)
+	}
+
+	out := make(chan Probe)
+	go func() {
+		defer close(out)
+		// probes of each group, in the order they were done
+		done := make([][]Probe, len(sizes))
+		current, sent := 0, 0
+		for range loaders {
+			select {
+			case r := <-results:
+				done[r.group] = append(done[r.group], r.probe)
+			case <-ctx.Done():
+				return
+			}
+			for current < len(sizes) {
+				for ; sent < len(done[current]); sent++ {
+					select {
+					case out <- done[current][sent]:
+					case <-ctx.Done():
+						return
+					}
+				}
+				if sent < sizes[current] {
+					// wait for the other loaders of this group
+					break
+				}
+				current, sent = current+1, 0
+			}
+		}
+	}()
+	return out
+}
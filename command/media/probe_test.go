@@ -0,0 +1,79 @@
+package media_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func delayedLoader(d time.Duration, err error) media.URLLoader {
+	return func(rawurl string, options ...media.Option) (func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error), error) {
+		time.Sleep(d)
+		if err != nil {
+			return nil, err
+		}
+		return func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error) {
+			return nil, nil
+		}, nil
+	}
+}
+
+func TestProbeLoaders(t *testing.T) {
+	unsupported := errors.New("unsupported")
+	loaders := []media.NamedLoader{
+		{Name: "site.failing", Priority: 100, Loader: delayedLoader(60*time.Millisecond, unsupported)},
+		{Name: "default.slow", Priority: 10, Loader: delayedLoader(40*time.Millisecond, nil)},
+		{Name: "default.fast", Priority: 10, Loader: delayedLoader(0, nil)},
+		{Name: "fallback", Priority: 0, Loader: delayedLoader(0, nil)},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	start := time.Now()
+	var names []string
+	for p := range media.ProbeLoaders(ctx, loaders, "http://example.com/video.mp4") {
+		names = append(names, p.Name)
+		if (p.Err == nil) == (p.Load == nil) {
+			t.Errorf("%s: either Load or Err should be set", p.Name)
+		}
+	}
+	expected := []string{"site.failing", "default.fast", "default.slow", "fallback"}
+	if len(names) != len(expected) {
+		t.Fatalf("unexpected probes: %v", names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("unexpected order: %v (expected %v)", names, expected)
+			break
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 90*time.Millisecond {
+		t.Errorf("the loaders should be probed concurrently (took %s)", elapsed)
+	}
+}
+
+func TestProbeLoadersCancel(t *testing.T) {
+	loaders := []media.NamedLoader{
+		{Name: "fast", Priority: 10, Loader: delayedLoader(0, nil)},
+		{Name: "slow", Priority: 0, Loader: delayedLoader(time.Second, nil)},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	probes := media.ProbeLoaders(ctx, loaders, "http://example.com/video.mp4")
+	if p := <-probes; p.Name != "fast" {
+		t.Fatalf("unexpected first probe: %s", p.Name)
+	}
+	cancel()
+	select {
+	case _, ok := <-probes:
+		if ok {
+			t.Error("no probe should be sent after the cancellation")
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Error("the probes should be closed after the cancellation")
+	}
+}
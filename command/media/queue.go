@@ -0,0 +1,109 @@
+package media
+
+import (
+	"context"
+	"time"
+
+	"github.com/oliverpool/go-chromecast/command"
+)
+
+// DefaultPreloadTime before the end of the previous item (default of the receivers)
+const DefaultPreloadTime = 20 * time.Second
+
+// QueueItem is an item of the queue
+type QueueItem struct {
+	// ItemID is assigned by the receiver (leave it empty when loading or inserting)
+	ItemID int  `json:"itemId,omitempty"`
+	Media  Item `json:"media"`
+	// Autoplay starts the item as soon as the previous one ends
+	Autoplay bool `json:"autoplay"`
+	// PreloadTime is the time before the end of the previous item to start loading this one
+	// (for gapless transitions)
+	PreloadTime Seconds `json:"preloadTime"`
+	StartTime   Seconds `json:"startTime"`
+}
+
+// NewQueueItem returns an item played automatically and preloaded DefaultPreloadTime before the end of the previous one
+func NewQueueItem(item Item) QueueItem {
+	return QueueItem{
+		Media:       item,
+		Autoplay:    true,
+		PreloadTime: Seconds{DefaultPreloadTime},
+	}
+}
+
+// StartIndex of the queue to play first
+func StartIndex(i int) func(command.Map) {
+	return func(c command.Map) {
+		c["startIndex"] = i
+	}
+}
+
+// QueueLoad loads the items as a new queue
+func (a *App) QueueLoad(items []QueueItem, options ...Option) (<-chan []byte, error) {
+	return a.Client.Request(a.envelope(), queueLoadPayload(items, options))
+}
+
+// QueueLoadAndGetSessionCtx loads the items as a new queue and returns its session
+// (chromecast.ErrRequestTimeout if ctx is done before)
+func (a *App) QueueLoadAndGetSessionCtx(ctx context.Context, items []QueueItem, options ...Option) (*Session, error) {
+	body, err := command.Request(ctx, a.Client, a.envelope(), queueLoadPayload(items, options))
+	if err != nil {
+		return nil, err
+	}
+	if err = command.ResponseError(body); err != nil {
+		return nil, err
+	}
+	s, err := unmarshalStatus(body)
+	if err != nil {
+		return nil, err
+	}
+	a.setStatus(s.Status)
+	return a.firstSession(s.Status)
+}
+
+func queueLoadPayload(items []QueueItem, options []Option) command.Map {
+	payload := command.Map{
+		"type":       "QUEUE_LOAD",
+		"items":      items,
+		"startIndex": 0,
+	}
+	for _, opt := range options {
+		opt(payload)
+	}
+	return payload
+}
+
+// QueueInsert appends the items to the queue of the session
+func (s Session) QueueInsert(items []QueueItem, options ...Option) (<-chan []byte, error) {
+	options = append(options, func(c command.Map) {
+		c["items"] = items
+	})
+	return s.do("QUEUE_INSERT", options...)
+}
+
+// QueueJump skips n items of the queue (backwards if negative)
+func (s Session) QueueJump(n int, options ...Option) (<-chan []byte, error) {
+	options = append(options, func(c command.Map) {
+		c["jump"] = n
+	})
+	return s.do("QUEUE_UPDATE", options...)
+}
+
+// Next plays the next item of the queue
+func (s Session) Next(options ...Option) (<-chan []byte, error) {
+	return s.QueueJump(1, options...)
+}
+
+// Previous plays the previous item of the queue
+func (s Session) Previous(options ...Option) (<-chan []byte, error) {
+	return s.QueueJump(-1, options...)
+}
+
+// JumpToItem plays the item of the queue with the given itemId
+func (s Session) JumpToItem(itemID int, options ...Option) (<-chan []byte, error) {
+	options = append(options, func(c command.Map) {
+		c["currentItemId"] = itemID
+	})
+	return s.do("QUEUE_UPDATE", options...)
+}
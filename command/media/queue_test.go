@@ -0,0 +1,83 @@
+package media_test
+
+import (
+	"testing"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func TestQueueLoad(t *testing.T) {
+	client := &rebootedClient{transportID: "t1", sessionID: 1}
+	transportID := "t1"
+	app, err := media.ConnectFromStatus(client, chromecast.Status{
+		Applications: []*chromecast.ApplicationSession{{
+			TransportId: &transportID,
+			Namespaces:  []*chromecast.Namespace{{Name: media.Namespace}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := []media.QueueItem{
+		media.NewQueueItem(media.Item{ContentID: "http://example.com/1.mp3"}),
+		media.NewQueueItem(media.Item{ContentID: "http://example.com/2.mp3"}),
+	}
+	if _, err = app.QueueLoad(items, media.StartIndex(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	sent := client.payloads[len(client.payloads)-1]
+	if sent["type"] != "QUEUE_LOAD" || sent["startIndex"] != float64(1) {
+		t.Errorf("unexpected payload: %v", sent)
+	}
+	loaded, _ := sent["items"].([]interface{})
+	if len(loaded) != 2 {
+		t.Fatalf("2 items should have been sent, got %v", sent["items"])
+	}
+	first := loaded[0].(map[string]interface{})
+	if first["autoplay"] != true || first["preloadTime"] != float64(20) {
+		t.Errorf("the item should be autoplayed and preloaded, got %v", first)
+	}
+	if _, ok := first["itemId"]; ok {
+		t.Errorf("the itemId should be left to the receiver, got %v", first["itemId"])
+	}
+}
+
+func TestQueueNavigation(t *testing.T) {
+	client := &rebootedClient{transportID: "t1", sessionID: 1}
+	transportID := "t1"
+	app, err := media.ConnectFromStatus(client, chromecast.Status{
+		Applications: []*chromecast.ApplicationSession{{
+			TransportId: &transportID,
+			Namespaces:  []*chromecast.Namespace{{Name: media.Namespace}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := app.CurrentSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session.Next()
+	session.Previous()
+	session.JumpToItem(4)
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	sent := client.payloads[len(client.payloads)-3:]
+	if sent[0]["type"] != "QUEUE_UPDATE" || sent[0]["jump"] != float64(1) {
+		t.Errorf("unexpected Next payload: %v", sent[0])
+	}
+	if sent[1]["jump"] != float64(-1) {
+		t.Errorf("unexpected Previous payload: %v", sent[1])
+	}
+	if sent[2]["currentItemId"] != float64(4) {
+		t.Errorf("unexpected JumpToItem payload: %v", sent[2])
+	}
+}
@@ -0,0 +1,68 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// MaxRedirects followed by ResolveRedirects
+const MaxRedirects = 5
+
+// ResolveRedirects follows the HTTP redirects of the URL (like a short link) and returns the final URL,
+// to find the loader of the actual media.
+// It uses HEAD requests (or GET if the server does not support HEAD).
+func ResolveRedirects(ctx context.Context, client *http.Client, rawurl string) (string, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return rawurl, fmt.Errorf("could not parse url '%s': %v", rawurl, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return rawurl, nil
+	}
+
+	noFollow := *client
+	noFollow.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	for i := 0; i <= MaxRedirects; i++ {
+		location, err := redirection(ctx, &noFollow, u)
+		if err != nil {
+			return u.String(), err
+		}
+		if location == nil {
+			return u.String(), nil
+		}
+		u = location
+	}
+	return u.String(), fmt.Errorf("more than %d redirects for '%s'", MaxRedirects, rawurl)
+}
+
+// redirection returns the location of the redirect response (nil if the response is not a redirect)
+func redirection(ctx context.Context, client *http.Client, u *url.URL) (*url.URL, error) {
+	var resp *http.Response
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequest(method, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err = client.Do(req.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("could not follow the redirects: %w", err)
+		}
+		// the body of the GET request is not needed
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed && resp.StatusCode != http.StatusNotImplemented {
+			break
+		}
+	}
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return nil, nil
+	}
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil, nil
+	}
+	return u.Parse(location)
+}
@@ -0,0 +1,56 @@
+package media_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func TestResolveRedirects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/short":
+			http.Redirect(w, r, "/no-head", http.StatusMovedPermanently)
+		case r.URL.Path == "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			http.Redirect(w, r, "/watch?v=b-GIBLX3nAk", http.StatusFound)
+		case strings.HasPrefix(r.URL.Path, "/loop/"):
+			n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/loop/"))
+			http.Redirect(w, r, "/loop/"+strconv.Itoa(n+1), http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer ts.Close()
+
+	client := ts.Client()
+	got, err := media.ResolveRedirects(context.Background(), client, ts.URL+"/short")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != ts.URL+"/watch?v=b-GIBLX3nAk" {
+		t.Errorf("unexpected final URL: %s", got)
+	}
+
+	got, err = media.ResolveRedirects(context.Background(), client, ts.URL+"/video.mp4")
+	if err != nil || got != ts.URL+"/video.mp4" {
+		t.Errorf("a URL without redirect should be kept, got %s (%v)", got, err)
+	}
+
+	if _, err = media.ResolveRedirects(context.Background(), client, ts.URL+"/loop/0"); err == nil {
+		t.Error("an error was expected after too many redirects")
+	}
+
+	got, err = media.ResolveRedirects(context.Background(), client, "plex://123")
+	if err != nil || got != "plex://123" {
+		t.Errorf("a non-http URL should be kept, got %s (%v)", got, err)
+	}
+}
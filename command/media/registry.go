@@ -0,0 +1,82 @@
+package media
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Priorities of the loaders registered by this module
+// (the loaders specific to a website come before the generic ones)
+const (
+	SitePriority    = 100
+	DefaultPriority = 10
+	// FallbackPriority is for the loaders accepting any URL
+	FallbackPriority = 0
+)
+
+// NamedLoader is a registered URLLoader
+type NamedLoader struct {
+	Name     string
+	Priority int
+	Loader   URLLoader
+	// Matcher tells if the Loader may handle a URL, without any side effect (nil if unknown)
+	Matcher func(rawurl string) bool
+	// Resolver returns the items to queue on the default media receiver (nil if not supported, see RegisterResolver)
+	Resolver ItemResolver
+}
+
+// CanHandle returns false if the loader can not handle the URL (without calling the Loader).
+// It returns true if the loader has no Matcher.
+func (l NamedLoader) CanHandle(rawurl string) bool {
+	return l.Matcher == nil || l.Matcher(rawurl)
+}
+
+var (
+	loadersMu sync.Mutex
+	loaders   []NamedLoader
+)
+
+// RegisterLoader makes a loader available to all the applications (usually in the init function of the loader package)
+// It panics if a loader with the same name is already registered.
+func RegisterLoader(name string, priority int, loader URLLoader) {
+	RegisterMatchingLoader(name, priority, loader, nil)
+}
+
+// RegisterMatchingLoader registers a loader with its matcher (see NamedLoader.CanHandle)
+func RegisterMatchingLoader(name string, priority int, loader URLLoader, matcher func(rawurl string) bool) {
+	loadersMu.Lock()
+	defer loadersMu.Unlock()
+	for _, l := range loaders {
+		if l.Name == name {
+			panic(fmt.Sprintf("media: loader %q registered twice", name))
+		}
+	}
+	loaders = append(loaders, NamedLoader{Name: name, Priority: priority, Loader: loader, Matcher: matcher})
+}
+
+// Loaders returns the registered loaders, by decreasing priority (and by name for the same priority)
+func Loaders() []NamedLoader {
+	loadersMu.Lock()
+	defer loadersMu.Unlock()
+	sorted := make([]NamedLoader, len(loaders))
+	copy(sorted, loaders)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// Candidates returns the loaders which can handle the URL, sorted like Loaders()
+func Candidates(rawurl string) []NamedLoader {
+	var candidates []NamedLoader
+	for _, l := range Loaders() {
+		if l.CanHandle(rawurl) {
+			candidates = append(candidates, l)
+		}
+	}
+	return candidates
+}
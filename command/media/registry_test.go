@@ -0,0 +1,49 @@
+package media_test
+
+import (
+	"testing"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func nopLoader(rawurl string, options ...media.Option) (func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error), error) {
+	return nil, nil
+}
+
+func TestRegisterLoader(t *testing.T) {
+	media.RegisterLoader("test.low", -1, nopLoader)
+	media.RegisterLoader("test.b", 1000, nopLoader)
+	media.RegisterLoader("test.a", 1000, nopLoader)
+
+	var names []string
+	for _, l := range media.Loaders() {
+		names = append(names, l.Name)
+	}
+	if len(names) < 3 || names[0] != "test.a" || names[1] != "test.b" || names[len(names)-1] != "test.low" {
+		t.Errorf("unexpected order: %v", names)
+	}
+
+	media.RegisterMatchingLoader("test.matching", 1000, nopLoader, func(rawurl string) bool {
+		return rawurl == "test://match"
+	})
+	var candidates []string
+	for _, l := range media.Candidates("test://match") {
+		candidates = append(candidates, l.Name)
+	}
+	if len(candidates) < 4 || candidates[0] != "test.a" || candidates[2] != "test.matching" {
+		t.Errorf("unexpected candidates: %v", candidates)
+	}
+	for _, l := range media.Candidates("test://other") {
+		if l.Name == "test.matching" {
+			t.Error("the matching loader should not handle other URLs")
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering the same name twice should panic")
+		}
+	}()
+	media.RegisterLoader("test.a", 0, nopLoader)
+}
@@ -0,0 +1,37 @@
+package media
+
+import "github.com/oliverpool/go-chromecast/command"
+
+// RepeatMode of the queue
+type RepeatMode string
+
+const (
+	RepeatOff           RepeatMode = "REPEAT_OFF"
+	RepeatAll           RepeatMode = "REPEAT_ALL"
+	RepeatSingle        RepeatMode = "REPEAT_SINGLE"
+	RepeatAllAndShuffle RepeatMode = "REPEAT_ALL_AND_SHUFFLE"
+)
+
+var repeatModes = []RepeatMode{RepeatOff, RepeatAll, RepeatSingle, RepeatAllAndShuffle}
+
+// Next returns the following repeat mode (REPEAT_OFF after REPEAT_ALL_AND_SHUFFLE)
+func (m RepeatMode) Next() RepeatMode {
+	for i, mode := range repeatModes {
+		if mode == m {
+			return repeatModes[(i+1)%len(repeatModes)]
+		}
+	}
+	return RepeatAll // unknown (or empty) mode is considered off
+}
+
+// WithRepeatMode sets the repeat mode of a loaded queue
+func WithRepeatMode(mode RepeatMode) Option {
+	return func(c command.Map) {
+		c["repeatMode"] = mode
+	}
+}
+
+// SetRepeatMode changes the repeat mode of the queue
+func (s Session) SetRepeatMode(mode RepeatMode, options ...Option) (<-chan []byte, error) {
+	return s.do("QUEUE_UPDATE", append(options, WithRepeatMode(mode))...)
+}
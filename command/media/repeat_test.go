@@ -0,0 +1,22 @@
+package media_test
+
+import (
+	"testing"
+
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func TestRepeatModeNext(t *testing.T) {
+	cases := map[media.RepeatMode]media.RepeatMode{
+		"":                        media.RepeatAll,
+		media.RepeatOff:           media.RepeatAll,
+		media.RepeatAll:           media.RepeatSingle,
+		media.RepeatSingle:        media.RepeatAllAndShuffle,
+		media.RepeatAllAndShuffle: media.RepeatOff,
+	}
+	for mode, expected := range cases {
+		if next := mode.Next(); next != expected {
+			t.Errorf("%q.Next() = %q, expected %q", mode, next, expected)
+		}
+	}
+}
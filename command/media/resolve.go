@@ -0,0 +1,49 @@
+package media
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ItemResolver returns the items of the default media receiver for a URL, without interacting
+// with the chromecast (to queue them instead of loading them)
+type ItemResolver func(rawurl string) ([]Item, error)
+
+// RegisterResolver adds a resolver to a registered loader (usually in the init function of the loader package),
+// to be able to queue its URLs (see Resolve)
+// It panics if no loader with this name is registered.
+func RegisterResolver(name string, resolver ItemResolver) {
+	loadersMu.Lock()
+	defer loadersMu.Unlock()
+	for i, l := range loaders {
+		if l.Name == name {
+			loaders[i].Resolver = resolver
+			return
+		}
+	}
+	panic(fmt.Sprintf("media: no loader %q to add a resolver to", name))
+}
+
+// Resolve returns the items of the first candidate loader able to resolve the URL (see Candidates),
+// with the name of this loader
+func Resolve(rawurl string) (string, []Item, error) {
+	var errs []string
+	for _, l := range Candidates(rawurl) {
+		if l.Resolver == nil {
+			continue
+		}
+		items, err := l.Resolver(rawurl)
+		if err == nil && len(items) == 0 {
+			err = fmt.Errorf("no item found")
+		}
+		if err != nil {
+			errs = append(errs, l.Name+": "+err.Error())
+			continue
+		}
+		return l.Name, items, nil
+	}
+	if len(errs) == 0 {
+		return "", nil, fmt.Errorf("no loader can queue '%s'", rawurl)
+	}
+	return "", nil, fmt.Errorf("could not resolve '%s' (%s)", rawurl, strings.Join(errs, "; "))
+}
@@ -0,0 +1,48 @@
+package media_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func TestResolve(t *testing.T) {
+	matchResolve := func(rawurl string) bool {
+		return strings.HasPrefix(rawurl, "resolve://")
+	}
+	media.RegisterMatchingLoader("test.resolve.failing", 2000, nopLoader, matchResolve)
+	media.RegisterResolver("test.resolve.failing", func(rawurl string) ([]media.Item, error) {
+		return nil, errors.New("unavailable")
+	})
+	// without resolver: skipped
+	media.RegisterMatchingLoader("test.resolve.none", 1999, nopLoader, matchResolve)
+	media.RegisterMatchingLoader("test.resolve.ok", 1998, nopLoader, matchResolve)
+	media.RegisterResolver("test.resolve.ok", func(rawurl string) ([]media.Item, error) {
+		if rawurl == "resolve://empty" {
+			return nil, nil
+		}
+		return []media.Item{{ContentID: rawurl}}, nil
+	})
+
+	loader, items, err := media.Resolve("resolve://video")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loader != "test.resolve.ok" || len(items) != 1 || items[0].ContentID != "resolve://video" {
+		t.Errorf("unexpected resolution by %s: %+v", loader, items)
+	}
+
+	_, _, err = media.Resolve("resolve://empty")
+	if err == nil || !strings.Contains(err.Error(), "test.resolve.failing: unavailable") {
+		t.Errorf("the errors of the loaders should be reported, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("adding a resolver to an unknown loader should panic")
+		}
+	}()
+	media.RegisterResolver("test.resolve.unknown", nil)
+}
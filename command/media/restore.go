@@ -0,0 +1,118 @@
+package media
+
+import (
+	"context"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command"
+)
+
+// reconnectNotifier is implemented by the clients which reconnect automatically (like client.Client)
+type reconnectNotifier interface {
+	OnReconnect(func())
+}
+
+func newApp(a *command.App) *App {
+	app := &App{
+		App: a,
+	}
+	if n, ok := a.Client.(reconnectNotifier); ok {
+		n.OnReconnect(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), command.DefaultTimeout)
+			defer cancel()
+			app.Restore(ctx)
+		})
+	}
+	return app
+}
+
+// Restore re-joins the app after a reconnection:
+// the transportId and the media sessions are re-discovered
+// (the existing Session keep working if the chromecast started a new media session).
+// The embedded Envelope is left untouched: the new transportId is only used by the methods of App.
+func (a *App) Restore(ctx context.Context) error {
+	env := a.envelope()
+	st, err := command.Launcher{Requester: a.Client}.StatusCtx(ctx)
+	if err != nil {
+		return err
+	}
+	destination, err := st.FirstDestinationSupporting(env.Namespace)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	changed := destination != env.Destination
+	a.destination = destination
+	updating := a.updating
+	a.mu.Unlock()
+	if changed {
+		if err = command.Connect.SendTo(a.Client, destination); err != nil {
+			return err
+		}
+		if updating {
+			// the previous listener was bound to the former transportId
+			go a.UpdateStatus()
+		}
+	}
+
+	previous := a.LatestStatus()
+	// the pushed statuses may come from the former session
+	status, err := a.fetchStatus(ctx)
+	if err != nil {
+		return err
+	}
+	a.renameSessions(previous, status)
+	return nil
+}
+
+// renameSessions maps the former session to the new one (when there is exactly one of each)
+func (a *App) renameSessions(previous, current []Status) {
+	var former, next []int
+	for _, s := range previous {
+		if s.SessionID > 0 {
+			former = append(former, s.SessionID)
+		}
+	}
+	for _, s := range current {
+		if s.SessionID > 0 {
+			next = append(next, s.SessionID)
+		}
+	}
+	if len(former) != 1 || len(next) != 1 || former[0] == next[0] {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.renamed == nil {
+		a.renamed = make(map[int]int, 1)
+	}
+	for old, renamed := range a.renamed {
+		if renamed == former[0] {
+			a.renamed[old] = next[0]
+		}
+	}
+	a.renamed[former[0]] = next[0]
+}
+
+// sessionID returns the current ID of a session (which may have been renamed by Restore)
+func (a *App) sessionID(id int) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if renamed, ok := a.renamed[id]; ok {
+		return renamed
+	}
+	return id
+}
+
+// envelope returns the envelope of the app, with the destination re-discovered by Restore (if any)
+func (a *App) envelope() chromecast.Envelope {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	env := a.Envelope
+	if a.destination != "" {
+		env.Destination = a.destination
+	}
+	return env
+}
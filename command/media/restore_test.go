@@ -0,0 +1,122 @@
+package media_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+// rebootedClient answers with the current transportId and media session
+type rebootedClient struct {
+	mu          sync.Mutex
+	transportID string
+	sessionID   int
+	sent        []chromecast.Envelope
+	payloads    []map[string]interface{}
+}
+
+func (c *rebootedClient) Listen(env chromecast.Envelope, responseType string, ch chan<- []byte) {}
+
+func (c *rebootedClient) Send(env chromecast.Envelope, payload interface{}) error {
+	b, _ := json.Marshal(payload)
+	var m map[string]interface{}
+	json.Unmarshal(b, &m)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, env)
+	c.payloads = append(c.payloads, m)
+	return nil
+}
+
+func (c *rebootedClient) Request(env chromecast.Envelope, payload chromecast.IdentifiablePayload) (<-chan []byte, error) {
+	c.Send(env, payload)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var response interface{}
+	if env.Namespace == media.Namespace {
+		response = map[string]interface{}{
+			"type":   "MEDIA_STATUS",
+			"status": []map[string]interface{}{{"mediaSessionId": c.sessionID, "playerState": "PLAYING"}},
+		}
+	} else {
+		response = map[string]interface{}{
+			"type": "RECEIVER_STATUS",
+			"status": map[string]interface{}{
+				"applications": []map[string]interface{}{{
+					"appId":       "CC1AD845",
+					"transportId": c.transportID,
+					"namespaces":  []map[string]string{{"name": media.Namespace}},
+				}},
+			},
+		}
+	}
+	b, _ := json.Marshal(response)
+	ch := make(chan []byte, 1)
+	ch <- b
+	close(ch)
+	return ch, nil
+}
+
+func (c *rebootedClient) Close() error {
+	return nil
+}
+
+func (c *rebootedClient) reboot(transportID string, sessionID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.transportID = transportID
+	c.sessionID = sessionID
+}
+
+func TestRestore(t *testing.T) {
+	client := &rebootedClient{transportID: "t1", sessionID: 1}
+	transportID := "t1"
+	app, err := media.ConnectFromStatus(client, chromecast.Status{
+		Applications: []*chromecast.ApplicationSession{{
+			TransportId: &transportID,
+			Namespaces:  []*chromecast.Namespace{{Name: media.Namespace}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = app.Status(); err != nil {
+		t.Fatal(err)
+	}
+	session, err := app.CurrentSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.reboot("t2", 7)
+	// the envelope may be read while restoring (checked by go test -race)
+	read := make(chan string)
+	go func() {
+		read <- app.Envelope.Destination
+	}()
+	if err = app.Restore(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if destination := <-read; destination != "t1" {
+		t.Errorf("the embedded envelope should be left untouched, got %s", destination)
+	}
+
+	if _, err = session.Play(); err != nil {
+		t.Fatal(err)
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	last := len(client.sent) - 1
+	if client.sent[last].Destination != "t2" {
+		t.Errorf("the PLAY should have been sent to the new transportId, got %s", client.sent[last].Destination)
+	}
+	if id := client.payloads[last]["mediaSessionId"]; id != float64(7) {
+		t.Errorf("the PLAY should have used the new session, got %v", id)
+	}
+}
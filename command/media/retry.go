@@ -0,0 +1,85 @@
+package media
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command"
+)
+
+// retryPolicyKey stores the RetryPolicy in the payload (it is removed before sending)
+const retryPolicyKey = "-retry-policy"
+
+// RetryPolicy of the loading, when the receiver answers LOAD_FAILED or LOAD_CANCELLED
+type RetryPolicy struct {
+	// Retries of the same item
+	Retries int
+	// Backoff before the first retry (doubled after each retry)
+	Backoff time.Duration
+	// Fallback is loaded (once) when the retries are exhausted, like another format of the media
+	// (it replaces the loaded item: the options setting the item are not applied to it)
+	Fallback *Item
+}
+
+// Retry the loading according to the policy
+func Retry(policy RetryPolicy) Option {
+	return func(c command.Map) {
+		c[retryPolicyKey] = policy
+	}
+}
+
+// popRetryPolicy removes the RetryPolicy from the payload
+func popRetryPolicy(payload command.Map) (RetryPolicy, bool) {
+	policy, ok := payload[retryPolicyKey].(RetryPolicy)
+	delete(payload, retryPolicyKey)
+	return policy, ok
+}
+
+// loadWithRetry sends the LOAD payload until it succeeds or the policy is exhausted
+// The last response is returned (even if it is an error, and also if a retry could not be sent)
+func (a *App) loadWithRetry(ctx context.Context, payload command.Map, policy RetryPolicy) ([]byte, error) {
+	backoff := policy.Backoff
+	var last []byte
+	for attempt := 0; ; attempt++ {
+		body, err := command.Request(ctx, a.Client, a.envelope(), payload)
+		if err != nil {
+			if last != nil {
+				return last, nil
+			}
+			return nil, err
+		}
+		var loadErr chromecast.LoadFailedError
+		if !errors.As(command.ResponseError(body), &loadErr) {
+			return body, nil
+		}
+		last = body
+		if attempt >= policy.Retries {
+			if policy.Fallback == nil {
+				return body, nil
+			}
+			payload["media"] = *policy.Fallback
+			policy.Fallback = nil
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return body, nil
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// loadFailedPayload returns a LOAD_FAILED payload for the error
+// (for the readers of the channel returned by Load)
+func loadFailedPayload(err error) []byte {
+	b, _ := json.Marshal(command.Map{
+		"type":   "LOAD_FAILED",
+		"reason": err.Error(),
+	})
+	return b
+}
@@ -0,0 +1,151 @@
+package media_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command"
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+// flakyClient answers LOAD_FAILED to the first failures LOADs (and to every LOAD of a broken contentId)
+type flakyClient struct {
+	mu       sync.Mutex
+	failures int
+	loaded   []string
+	// closed makes the requests fail once a LOAD was answered
+	closed bool
+}
+
+func (c *flakyClient) Listen(env chromecast.Envelope, responseType string, ch chan<- []byte) {}
+
+func (c *flakyClient) Send(env chromecast.Envelope, payload interface{}) error {
+	return nil
+}
+
+func (c *flakyClient) Request(env chromecast.Envelope, payload chromecast.IdentifiablePayload) (<-chan []byte, error) {
+	b, _ := json.Marshal(payload)
+	var m struct {
+		Media media.Item `json:"media"`
+	}
+	json.Unmarshal(b, &m)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed && len(c.loaded) > 0 {
+		return nil, chromecast.ErrConnectionClosed
+	}
+	c.loaded = append(c.loaded, m.Media.ContentID)
+	response := `{"type":"MEDIA_STATUS","status":[{"mediaSessionId":1,"playerState":"BUFFERING"}]}`
+	if c.failures > 0 || m.Media.ContentID == "broken" {
+		c.failures--
+		response = `{"type":"LOAD_FAILED","detailedErrorCode":104}`
+	}
+	ch := make(chan []byte, 1)
+	ch <- []byte(response)
+	close(ch)
+	return ch, nil
+}
+
+func (c *flakyClient) Close() error {
+	return nil
+}
+
+func connectFlaky(t *testing.T, client *flakyClient) *media.App {
+	transportID := "t1"
+	app, err := media.ConnectFromStatus(client, chromecast.Status{
+		Applications: []*chromecast.ApplicationSession{{
+			TransportId: &transportID,
+			Namespaces:  []*chromecast.Namespace{{Name: media.Namespace}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+func TestRetry(t *testing.T) {
+	client := &flakyClient{failures: 2}
+	app := connectFlaky(t, client)
+
+	session, err := app.LoadAndGetSession(media.Item{ContentID: "movie"}, media.Retry(media.RetryPolicy{
+		Retries: 2,
+		Backoff: time.Millisecond,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.ID != 1 {
+		t.Errorf("unexpected session: %d", session.ID)
+	}
+	if len(client.loaded) != 3 {
+		t.Errorf("3 LOAD expected, got %v", client.loaded)
+	}
+}
+
+func TestRetryFallback(t *testing.T) {
+	client := &flakyClient{}
+	app := connectFlaky(t, client)
+
+	_, err := app.LoadAndGetSession(media.Item{ContentID: "broken"}, media.Retry(media.RetryPolicy{
+		Retries:  1,
+		Fallback: &media.Item{ContentID: "fallback"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"broken", "broken", "fallback"}
+	if len(client.loaded) != len(expected) || client.loaded[2] != "fallback" {
+		t.Errorf("expected %v, got %v", expected, client.loaded)
+	}
+
+	client.loaded = nil
+	_, err = app.LoadAndGetSession(media.Item{ContentID: "broken"}, media.Retry(media.RetryPolicy{
+		Fallback: &media.Item{ContentID: "broken"},
+	}))
+	var loadErr chromecast.LoadFailedError
+	if !errors.As(err, &loadErr) || loadErr.DetailedErrorCode != 104 {
+		t.Errorf("a LoadFailedError was expected, got %v", err)
+	}
+}
+
+func TestRetryLoadFailed(t *testing.T) {
+	for _, client := range []*flakyClient{
+		// every attempt fails
+		{},
+		// the retry can not be sent
+		{closed: true},
+	} {
+		app := connectFlaky(t, client)
+		ch, err := app.Load(media.Item{ContentID: "broken"}, media.Retry(media.RetryPolicy{
+			Retries: 2,
+			Backoff: time.Millisecond,
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		payload, ok := <-ch
+		if !ok {
+			t.Fatal("the failure should be sent before closing the channel")
+		}
+		var loadErr chromecast.LoadFailedError
+		if err := command.ResponseError(payload); !errors.As(err, &loadErr) {
+			t.Errorf("a LoadFailedError was expected, got %v", err)
+		}
+	}
+
+	// the request can not be sent at all
+	client := &flakyClient{closed: true, loaded: []string{"previous"}}
+	app := connectFlaky(t, client)
+	ch, _ := app.Load(media.Item{ContentID: "movie"}, media.Retry(media.RetryPolicy{Retries: 1}))
+	payload := <-ch
+	if err := command.ResponseError(payload); err == nil || !strings.Contains(err.Error(), "connection closed") {
+		t.Errorf("the error of the request was expected, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package media_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+// absoluteSeekClient rejects the relative seeks
+type absoluteSeekClient struct {
+	seeks []map[string]interface{}
+}
+
+func (c *absoluteSeekClient) Listen(env chromecast.Envelope, responseType string, ch chan<- []byte) {}
+
+func (c *absoluteSeekClient) Send(env chromecast.Envelope, payload interface{}) error {
+	return nil
+}
+
+func (c *absoluteSeekClient) Request(env chromecast.Envelope, payload chromecast.IdentifiablePayload) (<-chan []byte, error) {
+	b, _ := json.Marshal(payload)
+	var m map[string]interface{}
+	json.Unmarshal(b, &m)
+
+	response := `{"type":"MEDIA_STATUS","status":[{"mediaSessionId":1,"playerState":"PAUSED","currentTime":60}]}`
+	if m["type"] == "SEEK" {
+		c.seeks = append(c.seeks, m)
+		if _, ok := m["relativeTime"]; ok {
+			response = `{"type":"INVALID_REQUEST","reason":"INVALID_COMMAND"}`
+		}
+	}
+	ch := make(chan []byte, 1)
+	ch <- []byte(response)
+	close(ch)
+	return ch, nil
+}
+
+func (c *absoluteSeekClient) Close() error {
+	return nil
+}
+
+func TestSeekByFallback(t *testing.T) {
+	client := &absoluteSeekClient{}
+	transportID := "t1"
+	app, err := media.ConnectFromStatus(client, chromecast.Status{
+		Applications: []*chromecast.ApplicationSession{{
+			TransportId: &transportID,
+			Namespaces:  []*chromecast.Namespace{{Name: media.Namespace}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = app.Status(); err != nil {
+		t.Fatal(err)
+	}
+	session, err := app.CurrentSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = session.SeekBy(-10 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.seeks) != 2 {
+		t.Fatalf("a relative and an absolute SEEK should have been sent, got %v", client.seeks)
+	}
+	if rel := client.seeks[0]["relativeTime"]; rel != float64(-10) {
+		t.Errorf("unexpected relativeTime: %v", rel)
+	}
+	if abs := client.seeks[1]["currentTime"]; abs != float64(50) {
+		t.Errorf("the paused media should have been seeked to 50s, got %v", abs)
+	}
+}
@@ -1,24 +1,51 @@
 package media
 
-import "github.com/oliverpool/go-chromecast/command"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command"
+)
 
 type Session struct {
 	*App
 	ID int `json:"mediaSessionId"`
 }
 
-func (s Session) do(cmd string, options ...Option) (<-chan []byte, error) {
+func (s Session) payload(cmd string, options []Option) command.Map {
 	payload := command.Map{
 		"type":           cmd,
-		"mediaSessionId": s.ID,
+		"mediaSessionId": s.App.sessionID(s.ID),
 	}
 	for _, opt := range options {
 		opt(payload)
 	}
-	return s.App.request(payload)
+	return payload
 }
 
-func (s Session) doEnsure(cmd, state string, options ...Option) (<-chan bool, error) {
+func (s Session) do(cmd string, options ...Option) (<-chan []byte, error) {
+	return s.App.request(s.payload(cmd, options))
+}
+
+// doCtx sends the command and updates the latest status with the response
+func (s Session) doCtx(ctx context.Context, cmd string, options ...Option) error {
+	body, err := command.Request(ctx, s.App.Client, s.App.envelope(), s.payload(cmd, options))
+	if err != nil {
+		return err
+	}
+	if err = command.ResponseError(body); err != nil {
+		return err
+	}
+	if sr, err := unmarshalStatus(body); err == nil && len(sr.Status) > 0 {
+		s.App.setStatus(sr.Status)
+	}
+	return nil
+}
+
+func (s Session) doEnsure(cmd string, state PlayerState, options ...Option) (<-chan bool, error) {
 	req, err := s.do(cmd, options...)
 	if err != nil {
 		return nil, err
@@ -36,7 +63,7 @@ func (s Session) doEnsure(cmd, state string, options ...Option) (<-chan bool, er
 }
 
 func (s Session) Pause(options ...Option) (<-chan bool, error) {
-	return s.doEnsure("PAUSE", "PAUSED", options...)
+	return s.doEnsure("PAUSE", Paused, options...)
 }
 
 func (s Session) Seek(options ...Option) (<-chan []byte, error) {
@@ -44,14 +71,140 @@ func (s Session) Seek(options ...Option) (<-chan []byte, error) {
 }
 
 func (s Session) Stop(options ...Option) (<-chan bool, error) {
-	return s.doEnsure("STOP", "IDLE", options...)
+	return s.doEnsure("STOP", Idle, options...)
 }
 
 func (s Session) Play(options ...Option) (<-chan bool, error) {
-	return s.doEnsure("PLAY", "PLAYING", options...)
+	return s.doEnsure("PLAY", Playing, options...)
+}
+
+// PlayCtx resumes the playback and waits for the response of the receiver
+// (a chromecast.RequestError if the command is rejected)
+func (s Session) PlayCtx(ctx context.Context, options ...Option) error {
+	return s.doCtx(ctx, "PLAY", options...)
+}
+
+// PauseCtx pauses the playback and waits for the response of the receiver
+func (s Session) PauseCtx(ctx context.Context, options ...Option) error {
+	return s.doCtx(ctx, "PAUSE", options...)
+}
+
+// StopCtx stops the playback (ending the session) and waits for the response of the receiver
+func (s Session) StopCtx(ctx context.Context, options ...Option) error {
+	return s.doCtx(ctx, "STOP", options...)
+}
+
+// SeekCtx moves the playback to t and waits for the response of the receiver
+func (s Session) SeekCtx(ctx context.Context, t time.Duration, options ...Option) error {
+	return s.doCtx(ctx, "SEEK", append(options, Seek(t))...)
+}
+
+// QueueJumpCtx skips n items of the queue (backwards if negative) and waits for the response of the receiver
+func (s Session) QueueJumpCtx(ctx context.Context, n int, options ...Option) error {
+	return s.doCtx(ctx, "QUEUE_UPDATE", append(options, func(c command.Map) {
+		c["jump"] = n
+	})...)
+}
+
+// Status returns the status of this media session only (waiting at most command.DefaultTimeout)
+func (s Session) Status() (Status, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), command.DefaultTimeout)
+	defer cancel()
+	return s.StatusCtx(ctx)
+}
+
+// StatusCtx returns the status of this media session only (chromecast.ErrRequestTimeout if ctx is done before)
+// The other sessions of the latest status of the app are kept.
+func (s Session) StatusCtx(ctx context.Context) (Status, error) {
+	id := s.App.sessionID(s.ID)
+	body, err := command.Request(ctx, s.App.Client, s.App.envelope(), s.payload("GET_STATUS", nil))
+	if err != nil {
+		return Status{}, err
+	}
+	if err = command.ResponseError(body); err != nil {
+		return Status{}, err
+	}
+	sr, err := unmarshalStatus(body)
+	if err != nil {
+		return Status{}, err
+	}
+	s.App.mergeStatus(sr.Status)
+	for _, st := range sr.Status {
+		if st.SessionID == id {
+			return st, nil
+		}
+	}
+	return Status{}, chromecast.ErrNoSession
+}
+
+// SeekBy moves the playback by delta (waiting at most command.DefaultTimeout)
+func (s Session) SeekBy(delta time.Duration, options ...Option) error {
+	ctx, cancel := context.WithTimeout(context.Background(), command.DefaultTimeout)
+	defer cancel()
+	return s.SeekByCtx(ctx, delta, options...)
+}
+
+// SeekByCtx moves the playback by delta, relatively to the position on the receiver.
+// If the receiver rejects the relative seek, the position is extrapolated from the latest status.
+func (s Session) SeekByCtx(ctx context.Context, delta time.Duration, options ...Option) error {
+	relative := append(options[:len(options):len(options)], func(c command.Map) {
+		c["relativeTime"] = delta.Seconds()
+	})
+	err := s.doCtx(ctx, "SEEK", relative...)
+	var reqErr chromecast.RequestError
+	if !errors.As(err, &reqErr) {
+		return err
+	}
+	current, ok := s.App.extrapolatedTime(s.App.sessionID(s.ID))
+	if !ok {
+		return err
+	}
+	target := current + delta
+	if target < 0 {
+		target = 0
+	}
+	return s.doCtx(ctx, "SEEK", append(options, Seek(target))...)
+}
+
+// SeekToLiveEdge seeks to the end of the seekable range of a live stream
+// (according to the latest status of the app)
+func (s Session) SeekToLiveEdge(options ...Option) (<-chan []byte, error) {
+	id := s.App.sessionID(s.ID)
+	for _, st := range s.App.LatestStatus() {
+		if st.SessionID == id && st.LiveSeekableRange != nil {
+			options = append(options, Seek(st.LiveSeekableRange.End.Duration))
+			return s.do("SEEK", options...)
+		}
+	}
+	return nil, fmt.Errorf("no seekable range for the session %d (not live?)", id)
+}
+
+// SetPlaybackRate changes the playback speed (1 is the normal speed)
+func (s Session) SetPlaybackRate(rate float64, options ...Option) (<-chan []byte, error) {
+	options = append(options, func(c command.Map) {
+		c["playbackRate"] = rate
+	})
+	return s.do("SET_PLAYBACK_RATE", options...)
+}
+
+// SetStreamVolume changes the volume of the stream (between 0 and 1),
+// without changing the volume of the device (see the command/volume package)
+func (s Session) SetStreamVolume(level float64, options ...Option) (<-chan []byte, error) {
+	options = append(options, func(c command.Map) {
+		c["volume"] = chromecast.Volume{Level: &level}
+	})
+	return s.do("SET_VOLUME", options...)
+}
+
+// MuteStream mutes (or unmutes) the stream, without muting the device
+func (s Session) MuteStream(muted bool, options ...Option) (<-chan []byte, error) {
+	options = append(options, func(c command.Map) {
+		c["volume"] = chromecast.Volume{Muted: &muted}
+	})
+	return s.do("SET_VOLUME", options...)
 }
 
-func playerStateIs(sr statusResponse, state string) bool {
+func playerStateIs(sr statusResponse, state PlayerState) bool {
 	for _, s := range sr.Status {
 		if s.PlayerState == state {
 			return true
@@ -0,0 +1,159 @@
+package media_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func TestSession(t *testing.T) {
+	client := &rebootedClient{transportID: "t1", sessionID: 5}
+	transportID := "t1"
+	app, err := media.ConnectFromStatus(client, chromecast.Status{
+		Applications: []*chromecast.ApplicationSession{{
+			TransportId: &transportID,
+			Namespaces:  []*chromecast.Namespace{{Name: media.Namespace}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = app.Session(5); err != chromecast.ErrNoSession {
+		t.Errorf("no session should be known before the first status, got %v", err)
+	}
+	if _, err = app.Status(); err != nil {
+		t.Fatal(err)
+	}
+	session, err := app.Session(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := session.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.SessionID != 5 {
+		t.Errorf("unexpected session: %d", st.SessionID)
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	sent := client.payloads[len(client.payloads)-1]
+	if sent["type"] != "GET_STATUS" || sent["mediaSessionId"] != float64(5) {
+		t.Errorf("the GET_STATUS should be filtered by mediaSessionId, got %v", sent)
+	}
+}
+
+func TestAttach(t *testing.T) {
+	client := &rebootedClient{transportID: "t1", sessionID: 9}
+	transportID := "t1"
+	st := chromecast.Status{
+		Applications: []*chromecast.ApplicationSession{{
+			TransportId: &transportID,
+			Namespaces:  []*chromecast.Namespace{{Name: media.Namespace}},
+		}},
+	}
+	_, session, err := media.Attach(context.Background(), client, st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.ID != 9 {
+		t.Errorf("unexpected session: %d", session.ID)
+	}
+	client.mu.Lock()
+	if client.payloads[0]["type"] != "CONNECT" || client.sent[0].Destination != "t1" {
+		t.Errorf("a virtual connection should have been opened first, got %v to %s", client.payloads[0], client.sent[0].Destination)
+	}
+	client.mu.Unlock()
+
+	// CurrentSession fetches the status when needed
+	app, err := media.ConnectFromStatus(client, st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session, err = app.CurrentSession(); err != nil || session.ID != 9 {
+		t.Errorf("the current session should have been fetched, got %v (%v)", session, err)
+	}
+}
+
+func TestStreamVolume(t *testing.T) {
+	client := &rebootedClient{transportID: "t1", sessionID: 2}
+	transportID := "t1"
+	app, err := media.ConnectFromStatus(client, chromecast.Status{
+		Applications: []*chromecast.ApplicationSession{{
+			TransportId: &transportID,
+			Namespaces:  []*chromecast.Namespace{{Name: media.Namespace}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := app.CurrentSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session.SetStreamVolume(.3)
+	session.MuteStream(true)
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	last := len(client.sent) - 1
+	if client.sent[last].Namespace != media.Namespace {
+		t.Errorf("the stream volume should be set on the media namespace, got %s", client.sent[last].Namespace)
+	}
+	level := client.payloads[last-1]["volume"].(map[string]interface{})
+	if client.payloads[last-1]["type"] != "SET_VOLUME" || level["level"] != .3 {
+		t.Errorf("unexpected SetStreamVolume payload: %v", client.payloads[last-1])
+	}
+	muted := client.payloads[last]["volume"].(map[string]interface{})
+	if muted["muted"] != true || muted["level"] != nil {
+		t.Errorf("unexpected MuteStream payload: %v", client.payloads[last])
+	}
+}
+
+func TestSessionCtx(t *testing.T) {
+	client := &rebootedClient{transportID: "t1", sessionID: 4}
+	transportID := "t1"
+	_, session, err := media.Attach(context.Background(), client, chromecast.Status{
+		Applications: []*chromecast.ApplicationSession{{
+			TransportId: &transportID,
+			Namespaces:  []*chromecast.Namespace{{Name: media.Namespace}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	cc := []struct {
+		do       func() error
+		expected map[string]interface{}
+	}{
+		{func() error { return session.PlayCtx(ctx) }, map[string]interface{}{"type": "PLAY"}},
+		{func() error { return session.PauseCtx(ctx) }, map[string]interface{}{"type": "PAUSE"}},
+		{func() error { return session.StopCtx(ctx) }, map[string]interface{}{"type": "STOP"}},
+		{func() error { return session.SeekCtx(ctx, 90*time.Second) }, map[string]interface{}{"type": "SEEK", "currentTime": float64(90)}},
+		{func() error { return session.QueueJumpCtx(ctx, -1) }, map[string]interface{}{"type": "QUEUE_UPDATE", "jump": float64(-1)}},
+	}
+	for _, c := range cc {
+		if err := c.do(); err != nil {
+			t.Errorf("%s: %v", c.expected["type"], err)
+			continue
+		}
+		client.mu.Lock()
+		sent := client.payloads[len(client.payloads)-1]
+		client.mu.Unlock()
+		if sent["mediaSessionId"] != float64(4) {
+			t.Errorf("%s should target the session, got %v", c.expected["type"], sent)
+		}
+		for k, v := range c.expected {
+			if sent[k] != v {
+				t.Errorf("unexpected %s: %v (expected %v)", k, sent[k], v)
+			}
+		}
+	}
+}
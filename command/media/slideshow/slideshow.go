@@ -0,0 +1,114 @@
+// Package slideshow casts a list of images, one after the other
+package slideshow
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+// DefaultDuration of a slide
+const DefaultDuration = 10 * time.Second
+
+// Slideshow of images
+type Slideshow struct {
+	URLs []string
+	// Duration of each slide (DefaultDuration if 0)
+	Duration time.Duration
+	// Loop restarts with the first image after the last one
+	Loop bool
+}
+
+// ContentType of an image URL, according to its extension (empty if it is not an image)
+func ContentType(rawurl string) string {
+	p := rawurl
+	if u, err := url.Parse(rawurl); err == nil {
+		p = u.Path
+	}
+	switch strings.ToLower(path.Ext(p)) {
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".png":
+		return "image/png"
+	case ".gif":
+		return "image/gif"
+	case ".webp":
+		return "image/webp"
+	case ".bmp":
+		return "image/bmp"
+	default:
+		return ""
+	}
+}
+
+func (s Slideshow) duration() time.Duration {
+	if s.Duration <= 0 {
+		return DefaultDuration
+	}
+	return s.Duration
+}
+
+// Items returns the queue of the images
+func (s Slideshow) Items() ([]media.QueueItem, error) {
+	items := make([]media.QueueItem, 0, len(s.URLs))
+	for _, u := range s.URLs {
+		contentType := ContentType(u)
+		if contentType == "" {
+			return nil, fmt.Errorf("could not find the image type of '%s'", u)
+		}
+		item := media.NewQueueItem(media.Item{
+			ContentID:   u,
+			ContentType: contentType,
+			StreamType:  "BUFFERED",
+			Metadata:    media.PhotoMediaMetadata{Title: path.Base(u)},
+		})
+		item.PreloadTime = media.Seconds{Duration: s.duration() / 2}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Run loads the images on the app and shows the next one after each Duration
+// (the receivers do not advance by themselves on images)
+// It returns when the last image was shown (never if Loop is set) or when ctx is done.
+func (s Slideshow) Run(ctx context.Context, app *media.App) error {
+	items, err := s.Items()
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("no image to show")
+	}
+	var options []media.Option
+	if s.Loop {
+		options = append(options, media.WithRepeatMode(media.RepeatAll))
+	}
+	session, err := app.QueueLoadAndGetSessionCtx(ctx, items, options...)
+	if err != nil {
+		return fmt.Errorf("could not load the images: %w", err)
+	}
+
+	ticker := time.NewTicker(s.duration())
+	defer ticker.Stop()
+	for shown := 1; s.Loop || shown < len(items); shown++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if _, err = session.QueueJump(1); err != nil {
+			return fmt.Errorf("could not show the next image: %w", err)
+		}
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-ticker.C:
+		return nil
+	}
+}
@@ -0,0 +1,33 @@
+package slideshow_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oliverpool/go-chromecast/command/media/slideshow"
+)
+
+func TestItems(t *testing.T) {
+	s := slideshow.Slideshow{
+		URLs:     []string{"http://example.com/a.JPG", "http://example.com/b.png?size=large"},
+		Duration: 4 * time.Second,
+	}
+	items, err := s.Items()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("2 items expected, got %d", len(items))
+	}
+	if items[0].Media.ContentType != "image/jpeg" || items[1].Media.ContentType != "image/png" {
+		t.Errorf("unexpected content types: %s, %s", items[0].Media.ContentType, items[1].Media.ContentType)
+	}
+	if !items[0].Autoplay || items[0].PreloadTime.Duration != 2*time.Second {
+		t.Errorf("the slides should be autoplayed and preloaded, got %+v", items[0])
+	}
+
+	s.URLs = append(s.URLs, "http://example.com/movie.mp4")
+	if _, err = s.Items(); err == nil {
+		t.Error("a video should not be accepted")
+	}
+}
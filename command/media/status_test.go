@@ -0,0 +1,97 @@
+package media_test
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func TestStatusEndOfPlayback(t *testing.T) {
+	cases := []struct {
+		payload  string
+		finished bool
+		errored  bool
+	}{
+		{`{"playerState":"IDLE","idleReason":"FINISHED"}`, true, false},
+		{`{"playerState":"IDLE","idleReason":"ERROR"}`, false, true},
+		{`{"playerState":"IDLE","idleReason":"CANCELLED"}`, false, false},
+		{`{"playerState":"PLAYING"}`, false, false},
+	}
+	for _, c := range cases {
+		var st media.Status
+		if err := json.Unmarshal([]byte(c.payload), &st); err != nil {
+			t.Fatal(err)
+		}
+		if st.Finished() != c.finished {
+			t.Errorf("%s: Finished() should be %v", c.payload, c.finished)
+		}
+		if st.Errored() != c.errored {
+			t.Errorf("%s: Errored() should be %v", c.payload, c.errored)
+		}
+	}
+}
+
+// slowStatusClient answers the GET_STATUS once release is closed
+type slowStatusClient struct {
+	requests int32
+	release  chan struct{}
+}
+
+func (c *slowStatusClient) Listen(env chromecast.Envelope, responseType string, ch chan<- []byte) {}
+
+func (c *slowStatusClient) Send(env chromecast.Envelope, payload interface{}) error {
+	return nil
+}
+
+func (c *slowStatusClient) Request(env chromecast.Envelope, payload chromecast.IdentifiablePayload) (<-chan []byte, error) {
+	atomic.AddInt32(&c.requests, 1)
+	ch := make(chan []byte, 1)
+	go func() {
+		<-c.release
+		ch <- []byte(`{"type":"MEDIA_STATUS","status":[{"mediaSessionId":1,"playerState":"PLAYING"}]}`)
+	}()
+	return ch, nil
+}
+
+func (c *slowStatusClient) Close() error {
+	return nil
+}
+
+func TestStatusCoalescing(t *testing.T) {
+	client := &slowStatusClient{release: make(chan struct{})}
+	transportID := "t1"
+	app, err := media.ConnectFromStatus(client, chromecast.Status{
+		Applications: []*chromecast.ApplicationSession{{
+			TransportId: &transportID,
+			Namespaces:  []*chromecast.Namespace{{Name: media.Namespace}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	connects := atomic.LoadInt32(&client.requests)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			st, err := app.Status()
+			if err != nil || len(st) != 1 {
+				t.Errorf("unexpected status: %v (%v)", st, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(client.release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&client.requests) - connects; n != 1 {
+		t.Errorf("the concurrent GET_STATUS should have been coalesced, got %d requests", n)
+	}
+}
@@ -0,0 +1,75 @@
+// Package styledreceiver loads the media on a styled (or custom) receiver, registered on the
+// Google Cast SDK Developer Console: it speaks the media namespace, like the default receiver.
+package styledreceiver
+
+import (
+	"errors"
+
+	"github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+	"github.com/oliverpool/go-chromecast/command/media/defaultreceiver"
+)
+
+// AppID of the receiver
+// (if empty, the settings of the "styled" loader are used, like {"app_id": "ABCD1234"}, see media.LoaderConfig)
+var AppID string
+
+// errNoAppID is returned when no app ID is set nor configured
+var errNoAppID = errors.New("no app id of the styled receiver configured")
+
+// appID returns the AppID or the configured one
+func appID() (string, error) {
+	if AppID != "" {
+		return AppID, nil
+	}
+	var settings struct {
+		AppID string `json:"app_id"`
+	}
+	if _, err := media.LoaderConfig("styled", &settings); err != nil {
+		return "", err
+	}
+	if settings.AppID == "" {
+		return "", errNoAppID
+	}
+	return settings.AppID, nil
+}
+
+func LaunchAndConnect(client chromecast.Client, statuses ...chromecast.Status) (*media.App, error) {
+	id, err := appID()
+	if err != nil {
+		return nil, err
+	}
+	return media.LaunchAndConnect(client, id, statuses...)
+}
+
+func init() {
+	// before the default receiver, when an app ID is configured
+	media.RegisterMatchingLoader("styled", media.DefaultPriority+5, URLLoader, CanHandle)
+}
+
+// CanHandle returns true for the URLs supported by the default receiver (if an app ID is configured)
+func CanHandle(rawurl string) bool {
+	_, err := appID()
+	return err == nil && defaultreceiver.CanHandle(rawurl)
+}
+
+func URLLoader(rawurl string, options ...media.Option) (func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error), error) {
+	if _, err := appID(); err != nil {
+		return nil, err
+	}
+	contentType, err := defaultreceiver.ExtractType(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error) {
+		app, err := LaunchAndConnect(client, statuses...)
+		if err != nil {
+			return nil, err
+		}
+		return app.Load(media.Item{
+			ContentID:   rawurl,
+			ContentType: contentType,
+			StreamType:  "BUFFERED",
+		}, options...)
+	}, nil
+}
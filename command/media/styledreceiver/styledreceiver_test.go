@@ -0,0 +1,35 @@
+package styledreceiver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func TestCanHandle(t *testing.T) {
+	if CanHandle("http://example.com/video.mp4") {
+		t.Error("no URL should be handled without app ID")
+	}
+	if _, err := URLLoader("http://example.com/video.mp4"); err != errNoAppID {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	defer media.SetLoaderConfigs(nil)
+	media.SetLoaderConfigs(map[string]json.RawMessage{"styled": json.RawMessage(`{"app_id":"ABCD1234"}`)})
+	if id, err := appID(); id != "ABCD1234" || err != nil {
+		t.Errorf("unexpected app ID: %s (%v)", id, err)
+	}
+	if !CanHandle("http://example.com/video.mp4") {
+		t.Error("the URL should be handled with a configured app ID")
+	}
+	if CanHandle("http://example.com/page.html") {
+		t.Error("the URLs without supported content-type should not be handled")
+	}
+
+	defer func() { AppID = "" }()
+	AppID = "1234ABCD"
+	if id, _ := appID(); id != "1234ABCD" {
+		t.Errorf("the AppID should have precedence over the configuration, got %s", id)
+	}
+}
@@ -0,0 +1,106 @@
+package media
+
+import "github.com/oliverpool/go-chromecast/command"
+
+// Types of the tracks
+const (
+	TextTrack  = "TEXT"
+	AudioTrack = "AUDIO"
+	VideoTrack = "VIDEO"
+)
+
+// Subtypes of the text tracks
+const (
+	SubtitlesSubtype = "SUBTITLES"
+	CaptionsSubtype  = "CAPTIONS"
+)
+
+// Track of a media (like the subtitles)
+type Track struct {
+	TrackID int `json:"trackId"`
+	// Type like TextTrack
+	Type             string `json:"type"`
+	TrackContentID   string `json:"trackContentId,omitempty"`
+	TrackContentType string `json:"trackContentType,omitempty"`
+	// Subtype of a text track, like SubtitlesSubtype
+	Subtype  string `json:"subtype,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Language string `json:"language,omitempty"` // RFC 5646, like en-US
+}
+
+// WithSubtitles adds the WebVTT subtitles to the loaded item.
+// The first subtitles are activated.
+// The receiver fetches them with CORS: the server must allow it (Access-Control-Allow-Origin)
+func WithSubtitles(url string, name string, language string) Option {
+	return func(c command.Map) {
+		item, ok := c["media"].(Item)
+		if !ok {
+			return
+		}
+		track := Track{
+			TrackID:          len(item.Tracks) + 1,
+			Type:             TextTrack,
+			TrackContentID:   url,
+			TrackContentType: "text/vtt",
+			Subtype:          SubtitlesSubtype,
+			Name:             name,
+			Language:         language,
+		}
+		item.Tracks = append(item.Tracks[:len(item.Tracks):len(item.Tracks)], track)
+		c["media"] = item
+		if _, ok := c["activeTrackIds"]; !ok {
+			c["activeTrackIds"] = []int{track.TrackID}
+		}
+	}
+}
+
+// Edge types of the text tracks
+const (
+	EdgeNone       = "NONE"
+	EdgeOutline    = "OUTLINE"
+	EdgeDropShadow = "DROP_SHADOW"
+	EdgeRaised     = "RAISED"
+	EdgeDepressed  = "DEPRESSED"
+)
+
+// TextTrackStyle describes the appearance of the subtitles
+// The colors are formatted as #RRGGBBAA
+type TextTrackStyle struct {
+	// FontScale of the text (1 is the default size)
+	FontScale       float64 `json:"fontScale,omitempty"`
+	ForegroundColor string  `json:"foregroundColor,omitempty"`
+	BackgroundColor string  `json:"backgroundColor,omitempty"`
+	// EdgeType like EdgeOutline
+	EdgeType   string `json:"edgeType,omitempty"`
+	EdgeColor  string `json:"edgeColor,omitempty"`
+	FontFamily string `json:"fontFamily,omitempty"`
+}
+
+// DefaultTextTrackStyle is a white text with a black outline, without background
+func DefaultTextTrackStyle() TextTrackStyle {
+	return TextTrackStyle{
+		FontScale:       1,
+		ForegroundColor: "#FFFFFFFF",
+		BackgroundColor: "#00000000",
+		EdgeType:        EdgeOutline,
+		EdgeColor:       "#000000FF",
+	}
+}
+
+// WithTextTrackStyle sets the style of the subtitles of the loaded item
+func WithTextTrackStyle(style TextTrackStyle) Option {
+	return func(c command.Map) {
+		if item, ok := c["media"].(Item); ok {
+			item.TextTrackStyle = &style
+			c["media"] = item
+		}
+	}
+}
+
+// SetTextTrackStyle changes the style of the subtitles of the session
+func (s Session) SetTextTrackStyle(style TextTrackStyle, options ...Option) (<-chan []byte, error) {
+	options = append(options, func(c command.Map) {
+		c["textTrackStyle"] = style
+	})
+	return s.do("EDIT_TRACKS_INFO", options...)
+}
@@ -0,0 +1,26 @@
+package media_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/oliverpool/go-chromecast/command"
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+func TestWithSubtitles(t *testing.T) {
+	payload := command.Map{"media": media.Item{ContentID: "http://example.com/movie.mp4"}}
+	media.WithSubtitles("http://example.com/movie.en.vtt", "English", "en")(payload)
+	media.WithSubtitles("http://example.com/movie.fr.vtt", "Français", "fr")(payload)
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"activeTrackIds":[1],"media":{"contentId":"http://example.com/movie.mp4","streamType":"","contentType":"","tracks":[` +
+		`{"trackId":1,"type":"TEXT","trackContentId":"http://example.com/movie.en.vtt","trackContentType":"text/vtt","subtype":"SUBTITLES","name":"English","language":"en"},` +
+		`{"trackId":2,"type":"TEXT","trackContentId":"http://example.com/movie.fr.vtt","trackContentType":"text/vtt","subtype":"SUBTITLES","name":"Français","language":"fr"}]}}`
+	if string(b) != expected {
+		t.Errorf("unexpected JSON:\n%s\nexpected:\n%s", b, expected)
+	}
+}
@@ -0,0 +1,140 @@
+package vimeo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+const apiURL = "https://api.vimeo.com"
+
+// AccessToken of the Vimeo API (see https://developer.vimeo.com/apps).
+// It is required for the private, password-protected and on-demand videos
+// (which are loaded on the default receiver).
+// If empty, the settings of the "vimeo" loader are used (see media.LoaderConfig).
+var AccessToken string
+
+// accessToken returns the AccessToken or the configured one
+func accessToken() (string, error) {
+	if AccessToken != "" {
+		return AccessToken, nil
+	}
+	var settings struct {
+		AccessToken string `json:"access_token"`
+	}
+	_, err := media.LoaderConfig("vimeo", &settings)
+	return settings.AccessToken, err
+}
+
+// maxWidth of the progressive files (the larger ones are not supported by all the chromecasts)
+const maxWidth = 1920
+
+// Video returned by the Vimeo API
+type Video struct {
+	Name    string `json:"name"`
+	Privacy struct {
+		View string `json:"view"` // anybody, unlisted, password, nobody...
+	} `json:"privacy"`
+	User struct {
+		Name string `json:"name"`
+	} `json:"user"`
+	Pictures struct {
+		Sizes []struct {
+			Width int    `json:"width"`
+			Link  string `json:"link"`
+		} `json:"sizes"`
+	} `json:"pictures"`
+	Play struct {
+		Progressive []File `json:"progressive"`
+		HLS         struct {
+			Link string `json:"link"`
+		} `json:"hls"`
+	} `json:"play"`
+	// Files are only available to the owner of the video
+	Files []File `json:"files"`
+}
+
+// File of a video
+type File struct {
+	Type  string `json:"type"`
+	Width int    `json:"width"`
+	Link  string `json:"link"`
+}
+
+// FetchVideo gets the video (hash is required for an unlisted video) with the access token
+func FetchVideo(id, hash string) (Video, error) {
+	var v Video
+	if id == "" {
+		return v, fmt.Errorf("empty video id")
+	}
+	token, err := accessToken()
+	if err != nil {
+		return v, err
+	}
+	path := "/videos/" + id
+	if hash != "" {
+		path += ":" + hash
+	}
+	req, err := http.NewRequest(http.MethodGet, apiURL+path, nil)
+	if err != nil {
+		return v, err
+	}
+	req.Header.Set("Authorization", "bearer "+token)
+	req.Header.Set("Accept", "application/vnd.vimeo.*+json;version=3.4")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return v, fmt.Errorf("could not get the video %s: %v", id, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return v, fmt.Errorf("could not get the video %s: unexpected status %s", id, resp.Status)
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return v, fmt.Errorf("could not parse the video %s: %v", id, err)
+	}
+	return v, nil
+}
+
+// Item returns the largest progressive file (or the HLS stream)
+func (v Video) Item() (media.Item, error) {
+	var best File
+	for _, f := range append(v.Play.Progressive, v.Files...) {
+		if f.Type != "video/mp4" || f.Link == "" || f.Width > maxWidth {
+			continue
+		}
+		if f.Width > best.Width {
+			best = f
+		}
+	}
+	item := media.Item{
+		ContentID:   best.Link,
+		ContentType: "video/mp4",
+		StreamType:  "BUFFERED",
+		Metadata:    v.metadata(),
+	}
+	if best.Link == "" {
+		if v.Play.HLS.Link == "" {
+			return media.Item{}, fmt.Errorf("no playable file for the video '%s'", v.Name)
+		}
+		item.ContentID = v.Play.HLS.Link
+		item.ContentType = "application/x-mpegurl"
+	}
+	return item, nil
+}
+
+func (v Video) metadata() media.GenericMediaMetadata {
+	metadata := media.GenericMediaMetadata{
+		Title:    v.Name,
+		Subtitle: v.User.Name,
+	}
+	var width int
+	for _, p := range v.Pictures.Sizes {
+		if p.Width > width {
+			width = p.Width
+			metadata.Images = []media.Image{{URL: p.Link}}
+		}
+	}
+	return metadata
+}
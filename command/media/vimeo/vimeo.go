@@ -3,10 +3,12 @@ package vimeo
 import (
 	"fmt"
 	"net/url"
-	"path"
+	"regexp"
+	"strings"
 
 	"github.com/oliverpool/go-chromecast"
 	"github.com/oliverpool/go-chromecast/command/media"
+	"github.com/oliverpool/go-chromecast/command/media/defaultreceiver"
 )
 
 const ID = "7742C69E"
@@ -29,34 +31,102 @@ func (a App) Load(id string, options ...media.Option) (<-chan []byte, error) {
 	return a.App.Load(item, options...)
 }
 
+func init() {
+	media.RegisterMatchingLoader("vimeo", media.SitePriority, URLLoader, CanHandle)
+}
+
+// CanHandle returns true for the URLs of a video on vimeo.com
+func CanHandle(rawurl string) bool {
+	_, _, err := extractVideo(rawurl)
+	return err == nil
+}
+
 func URLLoader(rawurl string, options ...media.Option) (func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error), error) {
-	id, err := ExtractID(rawurl)
+	id, hash, err := extractVideo(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	token, err := accessToken()
+	if err != nil {
+		return nil, err
+	}
+	if token == "" && hash == "" {
+		return func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error) {
+			app, err := LaunchAndConnect(client, statuses...)
+			if err != nil {
+				return nil, err
+			}
+			return app.Load("/videos/"+id, options...)
+		}, nil
+	}
+	if token == "" {
+		return nil, fmt.Errorf("an access token is required for the unlisted video %s", id)
+	}
+
+	v, err := FetchVideo(id, hash)
+	if err != nil {
+		return nil, err
+	}
+	if v.Privacy.View == "anybody" {
+		return func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error) {
+			app, err := LaunchAndConnect(client, statuses...)
+			if err != nil {
+				return nil, err
+			}
+			return app.Load("/videos/"+id, append([]media.Option{media.Metadata(v.metadata())}, options...)...)
+		}, nil
+	}
+	// the Vimeo receiver can only play the public videos
+	item, err := v.Item()
 	if err != nil {
 		return nil, err
 	}
 	return func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error) {
-		app, err := LaunchAndConnect(client, statuses...)
+		app, err := defaultreceiver.LaunchAndConnect(client, statuses...)
 		if err != nil {
 			return nil, err
 		}
-		return app.Load(id, options...)
+		return app.Load(item, options...)
 	}, nil
 }
 
 func ExtractID(rawurl string) (string, error) {
+	id, _, err := extractVideo(rawurl)
+	if err != nil {
+		return "", err
+	}
+	return "/videos/" + id, nil
+}
+
+var (
+	numeric = regexp.MustCompile(`^[0-9]+$`)
+	hex     = regexp.MustCompile(`^[0-9a-f]+$`)
+)
+
+// extractVideo returns the id of the video (and the hash of an unlisted video,
+// like https://vimeo.com/123456/abcdef1234)
+func extractVideo(rawurl string) (id string, hash string, err error) {
 	u, err := url.Parse(rawurl)
 	if err != nil {
-		return "", fmt.Errorf("could not parse url '%s': %v", rawurl, err)
+		return "", "", fmt.Errorf("could not parse url '%s': %v", rawurl, err)
 	}
 
 	hosts := map[string]struct{}{
 		"vimeo.com": struct{}{},
 	}
 	if _, ok := hosts[u.Host]; !ok {
-		return "", fmt.Errorf("unsupported host: %s", u.Host)
+		return "", "", fmt.Errorf("unsupported host: %s", u.Host)
 	}
-	if id := path.Base(u.Path); id != "" {
-		return "/videos/" + id, nil
+	// like /channels/staffpicks/276738707 or /ondemand/name/276738707
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, p := range parts {
+		if !numeric.MatchString(p) {
+			continue
+		}
+		if i+1 < len(parts) && hex.MatchString(parts[i+1]) {
+			hash = parts[i+1]
+		}
+		return p, hash, nil
 	}
-	return "", fmt.Errorf("could not find id inside URL: %s", rawurl)
+	return "", "", fmt.Errorf("could not find id inside URL: %s", rawurl)
 }
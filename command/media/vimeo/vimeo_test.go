@@ -1,6 +1,7 @@
 package vimeo_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/oliverpool/go-chromecast/command/media/vimeo"
@@ -13,6 +14,8 @@ func TestURLParsing(t *testing.T) {
 	}{
 		{"https://vimeo.com/channels/staffpicks/276738707", "/videos/276738707"},
 		{"https://vimeo.com/276405604", "/videos/276405604"},
+		{"https://vimeo.com/276405604/abcdef1234", "/videos/276405604"},
+		{"https://vimeo.com/ondemand/somefilm/276405604", "/videos/276405604"},
 	}
 
 	for _, c := range cc {
@@ -25,3 +28,38 @@ func TestURLParsing(t *testing.T) {
 		}
 	}
 }
+
+func TestVideoItem(t *testing.T) {
+	body := `{"name":"Film","privacy":{"view":"password"},"user":{"name":"Director"},
+		"pictures":{"sizes":[{"width":640,"link":"https://i.vimeocdn.com/640.jpg"},{"width":1280,"link":"https://i.vimeocdn.com/1280.jpg"}]},
+		"play":{"progressive":[
+			{"type":"video/mp4","width":640,"link":"https://vod.vimeocdn.com/640.mp4"},
+			{"type":"video/mp4","width":3840,"link":"https://vod.vimeocdn.com/3840.mp4"},
+			{"type":"video/mp4","width":1920,"link":"https://vod.vimeocdn.com/1920.mp4"}
+		],"hls":{"link":"https://vod.vimeocdn.com/master.m3u8"}}}`
+	var v vimeo.Video
+	if err := json.Unmarshal([]byte(body), &v); err != nil {
+		t.Fatal(err)
+	}
+	item, err := v.Item()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ContentID != "https://vod.vimeocdn.com/1920.mp4" || item.ContentType != "video/mp4" {
+		t.Errorf("the largest supported progressive file should be used, got %+v", item)
+	}
+
+	v.Play.Progressive = nil
+	item, err = v.Item()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ContentID != "https://vod.vimeocdn.com/master.m3u8" || item.ContentType != "application/x-mpegurl" {
+		t.Errorf("the HLS stream should be used without progressive file, got %+v", item)
+	}
+
+	v.Play.HLS.Link = ""
+	if _, err = v.Item(); err == nil {
+		t.Error("an error was expected without any file")
+	}
+}
@@ -0,0 +1,59 @@
+package media
+
+import (
+	"context"
+	"errors"
+)
+
+// Condition on the status of a media session, like a PlayerState (Playing, Paused...) or Finished
+type Condition interface {
+	Matches(Status) bool
+}
+
+// Matches returns true if the player is in this state
+func (p PlayerState) Matches(s Status) bool {
+	return s.PlayerState == p
+}
+
+type conditionFunc func(Status) bool
+
+func (c conditionFunc) Matches(s Status) bool {
+	return c(s)
+}
+
+// Finished matches a media which was played until the end
+var Finished Condition = conditionFunc(Status.Finished)
+
+// Ended matches a media which was played until the end or which stopped on an error
+var Ended Condition = conditionFunc(func(s Status) bool {
+	return s.Finished() || s.Errored()
+})
+
+// errEventsClosed is returned by WaitFor when the App is closed
+var errEventsClosed = errors.New("the events of the app were closed")
+
+// WaitFor blocks until the status of the session matches the condition
+// (ctx.Err() is returned if ctx is done before)
+func (s Session) WaitFor(ctx context.Context, cond Condition) (Status, error) {
+	events := s.App.Events()
+	defer s.App.stopEvents(events)
+
+	for _, st := range s.App.LatestStatus() {
+		if st.SessionID == s.App.sessionID(s.ID) && cond.Matches(st) {
+			return st, nil
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return Status{}, ctx.Err()
+		case st, ok := <-events:
+			if !ok {
+				return Status{}, errEventsClosed
+			}
+			if st.SessionID == s.App.sessionID(s.ID) && cond.Matches(st) {
+				return st, nil
+			}
+		}
+	}
+}
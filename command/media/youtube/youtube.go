@@ -2,8 +2,14 @@ package youtube
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"net/url"
 	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/oliverpool/go-chromecast"
 	"github.com/oliverpool/go-chromecast/command/media"
@@ -29,24 +35,75 @@ func (a App) Load(id string, options ...media.Option) (<-chan []byte, error) {
 	return a.App.Load(item, options...)
 }
 
+func init() {
+	media.RegisterMatchingLoader("youtube", media.SitePriority, URLLoader, CanHandle)
+}
+
+// CanHandle returns true for the URLs of a video (or a playlist) on YouTube
+func CanHandle(rawurl string) bool {
+	_, err := ExtractVideo(rawurl)
+	return err == nil
+}
+
 func URLLoader(rawurl string, options ...media.Option) (func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error), error) {
-	id, err := ExtractID(rawurl)
+	v, err := ExtractVideo(rawurl)
 	if err != nil {
 		return nil, err
 	}
+	if v.ID == "" {
+		if v.ID, err = firstVideo(v.ListID); err != nil {
+			return nil, fmt.Errorf("could not find the first video of the playlist %s: %v", v.ListID, err)
+		}
+	}
 	return func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error) {
 		app, err := LaunchAndConnect(client, statuses...)
 		if err != nil {
 			return nil, err
 		}
-		return app.Load(id, options...)
+		return app.LoadVideo(v, options...)
 	}, nil
 }
 
+// Video to load, with its playlist and its start offset
+type Video struct {
+	ID     string
+	ListID string // played after the video (optional)
+	Start  time.Duration
+}
+
+// LoadVideo loads the video at its start offset, followed by the videos of its playlist
+func (a App) LoadVideo(v Video, options ...media.Option) (<-chan []byte, error) {
+	item := media.Item{
+		ContentID:   v.ID,
+		ContentType: "x-youtube/video",
+		StreamType:  "BUFFERED",
+	}
+	if v.ListID != "" {
+		item.CustomData = map[string]string{"listId": v.ListID}
+	}
+	if v.Start > 0 {
+		// the given options may override the start offset
+		options = append([]media.Option{media.Seek(v.Start)}, options...)
+	}
+	return a.App.Load(item, options...)
+}
+
 func ExtractID(rawurl string) (string, error) {
+	v, err := ExtractVideo(rawurl)
+	if err != nil {
+		return "", err
+	}
+	if v.ID == "" {
+		return "", fmt.Errorf("could not find id inside URL: %s", rawurl)
+	}
+	return v.ID, nil
+}
+
+// ExtractVideo returns the video of the URL (the ID is empty for the URL of a playlist)
+func ExtractVideo(rawurl string) (Video, error) {
 	u, err := url.Parse(rawurl)
 	if err != nil {
-		return "", fmt.Errorf("could not parse url '%s': %v", rawurl, err)
+		return Video{}, fmt.Errorf("could not parse url '%s': %v", rawurl, err)
 	}
 
 	hosts := map[string]struct{}{
@@ -55,15 +112,66 @@ func ExtractID(rawurl string) (string, error) {
 		"youtu.be":                 struct{}{},
 		"youtube.com":              struct{}{},
 		"www.youtube.com":          struct{}{},
+		"m.youtube.com":            struct{}{},
+		"music.youtube.com":        struct{}{},
 	}
 	if _, ok := hosts[u.Host]; !ok {
-		return "", fmt.Errorf("unsupported host: %s", u.Host)
+		return Video{}, fmt.Errorf("unsupported host: %s", u.Host)
+	}
+	query := u.Query()
+	v := Video{
+		ID:     query.Get("v"),
+		ListID: query.Get("list"),
+	}
+	if v.ID == "" && path.Base(u.Path) != "playlist" {
+		v.ID = path.Base(u.Path)
+	}
+	if v.ID == "" && v.ListID == "" {
+		return Video{}, fmt.Errorf("could not find id inside URL: %s", rawurl)
+	}
+
+	t := query.Get("t")
+	if t == "" {
+		t = query.Get("start")
+	}
+	if t == "" && strings.HasPrefix(u.Fragment, "t=") {
+		t = strings.TrimPrefix(u.Fragment, "t=")
+	}
+	if t != "" {
+		if v.Start, err = parseTimestamp(t); err != nil {
+			return Video{}, fmt.Errorf("could not parse the timestamp '%s': %v", t, err)
+		}
 	}
-	if id := u.Query().Get("v"); id != "" {
-		return id, nil
+	return v, nil
+}
+
+// parseTimestamp like 90, 90s or 1m30s
+func parseTimestamp(t string) (time.Duration, error) {
+	if s, err := strconv.Atoi(t); err == nil {
+		return time.Duration(s) * time.Second, nil
+	}
+	return time.ParseDuration(t)
+}
+
+var videoIDRegexp = regexp.MustCompile(`"videoId":"([0-9A-Za-z_-]{11})"`)
+
+// firstVideo of the playlist (found in the playlist page)
+func firstVideo(listID string) (string, error) {
+	resp, err := http.Get("https://www.youtube.com/playlist?" + url.Values{"list": {listID}}.Encode())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	page, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
 	}
-	if id := path.Base(u.Path); id != "" {
-		return id, nil
+	m := videoIDRegexp.FindSubmatch(page)
+	if m == nil {
+		return "", fmt.Errorf("no video found")
 	}
-	return "", fmt.Errorf("could not find id inside URL: %s", rawurl)
+	return string(m[1]), nil
 }
@@ -2,6 +2,7 @@ package youtube_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/oliverpool/go-chromecast/command/media/youtube"
 )
@@ -28,3 +29,35 @@ func TestURLParsing(t *testing.T) {
 		}
 	}
 }
+
+func TestVideoExtraction(t *testing.T) {
+	cc := []struct {
+		url      string
+		expected youtube.Video
+	}{
+		{"https://www.youtube.com/watch?v=b-GIBLX3nAk", youtube.Video{ID: "b-GIBLX3nAk"}},
+		{"https://youtu.be/b-GIBLX3nAk?t=90", youtube.Video{ID: "b-GIBLX3nAk", Start: 90 * time.Second}},
+		{"https://www.youtube.com/watch?v=b-GIBLX3nAk&t=1m30s", youtube.Video{ID: "b-GIBLX3nAk", Start: 90 * time.Second}},
+		{"https://www.youtube-nocookie.com/embed/b-GIBLX3nAk?start=10", youtube.Video{ID: "b-GIBLX3nAk", Start: 10 * time.Second}},
+		{"https://www.youtube.com/watch?v=b-GIBLX3nAk#t=5s", youtube.Video{ID: "b-GIBLX3nAk", Start: 5 * time.Second}},
+		{"https://www.youtube.com/watch?v=b-GIBLX3nAk&list=PLabc123", youtube.Video{ID: "b-GIBLX3nAk", ListID: "PLabc123"}},
+		{"https://www.youtube.com/playlist?list=PLabc123", youtube.Video{ListID: "PLabc123"}},
+	}
+
+	for _, c := range cc {
+		got, err := youtube.ExtractVideo(c.url)
+		if err != nil {
+			t.Errorf("got unexpected error for '%s': %v", c.url, err)
+		}
+		if got != c.expected {
+			t.Errorf("got %+v, expected %+v for '%s'", got, c.expected, c.url)
+		}
+	}
+
+	if _, err := youtube.ExtractVideo("https://www.youtube.com/watch?v=b-GIBLX3nAk&t=soon"); err == nil {
+		t.Error("an invalid timestamp should be rejected")
+	}
+	if _, err := youtube.ExtractID("https://www.youtube.com/playlist?list=PLabc123"); err == nil {
+		t.Error("a playlist has no video id")
+	}
+}
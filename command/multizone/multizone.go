@@ -0,0 +1,120 @@
+// Package multizone queries the members of a cast group (urn:x-cast:com.google.cast.multizone)
+// The requests must be sent to the group (its leader forwards them)
+package multizone
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command"
+)
+
+const Namespace = "urn:x-cast:com.google.cast.multizone"
+
+var env = chromecast.Envelope{
+	Source:      command.DefaultSource,
+	Destination: command.DefaultDestination,
+	Namespace:   Namespace,
+}
+
+// Member is a device belonging to the cast group
+type Member = chromecast.GroupMember
+
+// Status of the cast group
+type Status struct {
+	Devices        []Member `json:"devices"`
+	IsMultichannel bool     `json:"isMultichannel"`
+}
+
+type statusResponse struct {
+	Status *Status `json:"status"`
+}
+
+// Controller of the cast group
+type Controller struct {
+	Requester chromecast.Requester
+}
+
+// Status returns the status of the group (waiting at most command.DefaultTimeout)
+func (c Controller) Status() (st Status, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), command.DefaultTimeout)
+	defer cancel()
+	return c.StatusCtx(ctx)
+}
+
+// StatusCtx returns the status of the group (chromecast.ErrRequestTimeout if ctx is done before)
+func (c Controller) StatusCtx(ctx context.Context) (st Status, err error) {
+	payload, err := command.Request(ctx, c.Requester, env, command.Map{"type": "GET_STATUS"})
+	if err != nil {
+		return st, err
+	}
+	if payload == nil {
+		return st, fmt.Errorf("could not get multizone status: %w", chromecast.ErrEmptyPayload)
+	}
+	if err = command.ResponseError(payload); err != nil {
+		return st, err
+	}
+
+	err = json.Unmarshal(payload, &statusResponse{Status: &st})
+	if err != nil {
+		err = fmt.Errorf("failed to unmarshal into multizone status: %s", err)
+	}
+	return st, err
+}
+
+// Members returns the devices of the group (see chromecast.Device.GroupMembers)
+func (c Controller) Members() ([]Member, error) {
+	st, err := c.Status()
+	return st.Devices, err
+}
+
+// MemberVolume returns the volume of the member (by device ID)
+func (c Controller) MemberVolume(deviceID string) (*chromecast.Volume, error) {
+	members, err := c.Members()
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		if m.ID == deviceID {
+			return m.Volume, nil
+		}
+	}
+	return nil, fmt.Errorf("member %s not found in the group", deviceID)
+}
+
+// SetMemberVolume changes the volume (level and/or muted) of a member
+// and returns the updated member (waiting at most command.DefaultTimeout)
+func (c Controller) SetMemberVolume(deviceID string, vol chromecast.Volume) (Member, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), command.DefaultTimeout)
+	defer cancel()
+	return c.SetMemberVolumeCtx(ctx, deviceID, vol)
+}
+
+// SetMemberVolumeCtx changes the volume (level and/or muted) of a member
+// and returns the updated member (chromecast.ErrRequestTimeout if ctx is done before)
+func (c Controller) SetMemberVolumeCtx(ctx context.Context, deviceID string, vol chromecast.Volume) (m Member, err error) {
+	payload, err := command.Request(ctx, c.Requester, env, command.Map{
+		"type":     "SET_DEVICE_VOLUME",
+		"deviceId": deviceID,
+		"volume":   vol,
+	})
+	if err != nil {
+		return m, err
+	}
+	if payload == nil {
+		return m, fmt.Errorf("could not set member volume: %w", chromecast.ErrEmptyPayload)
+	}
+	if err = command.ResponseError(payload); err != nil {
+		return m, err
+	}
+
+	err = json.Unmarshal(payload, &struct {
+		Device *Member `json:"device"`
+	}{Device: &m})
+	if err != nil {
+		err = fmt.Errorf("failed to unmarshal into member: %s", err)
+	}
+	return m, err
+}
@@ -0,0 +1,87 @@
+package multizone_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/multizone"
+)
+
+type cannedRequester struct {
+	env      chromecast.Envelope
+	payload  chromecast.IdentifiablePayload
+	response string
+}
+
+func (c *cannedRequester) Request(env chromecast.Envelope, payload chromecast.IdentifiablePayload) (<-chan []byte, error) {
+	c.env = env
+	c.payload = payload
+	ch := make(chan []byte, 1)
+	ch <- []byte(c.response)
+	close(ch)
+	return ch, nil
+}
+
+func TestMembers(t *testing.T) {
+	requester := &cannedRequester{
+		response: `{"requestId":1,"status":{"devices":[{"capabilities":2052,"deviceId":"a1b2","name":"Kitchen speaker","volume":{"level":0.3,"muted":false}},{"capabilities":2052,"deviceId":"c3d4","name":"Bedroom speaker","volume":{"level":0.5,"muted":true}}],"isMultichannel":false},"type":"MULTIZONE_STATUS"}`,
+	}
+
+	members, err := multizone.Controller{Requester: requester}.Members()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requester.env.Namespace != multizone.Namespace {
+		t.Errorf("unexpected namespace: %s", requester.env.Namespace)
+	}
+	if len(members) != 2 {
+		t.Fatalf("2 members expected, got %d", len(members))
+	}
+	if members[0].ID != "a1b2" || members[0].Name != "Kitchen speaker" || *members[0].Volume.Level != 0.3 {
+		t.Errorf("unexpected first member: %+v", members[0])
+	}
+	if !*members[1].Volume.Muted {
+		t.Errorf("second member should be muted")
+	}
+}
+
+func TestGroupMembers(t *testing.T) {
+	requester := &cannedRequester{
+		response: `{"requestId":1,"status":{"devices":[{"capabilities":2052,"deviceId":"a1b2","name":"Kitchen speaker"}],"isMultichannel":false},"type":"MULTIZONE_STATUS"}`,
+	}
+	controller := multizone.Controller{Requester: requester}
+
+	group := chromecast.Device{Properties: map[string]string{"fn": "Home group", "md": "Google Cast Group"}}
+	members, err := group.GroupMembers(controller)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(members) != 1 || members[0].Name != "Kitchen speaker" {
+		t.Errorf("unexpected members: %+v", members)
+	}
+
+	speaker := chromecast.Device{Properties: map[string]string{"fn": "Kitchen speaker", "md": "Google Home"}}
+	if _, err := speaker.GroupMembers(controller); err == nil {
+		t.Error("an error was expected for a device which is not a group")
+	}
+}
+
+func TestSetMemberVolume(t *testing.T) {
+	requester := &cannedRequester{
+		response: `{"requestId":1,"device":{"capabilities":2052,"deviceId":"a1b2","name":"Kitchen speaker","volume":{"level":0.6,"muted":false}},"type":"DEVICE_UPDATED"}`,
+	}
+
+	level := 0.6
+	member, err := multizone.Controller{Requester: requester}.SetMemberVolume("a1b2", chromecast.Volume{Level: &level})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if member.ID != "a1b2" || *member.Volume.Level != 0.6 {
+		t.Errorf("unexpected member: %+v", member)
+	}
+	b, _ := json.Marshal(requester.payload)
+	if string(b) != `{"deviceId":"a1b2","type":"SET_DEVICE_VOLUME","volume":{"level":0.6}}` {
+		t.Errorf("unexpected request: %s", b)
+	}
+}
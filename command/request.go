@@ -0,0 +1,34 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// DefaultTimeout of the requests made without context
+const DefaultTimeout = 20 * time.Second
+
+// Request sends the payload and waits for the response
+// It returns chromecast.ErrRequestTimeout if ctx is done before
+func Request(ctx context.Context, requester chromecast.Requester, env chromecast.Envelope, payload chromecast.IdentifiablePayload) ([]byte, error) {
+	if r, ok := requester.(chromecast.ContextRequester); ok {
+		return r.RequestCtx(ctx, env, payload)
+	}
+	response, err := requester.Request(env, payload)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case pay := <-response:
+		return pay, nil
+	case <-ctx.Done():
+		return nil, chromecast.ErrRequestTimeout
+	}
+}
+
+// defaultContext returns a context for the requests made without context
+func defaultContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), DefaultTimeout)
+}
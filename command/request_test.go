@@ -0,0 +1,30 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/client"
+	"github.com/oliverpool/go-chromecast/command"
+)
+
+var _ chromecast.ContextRequester = &client.Client{}
+
+// mutedRequester never answers
+type mutedRequester struct{}
+
+func (mutedRequester) Request(env chromecast.Envelope, payload chromecast.IdentifiablePayload) (<-chan []byte, error) {
+	return make(chan []byte), nil
+}
+
+func TestRequestTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := command.Launcher{Requester: mutedRequester{}}.StatusCtx(ctx)
+	if err != chromecast.ErrRequestTimeout {
+		t.Errorf("ErrRequestTimeout expected, got %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package command
+
+import (
+	"encoding/json"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// ResponseError returns the error contained in the response payload (nil if the payload is not an error)
+// It is a chromecast.LaunchError, chromecast.LoadFailedError or chromecast.RequestError
+func ResponseError(payload []byte) error {
+	var response struct {
+		Type              string `json:"type"`
+		Reason            string `json:"reason"`
+		DetailedErrorCode int    `json:"detailedErrorCode"`
+	}
+	if err := json.Unmarshal(payload, &response); err != nil {
+		return nil
+	}
+	reason := response.Reason
+	switch response.Type {
+	case "LAUNCH_ERROR":
+		return chromecast.LaunchError{Reason: reason}
+	case "LOAD_FAILED":
+		if reason == "" {
+			reason = "the media could not be loaded"
+		}
+		return chromecast.LoadFailedError{Reason: reason, DetailedErrorCode: response.DetailedErrorCode}
+	case "LOAD_CANCELLED":
+		if reason == "" {
+			reason = "cancelled"
+		}
+		return chromecast.LoadFailedError{Reason: reason, Cancelled: true}
+	case "INVALID_REQUEST", "INVALID_PLAYER_STATE":
+		return chromecast.RequestError{Type: response.Type, Reason: reason}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package command_test
+
+import (
+	"errors"
+	"testing"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command"
+)
+
+func TestResponseError(t *testing.T) {
+	var launchErr chromecast.LaunchError
+	if err := command.ResponseError([]byte(`{"type":"LAUNCH_ERROR","reason":"NOT_FOUND","requestId":1}`)); !errors.As(err, &launchErr) || launchErr.Reason != "NOT_FOUND" {
+		t.Errorf("LaunchError expected, got %v", err)
+	}
+
+	var loadErr chromecast.LoadFailedError
+	if err := command.ResponseError([]byte(`{"type":"LOAD_FAILED","requestId":2}`)); !errors.As(err, &loadErr) {
+		t.Errorf("LoadFailedError expected, got %v", err)
+	}
+	if err := command.ResponseError([]byte(`{"type":"LOAD_FAILED","detailedErrorCode":104}`)); !errors.As(err, &loadErr) || loadErr.DetailedErrorCode != 104 {
+		t.Errorf("LoadFailedError with a detailed code expected, got %v", err)
+	}
+	if err := command.ResponseError([]byte(`{"type":"LOAD_CANCELLED"}`)); !errors.As(err, &loadErr) || !loadErr.Cancelled {
+		t.Errorf("cancelled LoadFailedError expected, got %v", err)
+	}
+
+	var requestErr chromecast.RequestError
+	if err := command.ResponseError([]byte(`{"type":"INVALID_REQUEST","reason":"INVALID_COMMAND"}`)); !errors.As(err, &requestErr) || requestErr.Type != "INVALID_REQUEST" {
+		t.Errorf("RequestError expected, got %v", err)
+	}
+
+	if err := command.ResponseError([]byte(`{"type":"RECEIVER_STATUS","status":{}}`)); err != nil {
+		t.Errorf("no error expected, got %v", err)
+	}
+}
@@ -47,6 +47,16 @@ func (a App) Load(url string, options ...media.Option) (<-chan []byte, error) {
 	return a.Client.Request(a.Envelope, payload)
 }
 
+func init() {
+	media.RegisterMatchingLoader("urlreceiver", media.FallbackPriority, URLLoader, CanHandle)
+}
+
+// CanHandle returns true for the absolute URLs
+func CanHandle(rawurl string) bool {
+	u, err := url.Parse(rawurl)
+	return err == nil && u.IsAbs()
+}
+
 func URLLoader(rawurl string, options ...media.Option) (func(client chromecast.Client, statuses ...chromecast.Status) (<-chan []byte, error), error) {
 	url, err := ExtractID(rawurl)
 	if err != nil {
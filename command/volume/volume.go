@@ -0,0 +1,153 @@
+// Package volume changes the volume of the chromecast by steps
+package volume
+
+import (
+	"fmt"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command"
+	"github.com/oliverpool/go-chromecast/command/multizone"
+)
+
+// DefaultStep is used when the device does not report its step interval
+const DefaultStep = 0.05
+
+// Option to customize the volume change
+type Option func(*options)
+
+type options struct {
+	scaleMembers bool
+	step         float64
+}
+
+// ScaleMembers adjusts proportionally the volume of each member, when connected to a cast group
+// (preserving their relative balance, like the Google Home app)
+func ScaleMembers(o *options) {
+	o.scaleMembers = true
+}
+
+// WithStep changes the volume by step, instead of the step interval of the device (ignored if not positive)
+func WithStep(step float64) Option {
+	return func(o *options) {
+		o.step = step
+	}
+}
+
+// Up increases the volume by the step interval of the device
+func Up(requester chromecast.Requester, opts ...Option) (chromecast.Status, error) {
+	return step(requester, 1, opts)
+}
+
+// Down decreases the volume by the step interval of the device
+func Down(requester chromecast.Requester, opts ...Option) (chromecast.Status, error) {
+	return step(requester, -1, opts)
+}
+
+func step(requester chromecast.Requester, direction float64, opts []Option) (chromecast.Status, error) {
+	st, err := command.Launcher{Requester: requester}.Status()
+	if err != nil {
+		return st, err
+	}
+	o := newOptions(opts)
+	vol := st.Volume
+	if vol != nil && o.step > 0 {
+		withStep := *vol
+		withStep.StepInterval = &o.step
+		vol = &withStep
+	}
+	level, err := Next(vol, direction)
+	if err != nil {
+		return st, err
+	}
+	return set(requester, st.Volume, level, o)
+}
+
+// Set changes the volume level
+func Set(requester chromecast.Requester, level float64, opts ...Option) (chromecast.Status, error) {
+	o := newOptions(opts)
+	var current *chromecast.Volume
+	if o.scaleMembers {
+		st, err := command.Launcher{Requester: requester}.Status()
+		if err != nil {
+			return st, err
+		}
+		current = st.Volume
+	}
+	return set(requester, current, level, o)
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func set(requester chromecast.Requester, current *chromecast.Volume, level float64, o options) (chromecast.Status, error) {
+	if o.scaleMembers {
+		if err := scaleMembers(requester, current, level); err != nil {
+			return chromecast.Status{}, err
+		}
+	}
+	return command.Launcher{Requester: requester}.SetVolume(level)
+}
+
+// scaleMembers sets the volume of each member of the group, proportionally to the change of the group level
+func scaleMembers(requester chromecast.Requester, current *chromecast.Volume, level float64) error {
+	controller := multizone.Controller{Requester: requester}
+	members, err := controller.Members()
+	if err != nil {
+		return fmt.Errorf("could not get the group members: %w", err)
+	}
+	for _, m := range members {
+		if m.Volume == nil || m.Volume.Level == nil {
+			continue
+		}
+		memberLevel := Scale(*m.Volume.Level, current, level)
+		if _, err = controller.SetMemberVolume(m.ID, chromecast.Volume{Level: &memberLevel}); err != nil {
+			return fmt.Errorf("could not set the volume of %s: %w", m.Name, err)
+		}
+	}
+	return nil
+}
+
+// Scale returns the level of a member when the group changes from current to level
+// (the level itself if the current group level is unknown or 0)
+func Scale(memberLevel float64, current *chromecast.Volume, level float64) float64 {
+	if current == nil || current.Level == nil || *current.Level == 0 {
+		return level
+	}
+	return clamp(memberLevel * level / *current.Level)
+}
+
+// Next returns the level one step up (direction > 0) or down (direction < 0),
+// between 0 and 1
+func Next(vol *chromecast.Volume, direction float64) (float64, error) {
+	if vol == nil || vol.Level == nil {
+		return 0, fmt.Errorf("the volume level is unknown")
+	}
+	if vol.ControlType != nil && *vol.ControlType == "fixed" {
+		return 0, fmt.Errorf("the volume of the device is fixed")
+	}
+	step := DefaultStep
+	if vol.StepInterval != nil && *vol.StepInterval > 0 {
+		step = *vol.StepInterval
+	}
+	level := *vol.Level
+	if direction > 0 {
+		level += step
+	} else if direction < 0 {
+		level -= step
+	}
+	return clamp(level), nil
+}
+
+func clamp(level float64) float64 {
+	if level > 1 {
+		return 1
+	} else if level < 0 {
+		return 0
+	}
+	return level
+}
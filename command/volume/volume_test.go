@@ -0,0 +1,90 @@
+package volume_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/volume"
+)
+
+func TestNext(t *testing.T) {
+	level, step := 0.5, 0.1
+	vol := &chromecast.Volume{Level: &level, StepInterval: &step}
+
+	if next, err := volume.Next(vol, 1); err != nil || next != 0.6 {
+		t.Errorf("0.6 expected, got %v (%v)", next, err)
+	}
+	if next, err := volume.Next(vol, -1); err != nil || next != 0.4 {
+		t.Errorf("0.4 expected, got %v (%v)", next, err)
+	}
+
+	level = 0.98
+	if next, _ := volume.Next(vol, 1); next != 1 {
+		t.Errorf("the level should be capped to 1, got %v", next)
+	}
+
+	vol.StepInterval = nil
+	level = 0.5
+	if next, _ := volume.Next(vol, -1); next != 0.5-volume.DefaultStep {
+		t.Errorf("the default step should be used, got %v", next)
+	}
+
+	fixed := "fixed"
+	vol.ControlType = &fixed
+	if _, err := volume.Next(vol, 1); err == nil {
+		t.Error("a fixed volume should not be changed")
+	}
+}
+
+func TestScale(t *testing.T) {
+	group := 0.5
+	current := &chromecast.Volume{Level: &group}
+
+	if level := volume.Scale(0.4, current, 0.25); level != 0.2 {
+		t.Errorf("the member level should be halved, got %v", level)
+	}
+	if level := volume.Scale(0.8, current, 1); level != 1 {
+		t.Errorf("the member level should be capped to 1, got %v", level)
+	}
+	if level := volume.Scale(0.8, nil, 0.3); level != 0.3 {
+		t.Errorf("the group level should be used when the current level is unknown, got %v", level)
+	}
+}
+
+// statusRequester answers every request with the same receiver status
+type statusRequester struct {
+	payloads []map[string]interface{}
+}
+
+func (r *statusRequester) Request(env chromecast.Envelope, payload chromecast.IdentifiablePayload) (<-chan []byte, error) {
+	b, _ := json.Marshal(payload)
+	var m map[string]interface{}
+	json.Unmarshal(b, &m)
+	r.payloads = append(r.payloads, m)
+
+	ch := make(chan []byte, 1)
+	ch <- []byte(`{"type":"RECEIVER_STATUS","status":{"volume":{"level":0.5,"muted":false,"stepInterval":0.05}}}`)
+	close(ch)
+	return ch, nil
+}
+
+func TestUpWithStep(t *testing.T) {
+	requester := &statusRequester{}
+	if _, err := volume.Up(requester, volume.WithStep(0.2)); err != nil {
+		t.Fatal(err)
+	}
+	last := requester.payloads[len(requester.payloads)-1]
+	level := last["volume"].(map[string]interface{})["level"]
+	if last["type"] != "SET_VOLUME" || level != 0.7 {
+		t.Errorf("the volume should be set to 0.7, got %v", last)
+	}
+
+	if _, err := volume.Down(requester); err != nil {
+		t.Fatal(err)
+	}
+	last = requester.payloads[len(requester.payloads)-1]
+	if level = last["volume"].(map[string]interface{})["level"]; level != 0.45 {
+		t.Errorf("the step of the device should be used by default, got %v", last)
+	}
+}
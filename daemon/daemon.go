@@ -0,0 +1,276 @@
+// Package daemon exposes a REST API to control the chromecasts of the network,
+// keeping a connection to each discovered device
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command"
+	"github.com/oliverpool/go-chromecast/command/media"
+	"github.com/oliverpool/go-chromecast/discovery"
+	"github.com/oliverpool/go-chromecast/log"
+)
+
+// DefaultLoadTimeout to wait for the reply to a load request
+const DefaultLoadTimeout = 10 * time.Second
+
+// Server keeps track of the devices found by the Scanner (see Run) and serves the REST API:
+//
+//	GET  /devices                 the devices
+//	GET  /devices/{id}            the device (the id is its UUID, with or without dashes)
+//	GET  /devices/{id}/status     the receiver and media statuses
+//	POST /devices/{id}/load       {"url": "...", "loader": "youtube", "title": "..."} (loader and title are optional)
+//	POST /devices/{id}/play       (as well as /pause and /stop)
+//	POST /devices/{id}/seek       {"position": 90.5} in seconds
+//	POST /devices/{id}/volume     {"level": 0.5} and/or {"muted": true}
+//	GET  /events                  the discovery events and the status changes, as server-sent events (see Event)
+type Server struct {
+	// Scanner of the devices (watched by Run)
+	Scanner discovery.Scanner
+	// Matchers of the devices to keep track of (all by default)
+	Matchers []discovery.DeviceMatcher
+	// Connect returns a client connected to the device (it is kept until the device is lost)
+	Connect func(ctx context.Context, device *chromecast.Device) (chromecast.Client, error)
+	Logger  chromecast.Logger
+	// LoadTimeout to wait for the reply to a load request (DefaultLoadTimeout if 0)
+	LoadTimeout time.Duration
+
+	mu          sync.Mutex
+	ctx         context.Context
+	devices     map[string]*device
+	subscribers map[chan Event]struct{}
+}
+
+// device known by the Server, with its connection (if any)
+type device struct {
+	*chromecast.Device
+
+	mu     sync.Mutex // held while connecting
+	client chromecast.Client
+}
+
+// Run watches the devices and connects to them, until ctx is done (the connections are then closed)
+func (s *Server) Run(ctx context.Context) error {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	events, err := discovery.Service{Scanner: s.Scanner}.Watch(ctx, s.Matchers...)
+	if err != nil {
+		return err
+	}
+	for e := range events {
+		s.log("device", e.Device.Name(), "id", e.Device.ID(), "event", e.Type)
+		switch e.Type {
+		case discovery.DeviceFound, discovery.DeviceUpdated:
+			d := s.track(e.Device)
+			s.publishDevice(e.Type, e.Device)
+			go func() {
+				if _, err := s.client(d); err != nil {
+					s.log("device", d.Name(), "step", "connect", "err", err)
+				}
+			}()
+		case discovery.DeviceLost:
+			s.forget(e.Device.ID())
+			s.publishDevice(e.Type, e.Device)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id := range s.devices {
+		s.closeDevice(id)
+	}
+	return nil
+}
+
+// track adds (or updates) the device.
+// If its address changed, the previous connection is closed.
+func (s *Server) track(dev *chromecast.Device) *device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.devices == nil {
+		s.devices = make(map[string]*device)
+	}
+	id := normalizeID(dev.ID())
+	if previous, ok := s.devices[id]; ok {
+		if previous.Addr() == dev.Addr() {
+			previous.mu.Lock()
+			previous.Device = dev
+			previous.mu.Unlock()
+			return previous
+		}
+		s.closeDevice(id)
+	}
+	d := &device{Device: dev}
+	s.devices[id] = d
+	return d
+}
+
+// forget removes the device and closes its connection
+func (s *Server) forget(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeDevice(normalizeID(id))
+}
+
+// closeDevice closes the connection of the device and removes it (s.mu must be held)
+func (s *Server) closeDevice(id string) {
+	d, ok := s.devices[id]
+	if !ok {
+		return
+	}
+	delete(s.devices, id)
+	go func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.client != nil {
+			d.client.Close()
+			d.client = nil
+		}
+	}()
+}
+
+// Devices returns the known devices, sorted by name
+func (s *Server) Devices() []*chromecast.Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	devices := make([]*chromecast.Device, 0, len(s.devices))
+	for _, d := range s.devices {
+		d.mu.Lock()
+		devices = append(devices, d.Device)
+		d.mu.Unlock()
+	}
+	sortDevices(devices)
+	return devices
+}
+
+// lookup returns the device with the given id (with or without dashes)
+func (s *Server) lookup(id string) (*device, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.devices[normalizeID(id)]
+	return d, ok
+}
+
+// client returns the connection to the device (connecting if needed)
+func (s *Server) client(d *device) (chromecast.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client != nil {
+		return d.client, nil
+	}
+	s.mu.Lock()
+	ctx := s.ctx
+	s.mu.Unlock()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// the connection outlives the request
+	client, err := s.Connect(ctx, d.Device)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to %s: %w", d.Name(), err)
+	}
+	d.client = client
+	go s.watch(d.Device, client)
+	return client, nil
+}
+
+// disconnect closes the connection to the device, if it is still the current one
+// (the next request will reconnect)
+func (s *Server) disconnect(d *device, client chromecast.Client) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client == client {
+		d.client.Close()
+		d.client = nil
+	}
+}
+
+// load loads the URL with the given loader (or the first one able to handle it) and returns the loader used
+func (s *Server) load(client chromecast.Client, rawurl, loaderName string, options ...media.Option) (string, error) {
+	status, err := command.Launcher{Requester: client}.Status()
+	if err != nil {
+		return "", fmt.Errorf("could not get the receiver status: %w", err)
+	}
+
+	candidates := media.Candidates(rawurl)
+	if loaderName != "" {
+		candidates = nil
+		for _, l := range media.Loaders() {
+			if l.Name == loaderName {
+				candidates = append(candidates, l)
+			}
+		}
+		if len(candidates) == 0 {
+			return "", errorf(http.StatusBadRequest, "unknown loader '%s'", loaderName)
+		}
+	}
+
+	timeout := s.LoadTimeout
+	if timeout <= 0 {
+		timeout = DefaultLoadTimeout
+	}
+	for _, l := range candidates {
+		loader, err := l.Loader(rawurl, options...)
+		var reply <-chan []byte
+		if err == nil {
+			reply, err = loader(client, status)
+		}
+		if err != nil {
+			if loaderName != "" {
+				return l.Name, err
+			}
+			s.log("loader", l.Name, "url", rawurl, "step", "loading", "err", err)
+			continue
+		}
+		err = awaitLoad(reply, timeout)
+		var loadErr chromecast.LoadFailedError
+		if err != nil && loaderName == "" && errors.As(err, &loadErr) {
+			// try the next loader
+			s.log("loader", l.Name, "url", rawurl, "step", "loaded", "err", err)
+			continue
+		}
+		return l.Name, err
+	}
+	return "", errorf(http.StatusUnprocessableEntity, "no loader could load %s", rawurl)
+}
+
+// awaitLoad waits for the reply to the load request (a missing reply is not an error)
+func awaitLoad(reply <-chan []byte, timeout time.Duration) error {
+	select {
+	case payload := <-reply:
+		return command.ResponseError(payload)
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
+func (s *Server) log(keyvals ...interface{}) {
+	logger := s.Logger
+	if logger == nil {
+		logger = log.NopLogger()
+	}
+	logger.Log(keyvals...)
+}
+
+func sortDevices(devices []*chromecast.Device) {
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].Name() != devices[j].Name() {
+			return devices[i].Name() < devices[j].Name()
+		}
+		return devices[i].ID() < devices[j].ID()
+	})
+}
+
+func normalizeID(id string) string {
+	return strings.ToLower(strings.Replace(id, "-", "", -1))
+}
@@ -0,0 +1,271 @@
+package daemon_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command/media"
+	"github.com/oliverpool/go-chromecast/daemon"
+	"github.com/oliverpool/go-chromecast/discovery"
+)
+
+// fakeClient answers like a chromecast playing a media
+type fakeClient struct {
+	mu          sync.Mutex
+	commands    []string
+	closed      bool
+	subscribers []chan<- []byte
+}
+
+func (c *fakeClient) Listen(env chromecast.Envelope, responseType string, ch chan<- []byte) {}
+
+func (c *fakeClient) Send(env chromecast.Envelope, payload interface{}) error {
+	return nil
+}
+
+func (c *fakeClient) Request(env chromecast.Envelope, payload chromecast.IdentifiablePayload) (<-chan []byte, error) {
+	b, _ := json.Marshal(payload)
+	var m map[string]interface{}
+	json.Unmarshal(b, &m)
+	typ, _ := m["type"].(string)
+
+	c.mu.Lock()
+	c.commands = append(c.commands, typ)
+	c.mu.Unlock()
+
+	response := `{"type":"RECEIVER_STATUS","status":{"applications":[{"appId":"CC1AD845","transportId":"t1","namespaces":[{"name":"` + media.Namespace + `"}]}],"volume":{"level":0.5,"muted":false}}}`
+	if env.Namespace == media.Namespace {
+		response = `{"type":"MEDIA_STATUS","status":[{"mediaSessionId":1,"playerState":"PLAYING","currentTime":60}]}`
+		switch typ {
+		case "PAUSE":
+			response = `{"type":"MEDIA_STATUS","status":[{"mediaSessionId":1,"playerState":"PAUSED","currentTime":60}]}`
+		case "SEEK":
+			response = `{"type":"INVALID_REQUEST","reason":"INVALID_COMMAND"}`
+		}
+	}
+	ch := make(chan []byte, 1)
+	ch <- []byte(response)
+	close(ch)
+	return ch, nil
+}
+
+func (c *fakeClient) Subscribe(namespace string, responseType string, ch chan<- []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subscribers = append(c.subscribers, ch)
+}
+
+func (c *fakeClient) Unsubscribe(namespace string, responseType string, ch chan<- []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, s := range c.subscribers {
+		if s == ch {
+			c.subscribers = append(c.subscribers[:i], c.subscribers[i+1:]...)
+			close(ch)
+			return
+		}
+	}
+}
+
+// broadcast sends the payload to the subscribers
+func (c *fakeClient) broadcast(payload string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, ch := range c.subscribers {
+		ch <- []byte(payload)
+	}
+}
+
+func (c *fakeClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for _, ch := range c.subscribers {
+		close(ch)
+	}
+	c.subscribers = nil
+	return nil
+}
+
+func (c *fakeClient) sent(typ string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, c := range c.commands {
+		if c == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func newServer(client chromecast.Client) *daemon.Server {
+	return &daemon.Server{
+		Scanner: discovery.Static(&chromecast.Device{
+			IP:         net.ParseIP("192.168.1.4"),
+			Port:       8009,
+			Properties: map[string]string{"fn": "Living Room", "id": "3e1cc7c0-f4f3-4d3b-8f3a-1234567890ab"},
+		}),
+		Connect: func(ctx context.Context, device *chromecast.Device) (chromecast.Client, error) {
+			return client, nil
+		},
+	}
+}
+
+func TestServer(t *testing.T) {
+	client := &fakeClient{}
+	srv := newServer(client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- srv.Run(ctx)
+	}()
+	for i := 0; len(srv.Devices()) == 0; i++ {
+		if i > 100 {
+			t.Fatal("the device was not found")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	do := func(method, path, body string, v interface{}) int {
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if v != nil {
+			if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+				t.Fatalf("%s %s: %v", method, path, err)
+			}
+		}
+		return resp.StatusCode
+	}
+
+	var devices []daemon.Device
+	if code := do("GET", "/devices", "", &devices); code != http.StatusOK {
+		t.Fatalf("GET /devices: unexpected status code %d", code)
+	}
+	if len(devices) != 1 || devices[0].Name != "Living Room" || devices[0].IP != "192.168.1.4" {
+		t.Fatalf("unexpected devices: %+v", devices)
+	}
+
+	var status daemon.Status
+	// the dashes of the UUID are optional
+	if code := do("GET", "/devices/3e1cc7c0f4f34d3b8f3a1234567890ab/status", "", &status); code != http.StatusOK {
+		t.Fatalf("GET status: unexpected status code %d", code)
+	}
+	if status.Receiver == nil || *status.Receiver.Volume.Level != 0.5 {
+		t.Errorf("unexpected receiver status: %+v", status.Receiver)
+	}
+	if len(status.Media) != 1 || status.Media[0].PlayerState != media.Playing {
+		t.Errorf("unexpected media status: %+v", status.Media)
+	}
+
+	id := "/devices/3e1cc7c0-f4f3-4d3b-8f3a-1234567890ab"
+	if code := do("POST", id+"/pause", "", nil); code != http.StatusOK || !client.sent("PAUSE") {
+		t.Errorf("POST pause: unexpected status code %d (%v)", code, client.commands)
+	}
+	if code := do("POST", id+"/volume", `{"level":0.2}`, nil); code != http.StatusOK || !client.sent("SET_VOLUME") {
+		t.Errorf("POST volume: unexpected status code %d (%v)", code, client.commands)
+	}
+
+	cc := []struct {
+		method, path, body string
+		code               int
+	}{
+		{"GET", "/unknown", "", http.StatusNotFound},
+		{"GET", "/devices/abcd/status", "", http.StatusNotFound},
+		{"GET", id + "/unknown", "", http.StatusNotFound},
+		{"GET", id + "/pause", "", http.StatusMethodNotAllowed},
+		{"POST", id + "/volume", `{"level":2}`, http.StatusBadRequest},
+		{"POST", id + "/volume", `{}`, http.StatusBadRequest},
+		{"POST", id + "/load", `{"url":""}`, http.StatusBadRequest},
+		{"POST", id + "/load", `{"url":"http://example.com/video.mp4","loader":"unknown"}`, http.StatusBadRequest},
+		{"POST", id + "/seek", `{"position":90}`, http.StatusConflict},
+	}
+	for _, c := range cc {
+		var body map[string]string
+		code := do(c.method, c.path, c.body, &body)
+		if code != c.code || body["error"] == "" {
+			t.Errorf("%s %s: got %d %v, expected %d with an error", c.method, c.path, code, body, c.code)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if !client.closed {
+		t.Error("the connection should have been closed")
+	}
+}
+
+func TestEvents(t *testing.T) {
+	client := &fakeClient{}
+	srv := newServer(client)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected Content-Type: %s", ct)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Run(ctx)
+
+	lines := bufio.NewScanner(resp.Body)
+	next := func() daemon.Event {
+		var e daemon.Event
+		for lines.Scan() {
+			line := lines.Text()
+			if strings.HasPrefix(line, "data: ") {
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e); err != nil {
+					t.Fatal(err)
+				}
+				return e
+			}
+		}
+		t.Fatalf("the stream ended: %v", lines.Err())
+		return e
+	}
+
+	if e := next(); e.Type != "found" || e.Device.Name != "Living Room" {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	if e := next(); e.Type != daemon.ReceiverEvent || e.Receiver == nil || *e.Receiver.Volume.Level != 0.5 {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	if e := next(); e.Type != daemon.MediaEvent || e.Media == nil || e.Media.PlayerState != media.Playing {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+
+	client.broadcast(`{"type":"RECEIVER_STATUS","status":{"volume":{"level":0.8,"muted":false}}}`)
+	if e := next(); e.Type != daemon.ReceiverEvent || e.Receiver == nil || *e.Receiver.Volume.Level != 0.8 {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+}
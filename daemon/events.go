@@ -0,0 +1,222 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command"
+	"github.com/oliverpool/go-chromecast/command/media"
+	"github.com/oliverpool/go-chromecast/discovery"
+)
+
+// eventsBuffer is the number of events kept for a slow subscriber (the next ones are dropped)
+const eventsBuffer = 16
+
+// keepAliveInterval between the comments sent on an idle event stream (to keep the proxies from closing it)
+const keepAliveInterval = 30 * time.Second
+
+// Event types (in addition to the discovery.EventType: found, updated and lost)
+const (
+	ReceiverEvent = "receiver"
+	MediaEvent    = "media"
+)
+
+// Event streamed by GET /events
+type Event struct {
+	// Type is "found", "updated" or "lost" for the discovery events,
+	// "receiver" or "media" for the status changes
+	Type     string             `json:"type"`
+	Time     time.Time          `json:"time"`
+	Device   Device             `json:"device"`
+	Receiver *chromecast.Status `json:"receiver,omitempty"`
+	Media    *media.Status      `json:"media,omitempty"`
+}
+
+// Subscribe returns a channel receiving the events, until stop is called
+// (the events are dropped while the channel is full)
+func (s *Server) Subscribe() (events <-chan Event, stop func()) {
+	ch := make(chan Event, eventsBuffer)
+	s.mu.Lock()
+	if s.subscribers == nil {
+		s.subscribers = make(map[chan Event]struct{})
+	}
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.subscribers[ch]; ok {
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// publish sends the event to the subscribers (without blocking)
+func (s *Server) publish(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscribers {
+		select {
+		case ch <- e:
+		default:
+			s.log("event", e.Type, "device", e.Device.Name, "err", "subscriber too slow, event dropped")
+		}
+	}
+}
+
+// publishDevice publishes a discovery event
+func (s *Server) publishDevice(t discovery.EventType, d *chromecast.Device) {
+	s.publish(Event{Type: t.String(), Device: s.newDevice(d)})
+}
+
+// watch publishes the status changes of the receiver and of its media app, until the connection is closed.
+// The media app is followed when another one is launched.
+func (s *Server) watch(d *chromecast.Device, client chromecast.Client) {
+	subscriber, ok := client.(chromecast.Subscriber)
+	if !ok {
+		s.log("device", d.Name(), "step", "watch", "err", "the client does not support the status updates")
+		return
+	}
+	receiverStatuses, stop := command.SubscribeStatus(subscriber)
+	defer stop()
+
+	var (
+		app           *media.App
+		events        <-chan media.Status
+		transportID   string
+		lastReceiver  string
+		lastMediaByID = make(map[int]string)
+	)
+	defer func() {
+		if app != nil {
+			app.Close()
+		}
+	}()
+
+	onReceiver := func(st chromecast.Status) {
+		if key := jsonKey(st); key != lastReceiver {
+			lastReceiver = key
+			s.publish(Event{Type: ReceiverEvent, Device: s.newDevice(d), Receiver: &st})
+		}
+
+		destination, err := st.FirstDestinationSupporting(media.Namespace)
+		if err != nil {
+			destination = ""
+		}
+		if destination == transportID {
+			return
+		}
+		// the media app changed
+		transportID = destination
+		if app != nil {
+			app.Close()
+			app, events = nil, nil
+		}
+		if destination == "" {
+			return
+		}
+		if app, err = media.ConnectFromStatus(client, st); err != nil {
+			s.log("device", d.Name(), "step", "watch", "transportId", destination, "err", err)
+			return
+		}
+		events = app.Events()
+		// the Events only receive the changes
+		go app.Status()
+	}
+
+	status, err := command.Launcher{Requester: client}.Status()
+	if err != nil {
+		s.log("device", d.Name(), "step", "watch", "err", err)
+	} else {
+		onReceiver(status)
+	}
+	for {
+		select {
+		case st, ok := <-receiverStatuses:
+			if !ok {
+				return
+			}
+			onReceiver(st)
+		case st, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			// the position changes continuously
+			withoutTime := st
+			withoutTime.CurrentTime = media.Seconds{}
+			if key := jsonKey(withoutTime); key != lastMediaByID[st.SessionID] {
+				lastMediaByID[st.SessionID] = key
+				s.publish(Event{Type: MediaEvent, Device: s.newDevice(d), Media: &st})
+			}
+		}
+	}
+}
+
+// serveEvents streams the events as server-sent events, starting with the known devices
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	if err := allowMethod(r, http.MethodGet); err != nil {
+		writeJSON(w, statusCode(err), map[string]string{"error": err.Error()})
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "streaming is not supported"})
+		return
+	}
+
+	events, stop := s.Subscribe()
+	defer stop()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	for _, d := range s.Devices() {
+		if err := writeEvent(w, Event{Type: discovery.DeviceFound.String(), Time: time.Now(), Device: s.newDevice(d)}); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case e := <-events:
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes the event in the server-sent events format
+func writeEvent(w http.ResponseWriter, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+	return err
+}
+
+// jsonKey returns the JSON representation of v, to detect the changes
+func jsonKey(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,330 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/command"
+	"github.com/oliverpool/go-chromecast/command/media"
+)
+
+// Device as returned by the REST API
+type Device struct {
+	Name      string `json:"name"`
+	UUID      string `json:"uuid"`
+	IP        string `json:"ip"`
+	Port      int    `json:"port"`
+	Model     string `json:"model"`
+	Status    string `json:"status"`
+	Connected bool   `json:"connected"`
+}
+
+// Status of a device as returned by the REST API
+type Status struct {
+	Receiver *chromecast.Status `json:"receiver"`
+	Media    []media.Status     `json:"media"`
+}
+
+// LoadRequest is the body of POST /devices/{id}/load
+type LoadRequest struct {
+	URL string `json:"url"`
+	// Loader to use (the first loader able to load the URL if empty)
+	Loader string `json:"loader,omitempty"`
+	// Title displayed by the chromecast
+	Title string `json:"title,omitempty"`
+}
+
+// SeekRequest is the body of POST /devices/{id}/seek
+type SeekRequest struct {
+	// Position in seconds
+	Position float64 `json:"position"`
+}
+
+// VolumeRequest is the body of POST /devices/{id}/volume (at least one field must be set)
+type VolumeRequest struct {
+	Level *float64 `json:"level,omitempty"`
+	Muted *bool    `json:"muted,omitempty"`
+}
+
+// httpError is written as {"error": "..."} with its status code
+type httpError struct {
+	code int
+	err  error
+}
+
+func (e httpError) Error() string {
+	return e.err.Error()
+}
+
+func (e httpError) Unwrap() error {
+	return e.err
+}
+
+func errorf(code int, format string, a ...interface{}) error {
+	return httpError{code: code, err: fmt.Errorf(format, a...)}
+}
+
+// ServeHTTP serves the REST API (see Server)
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.Trim(r.URL.Path, "/") == "events" {
+		s.serveEvents(w, r)
+		return
+	}
+	v, err := s.route(r)
+	if err != nil {
+		s.log("method", r.Method, "path", r.URL.Path, "err", err)
+		writeJSON(w, statusCode(err), map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// route returns the value to write for the request
+func (s *Server) route(r *http.Request) (interface{}, error) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "devices" || len(parts) > 3 {
+		return nil, errorf(http.StatusNotFound, "unknown path %s", r.URL.Path)
+	}
+	if len(parts) == 1 {
+		if err := allowMethod(r, http.MethodGet); err != nil {
+			return nil, err
+		}
+		devices := []Device{}
+		for _, d := range s.Devices() {
+			devices = append(devices, s.newDevice(d))
+		}
+		return devices, nil
+	}
+
+	d, ok := s.lookup(parts[1])
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "unknown device %s", parts[1])
+	}
+	if len(parts) == 2 {
+		if err := allowMethod(r, http.MethodGet); err != nil {
+			return nil, err
+		}
+		return s.newDevice(d.Device), nil
+	}
+
+	var handle func(ctx context.Context, client chromecast.Client, r *http.Request) error
+	method := http.MethodPost
+	switch action := parts[2]; action {
+	case "status":
+		method = http.MethodGet
+	case "load":
+		handle = s.handleLoad
+	case "play", "pause", "stop":
+		handle = handleSession(action)
+	case "seek":
+		handle = handleSeek
+	case "volume":
+		handle = handleVolume
+	default:
+		return nil, errorf(http.StatusNotFound, "unknown action %s", action)
+	}
+	if err := allowMethod(r, method); err != nil {
+		return nil, err
+	}
+
+	client, err := s.client(d)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), command.DefaultTimeout)
+	defer cancel()
+	var status Status
+	if handle != nil {
+		err = handle(ctx, client, r)
+	}
+	if err == nil {
+		status, err = deviceStatus(ctx, client)
+	}
+	if errors.Is(err, chromecast.ErrConnectionClosed) {
+		s.disconnect(d, client)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (s *Server) handleLoad(ctx context.Context, client chromecast.Client, r *http.Request) error {
+	var req LoadRequest
+	if err := decodeBody(r, &req); err != nil {
+		return err
+	}
+	if req.URL == "" {
+		return errorf(http.StatusBadRequest, "the url is missing")
+	}
+	var options []media.Option
+	if req.Title != "" {
+		options = append(options, media.Metadata(media.GenericMediaMetadata{Title: req.Title}))
+	}
+	loader, err := s.load(client, req.URL, req.Loader, options...)
+	if err != nil {
+		return err
+	}
+	s.log("url", req.URL, "loader", loader, "step", "loaded")
+	return nil
+}
+
+// handleSession sends the command (play, pause or stop) to the current media session
+func handleSession(action string) func(ctx context.Context, client chromecast.Client, r *http.Request) error {
+	return func(ctx context.Context, client chromecast.Client, r *http.Request) error {
+		session, err := attach(ctx, client)
+		if err != nil {
+			return err
+		}
+		switch action {
+		case "play":
+			return session.PlayCtx(ctx)
+		case "pause":
+			return session.PauseCtx(ctx)
+		default:
+			return session.StopCtx(ctx)
+		}
+	}
+}
+
+func handleSeek(ctx context.Context, client chromecast.Client, r *http.Request) error {
+	var req SeekRequest
+	if err := decodeBody(r, &req); err != nil {
+		return err
+	}
+	if req.Position < 0 {
+		return errorf(http.StatusBadRequest, "the position must be positive")
+	}
+	session, err := attach(ctx, client)
+	if err != nil {
+		return err
+	}
+	return session.SeekCtx(ctx, time.Duration(req.Position*float64(time.Second)))
+}
+
+func handleVolume(ctx context.Context, client chromecast.Client, r *http.Request) error {
+	var req VolumeRequest
+	if err := decodeBody(r, &req); err != nil {
+		return err
+	}
+	if req.Level == nil && req.Muted == nil {
+		return errorf(http.StatusBadRequest, "the level or muted field is required")
+	}
+	launcher := command.Launcher{Requester: client}
+	if req.Level != nil {
+		if *req.Level < 0 || *req.Level > 1 {
+			return errorf(http.StatusBadRequest, "the level must be between 0 and 1")
+		}
+		if _, err := launcher.SetVolume(*req.Level); err != nil {
+			return fmt.Errorf("could not set the volume: %w", err)
+		}
+	}
+	if req.Muted != nil {
+		if _, err := launcher.Mute(*req.Muted); err != nil {
+			return fmt.Errorf("could not mute: %w", err)
+		}
+	}
+	return nil
+}
+
+// attach returns the current media session
+func attach(ctx context.Context, client chromecast.Client) (*media.Session, error) {
+	status, err := command.Launcher{Requester: client}.Status()
+	if err != nil {
+		return nil, fmt.Errorf("could not get the receiver status: %w", err)
+	}
+	_, session, err := media.Attach(ctx, client, status)
+	return session, err
+}
+
+// deviceStatus returns the status of the receiver and of its media app (if any)
+func deviceStatus(ctx context.Context, client chromecast.Client) (Status, error) {
+	st := Status{
+		Media: []media.Status{},
+	}
+	receiver, err := command.Launcher{Requester: client}.Status()
+	if err != nil {
+		return st, fmt.Errorf("could not get the receiver status: %w", err)
+	}
+	st.Receiver = &receiver
+
+	app, err := media.ConnectFromStatus(client, receiver)
+	if errors.Is(err, chromecast.ErrAppNotFound) {
+		return st, nil
+	}
+	if err != nil {
+		return st, fmt.Errorf("could not connect to the media app: %w", err)
+	}
+	statuses, err := app.StatusCtx(ctx)
+	if err != nil {
+		return st, fmt.Errorf("could not get the media status: %w", err)
+	}
+	st.Media = append(st.Media, statuses...)
+	return st, nil
+}
+
+func (s *Server) newDevice(d *chromecast.Device) Device {
+	ip := d.IP.String()
+	if d.Zone != "" {
+		ip += "%" + d.Zone
+	}
+	connected := false
+	if known, ok := s.lookup(d.ID()); ok {
+		known.mu.Lock()
+		connected = known.client != nil
+		known.mu.Unlock()
+	}
+	return Device{
+		Name:      d.Name(),
+		UUID:      d.ID(),
+		IP:        ip,
+		Port:      d.Port,
+		Model:     d.Type(),
+		Status:    d.Status(),
+		Connected: connected,
+	}
+}
+
+func allowMethod(r *http.Request, method string) error {
+	if r.Method != method {
+		return errorf(http.StatusMethodNotAllowed, "method %s not allowed (%s expected)", r.Method, method)
+	}
+	return nil
+}
+
+func decodeBody(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return errorf(http.StatusBadRequest, "invalid body: %v", err)
+	}
+	return nil
+}
+
+// statusCode returns the HTTP status code corresponding to the error
+func statusCode(err error) int {
+	var httpErr httpError
+	if errors.As(err, &httpErr) {
+		return httpErr.code
+	}
+	var reqErr chromecast.RequestError
+	var loadErr chromecast.LoadFailedError
+	switch {
+	case errors.Is(err, chromecast.ErrNoSession), errors.Is(err, chromecast.ErrAppNotFound),
+		errors.As(err, &reqErr), errors.As(err, &loadErr):
+		return http.StatusConflict
+	case errors.Is(err, chromecast.ErrRequestTimeout):
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusBadGateway
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -3,16 +3,29 @@ package chromecast
 import (
 	"fmt"
 	"net"
+	"strconv"
+	"time"
 )
 
 type Device struct {
-	IP         net.IP
+	IP net.IP
+	// Zone of a link-local IPv6 address (like "eth0")
+	Zone       string
 	Port       int
 	Properties map[string]string
+	// TTL of the announcement of the device (0 if unknown)
+	TTL time.Duration
+	// Info is only available if the device has been enriched (see the setupapi package)
+	Info *DeviceInfo `json:",omitempty"`
 }
 
+// Addr returns the address to dial (like "192.168.1.2:8009" or "[fe80::1%eth0]:8009")
 func (d Device) Addr() string {
-	return fmt.Sprintf("%s:%d", d.IP, d.Port)
+	host := d.IP.String()
+	if d.Zone != "" {
+		host += "%" + d.Zone
+	}
+	return net.JoinHostPort(host, strconv.Itoa(d.Port))
 }
 
 func (d Device) Name() string {
@@ -30,3 +43,96 @@ func (d Device) Type() string {
 func (d Device) Status() string {
 	return d.Properties["rs"]
 }
+
+// capability bits of the "ca" property
+const (
+	capabilityVideoOut = 1 << iota
+	capabilityVideoIn
+	capabilityAudioOut
+	capabilityAudioIn
+	capabilityDevMode
+	capabilityMultizoneGroup
+)
+
+// DeviceCapabilities are decoded from the "ca" property of the device
+type DeviceCapabilities struct {
+	VideoOut       bool
+	VideoIn        bool
+	AudioOut       bool
+	AudioIn        bool
+	DevMode        bool
+	MultizoneGroup bool
+}
+
+// Capabilities returns the decoded "ca" property (all false if missing)
+func (d Device) Capabilities() DeviceCapabilities {
+	ca, _ := strconv.Atoi(d.Properties["ca"])
+	return DeviceCapabilities{
+		VideoOut:       ca&capabilityVideoOut != 0,
+		VideoIn:        ca&capabilityVideoIn != 0,
+		AudioOut:       ca&capabilityAudioOut != 0,
+		AudioIn:        ca&capabilityAudioIn != 0,
+		DevMode:        ca&capabilityDevMode != 0,
+		MultizoneGroup: ca&capabilityMultizoneGroup != 0,
+	}
+}
+
+// IsGroup returns true if the device is a cast group (multizone)
+func (d Device) IsGroup() bool {
+	return d.Type() == "Google Cast Group" || d.Capabilities().MultizoneGroup
+}
+
+// GroupMember is a device belonging to a cast group
+type GroupMember struct {
+	ID           string  `json:"deviceId"`
+	Name         string  `json:"name"`
+	Capabilities int     `json:"capabilities"`
+	Volume       *Volume `json:"volume,omitempty"`
+}
+
+// GroupMemberLister lists the members of the cast group it is connected to (like multizone.Controller)
+type GroupMemberLister interface {
+	Members() ([]GroupMember, error)
+}
+
+// GroupMembers returns the members of the cast group, listed through its connection
+// (the mDNS announcement of a group does not contain its members)
+func (d Device) GroupMembers(lister GroupMemberLister) ([]GroupMember, error) {
+	if !d.IsGroup() {
+		return nil, fmt.Errorf("%s is not a cast group", d.Name())
+	}
+	return lister.Members()
+}
+
+// IsAudio returns true if the device only outputs audio (like a Chromecast Audio)
+func (d Device) IsAudio() bool {
+	if d.IsGroup() {
+		return false
+	}
+	if d.Type() == "Chromecast Audio" {
+		return true
+	}
+	ca := d.Capabilities()
+	return ca.AudioOut && !ca.VideoOut
+}
+
+// IsVideo returns true if the device can display video
+func (d Device) IsVideo() bool {
+	return !d.IsGroup() && d.Capabilities().VideoOut
+}
+
+// DeviceInfo contains the metadata exposed by the setup API of the device (eureka_info)
+type DeviceInfo struct {
+	Name              string  `json:"name"`
+	BuildVersion      string  `json:"build_version"`
+	CastBuildRevision string  `json:"cast_build_revision"`
+	ReleaseTrack      string  `json:"release_track"`
+	Uptime            float64 `json:"uptime"` // in seconds
+	SSID              string  `json:"ssid"`
+	Locale            string  `json:"locale"`
+	MacAddress        string  `json:"mac_address"`
+	IPAddress         string  `json:"ip_address"`
+	SSDPUDN           string  `json:"ssdp_udn"`
+	HasUpdate         bool    `json:"has_update"`
+	EthernetConnected bool    `json:"ethernet_connected"`
+}
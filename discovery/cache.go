@@ -0,0 +1,166 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// CachedDevice is a device remembered by the Cache
+type CachedDevice struct {
+	*chromecast.Device
+	Seen time.Time
+}
+
+// CacheStore persists the devices of a Cache
+type CacheStore interface {
+	Load() ([]CachedDevice, error)
+	Save([]CachedDevice) error
+}
+
+// Cache wraps a Scanner and remembers the devices it found in a Store.
+// When scanning, the cached devices are probed directly (in parallel with the wrapped Scanner),
+// so that a known device can be found without waiting for a scan round.
+type Cache struct {
+	Scanner Scanner
+	Store   CacheStore
+	// MaxAge of the cached devices (no expiration if 0)
+	MaxAge time.Duration
+	// ProbeTimeout to connect to a cached device (1s if 0)
+	ProbeTimeout time.Duration
+	Logger       chromecast.Logger
+}
+
+// Scan pushes the reachable cached devices and the devices found by the wrapped Scanner onto the results channel
+func (c Cache) Scan(ctx context.Context, results chan<- *chromecast.Device) error {
+	scanned := make(chan *chromecast.Device, 5)
+	if err := c.Scanner.Scan(ctx, scanned); err != nil {
+		return err
+	}
+
+	cached, err := c.Store.Load()
+	if err != nil {
+		c.log("step", "load", "err", err)
+	}
+	known := make(map[string]CachedDevice, len(cached))
+	for _, cd := range cached {
+		if cd.Device == nil || (c.MaxAge > 0 && time.Since(cd.Seen) > c.MaxAge) {
+			continue
+		}
+		known[uniqKey(cd.Device)] = cd
+	}
+
+	var wg sync.WaitGroup
+	for _, cd := range known {
+		wg.Add(1)
+		go func(device *chromecast.Device) {
+			defer wg.Done()
+			if !c.probe(ctx, device) {
+				return
+			}
+			select {
+			case results <- device:
+			case <-ctx.Done():
+			}
+		}(cd.Device)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for device := range scanned {
+			if device == nil {
+				continue
+			}
+			previous, ok := known[uniqKey(device)]
+			known[uniqKey(device)] = CachedDevice{Device: device, Seen: time.Now()}
+			if !ok || !sameDevice(previous.Device, device) {
+				c.save(known)
+			}
+			select {
+			case results <- device:
+			case <-ctx.Done():
+			}
+		}
+		c.save(known)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return nil
+}
+
+// probe checks if a cast connection can be opened to the device
+func (c Cache) probe(ctx context.Context, device *chromecast.Device) bool {
+	timeout := c.ProbeTimeout
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+	conn, err := (&net.Dialer{Timeout: timeout}).DialContext(ctx, "tcp", device.Addr())
+	if err != nil {
+		c.log("step", "probe", "addr", device.Addr(), "err", err)
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+func (c Cache) save(known map[string]CachedDevice) {
+	devices := make([]CachedDevice, 0, len(known))
+	for _, cd := range known {
+		devices = append(devices, cd)
+	}
+	if err := c.Store.Save(devices); err != nil {
+		c.log("step", "save", "err", err)
+	}
+}
+
+func (c Cache) log(keyvals ...interface{}) {
+	if c.Logger == nil {
+		return
+	}
+	vals := make([]interface{}, 0, len(keyvals)+2)
+	vals = append(vals, "package", "discovery")
+	vals = append(vals, keyvals...)
+	c.Logger.Log(vals...)
+}
+
+// FileStore stores the cached devices as JSON inside a file
+type FileStore struct {
+	Path string
+}
+
+// Load reads the cached devices (a missing file is not an error)
+func (f FileStore) Load() ([]CachedDevice, error) {
+	b, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var devices []CachedDevice
+	err = json.Unmarshal(b, &devices)
+	return devices, err
+}
+
+// Save writes the cached devices (creating the parent directories if needed)
+func (f FileStore) Save(devices []CachedDevice) error {
+	b, err := json.Marshal(devices)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(f.Path, b, 0644)
+}
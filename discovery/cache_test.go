@@ -0,0 +1,125 @@
+package discovery_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/discovery"
+)
+
+type memoryStore struct {
+	devices []discovery.CachedDevice
+	saved   chan []discovery.CachedDevice
+}
+
+func (m *memoryStore) Load() ([]discovery.CachedDevice, error) {
+	return m.devices, nil
+}
+
+func (m *memoryStore) Save(devices []discovery.CachedDevice) error {
+	m.saved <- devices
+	return nil
+}
+
+func TestCacheProbesKnownDevice(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	addr := listener.Addr().(*net.TCPAddr)
+
+	store := &memoryStore{
+		devices: []discovery.CachedDevice{{
+			Device: discovery.NewDevice(addr.IP, addr.Port, []string{"id=cached", "fn=casti"}),
+			Seen:   time.Now(),
+		}},
+		saved: make(chan []discovery.CachedDevice, 10),
+	}
+	scan := MockedScanner{
+		ScanFunc: func(ctx context.Context, results chan<- *chromecast.Device) error {
+			go func() {
+				<-ctx.Done()
+				close(results)
+			}()
+			return nil
+		},
+	}
+
+	service := discovery.Service{Scanner: discovery.Cache{Scanner: &scan, Store: store}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	first, err := service.First(ctx, discovery.WithName("casti"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if first.ID() != "cached" {
+		t.Errorf("the cached device should have been found, got '%s'", first.ID())
+	}
+}
+
+func TestCacheSavesScannedDevice(t *testing.T) {
+	store := &memoryStore{
+		saved: make(chan []discovery.CachedDevice, 10),
+	}
+	scan := MockedScanner{
+		ScanFunc: func(ctx context.Context, results chan<- *chromecast.Device) error {
+			go func() {
+				results <- discovery.NewDevice(net.IPv4(127, 0, 0, 1), 8009, []string{"id=scanned"})
+				<-ctx.Done()
+				close(results)
+			}()
+			return nil
+		},
+	}
+
+	service := discovery.Service{Scanner: discovery.Cache{Scanner: &scan, Store: store}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := service.First(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	saved := <-store.saved
+	if len(saved) != 1 || saved[0].ID() != "scanned" {
+		t.Errorf("the scanned device should have been saved, got %v", saved)
+	}
+}
+
+func TestCacheSkipsNilDevice(t *testing.T) {
+	store := &memoryStore{
+		saved: make(chan []discovery.CachedDevice, 10),
+	}
+	scan := MockedScanner{
+		ScanFunc: func(ctx context.Context, results chan<- *chromecast.Device) error {
+			go func() {
+				results <- nil
+				results <- discovery.NewDevice(net.IPv4(127, 0, 0, 1), 8009, []string{"id=scanned"})
+				close(results)
+			}()
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	results := make(chan *chromecast.Device, 5)
+	if err := (discovery.Cache{Scanner: &scan, Store: store}).Scan(ctx, results); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var devices []*chromecast.Device
+	for d := range results {
+		devices = append(devices, d)
+	}
+	if len(devices) != 1 || devices[0] == nil || devices[0].ID() != "scanned" {
+		t.Errorf("only the scanned device should have been forwarded, got %v", devices)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 	"sort"
 	"strings"
+	"time"
 
 	chromecast "github.com/oliverpool/go-chromecast"
 )
@@ -43,6 +44,8 @@ type Scanner interface {
 // Service allows to discover chromecast via the given scanner
 type Service struct {
 	Scanner Scanner
+	// TTL after which a device which has not been seen anymore is considered lost by Watch (DefaultTTL if 0)
+	TTL time.Duration
 }
 
 // First returns the first chromecast that is discovered by the scanner (matching all matchers - if any)
@@ -61,15 +64,24 @@ func (s Service) First(ctx context.Context, matchers ...DeviceMatcher) (*chromec
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
-		case device := <-result:
-			if match(device) {
+		case device, ok := <-result:
+			if !ok {
+				return nil, fmt.Errorf("scanner stopped before finding a device")
+			}
+			if device != nil && match(device) {
 				return device, nil
 			}
 		}
 	}
 }
 
+// ByID returns the first chromecast with the given UUID (matching all matchers - if any)
+func (s Service) ByID(ctx context.Context, uuid string, matchers ...DeviceMatcher) (*chromecast.Device, error) {
+	return s.First(ctx, append(matchers, WithID(uuid))...)
+}
+
 // Uniq scans until cancellation of the context and returns a map of chromecast devices by ID
+// (by address for the devices without ID)
 func (s Service) Uniq(ctx context.Context, matchers ...DeviceMatcher) (map[string]*chromecast.Device, error) {
 	scanned := make(chan *chromecast.Device, 5)
 
@@ -83,9 +95,12 @@ func (s Service) Uniq(ctx context.Context, matchers ...DeviceMatcher) (map[strin
 		select {
 		case <-ctx.Done():
 			return found, nil
-		case device := <-scanned:
-			if match(device) {
-				found[device.ID()] = device
+		case device, ok := <-scanned:
+			if !ok {
+				return found, nil
+			}
+			if device != nil && match(device) {
+				found[uniqKey(device)] = device
 			}
 		}
 	}
@@ -105,3 +120,11 @@ func (s Service) Sorted(ctx context.Context, matchers ...DeviceMatcher) ([]*chro
 	sort.Slice(result, func(i, j int) bool { return result[i].Name() < result[j].Name() })
 	return result, nil
 }
+
+// All scans until the deadline of the context and returns a deduplicated list of chromecast devices, sorted by name
+func (s Service) All(ctx context.Context, matchers ...DeviceMatcher) ([]*chromecast.Device, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		return nil, fmt.Errorf("the context must have a deadline")
+	}
+	return s.Sorted(ctx, matchers...)
+}
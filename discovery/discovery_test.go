@@ -157,3 +157,62 @@ func TestNamedCancelled(t *testing.T) {
 	}
 	<-done
 }
+
+func TestAll(t *testing.T) {
+	scan := MockedScanner{
+		ScanFunc: func(ctx context.Context, results chan<- *chromecast.Device) error {
+			go func() {
+				defer close(results)
+				for _, name := range []string{"kitchen", "bedroom", "kitchen", "attic"} {
+					results <- &chromecast.Device{Properties: map[string]string{"id": name, "fn": name}}
+				}
+				<-ctx.Done()
+			}()
+			return nil
+		},
+	}
+
+	service := discovery.Service{Scanner: &scan}
+
+	if _, err := service.All(context.Background()); err == nil {
+		t.Errorf("an error was expected without deadline")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	all, err := service.All(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var names []string
+	for _, d := range all {
+		names = append(names, d.Name())
+	}
+	if len(names) != 3 || names[0] != "attic" || names[1] != "bedroom" || names[2] != "kitchen" {
+		t.Errorf("unexpected devices: %v", names)
+	}
+}
+
+func TestStatic(t *testing.T) {
+	service := discovery.Service{Scanner: discovery.Static(
+		&chromecast.Device{Properties: map[string]string{"fn": "kitchen"}},
+		&chromecast.Device{Properties: map[string]string{"fn": "bedroom"}},
+	)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	d, err := service.First(ctx, discovery.WithName("bedroom"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if d.Name() != "bedroom" {
+		t.Errorf("unexpected device %s", d.Name())
+	}
+
+	_, err = service.First(ctx, discovery.WithName("attic"))
+	if err == nil || ctx.Err() != nil {
+		t.Errorf("an error was expected as soon as the static devices were exhausted, got %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package discovery
+
+import "fmt"
+
+// ScanError is reported by the scanners when a step of the scan fails
+type ScanError struct {
+	Scanner string // like "zeroconf" or "ssdp"
+	Step    string // like "browse" or "search"
+	Err     error
+}
+
+func (e ScanError) Error() string {
+	return fmt.Sprintf("%s scanner could not %s: %v", e.Scanner, e.Step, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e ScanError) Unwrap() error {
+	return e.Err
+}
+
+// ReportError sends the error on the errors channel, without blocking (the error is dropped if the channel is nil or full)
+func ReportError(errors chan<- error, err error) {
+	if errors == nil {
+		return
+	}
+	select {
+	case errors <- err:
+	default:
+	}
+}
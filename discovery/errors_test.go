@@ -0,0 +1,27 @@
+package discovery_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/oliverpool/go-chromecast/discovery"
+)
+
+func TestReportError(t *testing.T) {
+	blocked := errors.New("permission denied")
+	errs := make(chan error, 1)
+
+	discovery.ReportError(errs, discovery.ScanError{Scanner: "ssdp", Step: "search", Err: blocked})
+	// must not block when full or nil
+	discovery.ReportError(errs, blocked)
+	discovery.ReportError(nil, blocked)
+
+	err := <-errs
+	if !errors.Is(err, blocked) {
+		t.Errorf("the reported error should wrap the original error, got %v", err)
+	}
+	var scanErr discovery.ScanError
+	if !errors.As(err, &scanErr) || scanErr.Scanner != "ssdp" {
+		t.Errorf("a ScanError should have been reported, got %v", err)
+	}
+}
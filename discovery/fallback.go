@@ -0,0 +1,83 @@
+package discovery
+
+import (
+	"context"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// Fallback returns a Scanner which starts the fallback scanner only if the primary scanner
+// found nothing after the given delay (or could not be started).
+func Fallback(primary, fallback Scanner, after time.Duration) Scanner {
+	return fallbackScanner{
+		primary:  primary,
+		fallback: fallback,
+		after:    after,
+	}
+}
+
+type fallbackScanner struct {
+	primary  Scanner
+	fallback Scanner
+	after    time.Duration
+}
+
+func (f fallbackScanner) Scan(ctx context.Context, results chan<- *chromecast.Device) error {
+	scanned := make(chan *chromecast.Device, 5)
+	if err := f.primary.Scan(ctx, scanned); err != nil {
+		return f.fallback.Scan(ctx, results)
+	}
+
+	go func() {
+		defer close(results)
+
+		timer := time.NewTimer(f.after)
+		defer timer.Stop()
+		timeout := timer.C
+		done := ctx.Done()
+
+		var fallback chan *chromecast.Device
+		found := false
+		for scanned != nil || fallback != nil || timeout != nil {
+			var device *chromecast.Device
+			var ok bool
+			select {
+			case device, ok = <-scanned:
+				if !ok {
+					scanned = nil
+					if found {
+						timeout = nil
+					}
+					continue
+				}
+				found = true
+			case device, ok = <-fallback:
+				if !ok {
+					fallback = nil
+					continue
+				}
+			case <-timeout:
+				timeout = nil
+				if found {
+					continue
+				}
+				ch := make(chan *chromecast.Device, 5)
+				if err := f.fallback.Scan(ctx, ch); err == nil {
+					fallback = ch
+				}
+				continue
+			case <-done:
+				// the scanners will close their channels
+				done = nil
+				timeout = nil
+				continue
+			}
+			select {
+			case results <- device:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,69 @@
+package discovery_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/discovery"
+)
+
+func silentScanner() *MockedScanner {
+	return &MockedScanner{
+		ScanFunc: func(ctx context.Context, results chan<- *chromecast.Device) error {
+			go func() {
+				<-ctx.Done()
+				close(results)
+			}()
+			return nil
+		},
+	}
+}
+
+func TestFallbackUsed(t *testing.T) {
+	fallback := sendingScanner("probed")
+	service := discovery.Service{Scanner: discovery.Fallback(silentScanner(), fallback, 10*time.Millisecond)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	d, err := service.First(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if d.ID() != "probed" {
+		t.Errorf("the fallback device should have been found, got %s", d.ID())
+	}
+}
+
+func TestFallbackNotUsed(t *testing.T) {
+	fallback := sendingScanner("probed")
+	primary := &MockedScanner{
+		ScanFunc: func(ctx context.Context, results chan<- *chromecast.Device) error {
+			go func() {
+				results <- &chromecast.Device{Properties: map[string]string{"id": "scanned"}}
+				<-ctx.Done()
+				close(results)
+			}()
+			return nil
+		},
+	}
+	scanner := discovery.Fallback(primary, fallback, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	results := make(chan *chromecast.Device, 5)
+	if err := scanner.Scan(ctx, results); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for d := range results {
+		if d.ID() != "scanned" {
+			t.Errorf("unexpected device %s", d.ID())
+		}
+	}
+	if fallback.ScanFuncCalled != 0 {
+		t.Errorf("the fallback should not have been started")
+	}
+}
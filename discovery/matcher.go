@@ -1,6 +1,11 @@
 package discovery
 
-import chromecast "github.com/oliverpool/go-chromecast"
+import (
+	"net"
+	"strings"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
 
 // DeviceMatcher allows to specicy which device should be accepted
 type DeviceMatcher func(*chromecast.Device) bool
@@ -12,13 +17,26 @@ func WithName(name string) DeviceMatcher {
 	}
 }
 
-// WithID matches a device by its id
+// WithFuzzyName matches a device whose name contains the given name (case-insensitive)
+func WithFuzzyName(name string) DeviceMatcher {
+	name = strings.ToLower(name)
+	return func(device *chromecast.Device) bool {
+		return device != nil && strings.Contains(strings.ToLower(device.Name()), name)
+	}
+}
+
+// WithID matches a device by its id (the UUID can be given with or without dashes)
 func WithID(id string) DeviceMatcher {
+	id = normalizeID(id)
 	return func(device *chromecast.Device) bool {
-		return device != nil && device.ID() == id
+		return device != nil && normalizeID(device.ID()) == id
 	}
 }
 
+func normalizeID(id string) string {
+	return strings.ToLower(strings.Replace(id, "-", "", -1))
+}
+
 // WithType matches a device by its type
 func WithType(t string) DeviceMatcher {
 	return func(device *chromecast.Device) bool {
@@ -26,6 +44,31 @@ func WithType(t string) DeviceMatcher {
 	}
 }
 
+// WithinNetworks matches the devices whose IP belongs to one of the networks
+func WithinNetworks(networks ...*net.IPNet) DeviceMatcher {
+	return func(device *chromecast.Device) bool {
+		if device == nil {
+			return false
+		}
+		for _, n := range networks {
+			if n.Contains(device.IP) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// WithinCIDR matches the devices whose IP belongs to the network (like "192.168.1.0/24")
+// An invalid CIDR never matches.
+func WithinCIDR(cidr string) DeviceMatcher {
+	_, n, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return func(*chromecast.Device) bool { return false }
+	}
+	return WithinNetworks(n)
+}
+
 func matchAll(matchers ...DeviceMatcher) DeviceMatcher {
 	return func(device *chromecast.Device) bool {
 		for _, m := range matchers {
@@ -36,3 +79,24 @@ func matchAll(matchers ...DeviceMatcher) DeviceMatcher {
 		return true
 	}
 }
+
+// OnlyVideo matches the devices which can display video
+func OnlyVideo() DeviceMatcher {
+	return func(device *chromecast.Device) bool {
+		return device != nil && device.IsVideo()
+	}
+}
+
+// OnlyAudio matches the audio-only devices (like Chromecast Audio)
+func OnlyAudio() DeviceMatcher {
+	return func(device *chromecast.Device) bool {
+		return device != nil && device.IsAudio()
+	}
+}
+
+// OnlyGroup matches the cast groups
+func OnlyGroup() DeviceMatcher {
+	return func(device *chromecast.Device) bool {
+		return device != nil && device.IsGroup()
+	}
+}
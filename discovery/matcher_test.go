@@ -0,0 +1,95 @@
+package discovery_test
+
+import (
+	"net"
+	"testing"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/discovery"
+)
+
+func TestKindMatchers(t *testing.T) {
+	video := &chromecast.Device{Properties: map[string]string{"md": "Chromecast", "ca": "4101"}}
+	audio := &chromecast.Device{Properties: map[string]string{"md": "Chromecast Audio", "ca": "2052"}}
+	group := &chromecast.Device{Properties: map[string]string{"md": "Google Cast Group", "ca": "2084"}}
+
+	cc := []struct {
+		name     string
+		matcher  discovery.DeviceMatcher
+		expected [3]bool
+	}{
+		{"video", discovery.OnlyVideo(), [3]bool{true, false, false}},
+		{"audio", discovery.OnlyAudio(), [3]bool{false, true, false}},
+		{"group", discovery.OnlyGroup(), [3]bool{false, false, true}},
+	}
+	for _, c := range cc {
+		for i, d := range []*chromecast.Device{video, audio, group} {
+			if got := c.matcher(d); got != c.expected[i] {
+				t.Errorf("%s matcher: got %v for %s", c.name, got, d.Type())
+			}
+		}
+		if c.matcher(nil) {
+			t.Errorf("%s matcher should not match nil", c.name)
+		}
+	}
+}
+
+func TestWithID(t *testing.T) {
+	d := &chromecast.Device{Properties: map[string]string{"id": "3e1cc7c0f4f34d3b8f3a1234567890ab"}}
+	for _, id := range []string{
+		"3e1cc7c0f4f34d3b8f3a1234567890ab",
+		"3e1cc7c0-f4f3-4d3b-8f3a-1234567890ab",
+		"3E1CC7C0-F4F3-4D3B-8F3A-1234567890AB",
+	} {
+		if !discovery.WithID(id)(d) {
+			t.Errorf("%s should have matched", id)
+		}
+	}
+	if discovery.WithID("3e1cc7c0")(d) {
+		t.Errorf("a partial id should not match")
+	}
+}
+
+func TestWithFuzzyName(t *testing.T) {
+	d := &chromecast.Device{Properties: map[string]string{"fn": "Living Room TV"}}
+	for _, name := range []string{"Living Room TV", "living", "ROOM", "tv"} {
+		if !discovery.WithFuzzyName(name)(d) {
+			t.Errorf("%s should have matched", name)
+		}
+	}
+	if discovery.WithFuzzyName("kitchen")(d) {
+		t.Errorf("kitchen should not match")
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	d := &chromecast.Device{Properties: map[string]string{"ca": "4101"}}
+	expected := chromecast.DeviceCapabilities{VideoOut: true, AudioOut: true}
+	if got := d.Capabilities(); got != expected {
+		t.Errorf("got %+v, expected %+v", got, expected)
+	}
+	group := &chromecast.Device{Properties: map[string]string{"ca": "2084"}}
+	expected = chromecast.DeviceCapabilities{AudioOut: true, MultizoneGroup: true}
+	if got := group.Capabilities(); got != expected {
+		t.Errorf("got %+v, expected %+v", got, expected)
+	}
+}
+
+func TestWithinCIDR(t *testing.T) {
+	d := &chromecast.Device{IP: net.IPv4(192, 168, 1, 12)}
+	cc := []struct {
+		cidr     string
+		expected bool
+	}{
+		{"192.168.1.0/24", true},
+		{"192.168.0.0/16", true},
+		{"192.168.2.0/24", false},
+		{"fd00::/8", false},
+		{"invalid", false},
+	}
+	for _, c := range cc {
+		if got := discovery.WithinCIDR(c.cidr)(d); got != c.expected {
+			t.Errorf("got %v for %s", got, c.cidr)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// Multi returns a Scanner running all the scanners concurrently.
+// Their results are merged and deduplicated (see Uniq).
+func Multi(scanners ...Scanner) Scanner {
+	return multiScanner(scanners)
+}
+
+type multiScanner []Scanner
+
+// Scan fails only if none of the scanners could be started
+func (m multiScanner) Scan(ctx context.Context, results chan<- *chromecast.Device) error {
+	merged := make(chan *chromecast.Device, 5)
+
+	var wg sync.WaitGroup
+	var errs []string
+	for _, s := range m {
+		scanned := make(chan *chromecast.Device, 5)
+		if err := s.Scan(ctx, scanned); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for device := range scanned {
+				select {
+				case merged <- device:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+	if len(errs) == len(m) && len(m) > 0 {
+		return fmt.Errorf("no scanner could be started: %s", strings.Join(errs, "; "))
+	}
+
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+	go uniq(ctx, merged, results)
+	return nil
+}
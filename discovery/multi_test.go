@@ -0,0 +1,57 @@
+package discovery_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/discovery"
+)
+
+func sendingScanner(ids ...string) *MockedScanner {
+	return &MockedScanner{
+		ScanFunc: func(ctx context.Context, results chan<- *chromecast.Device) error {
+			go func() {
+				defer close(results)
+				for _, id := range ids {
+					results <- &chromecast.Device{Properties: map[string]string{"id": id}}
+				}
+			}()
+			return nil
+		},
+	}
+}
+
+func TestMulti(t *testing.T) {
+	failing := &MockedScanner{
+		ScanFunc: func(ctx context.Context, results chan<- *chromecast.Device) error {
+			return errors.New("multicast blocked")
+		},
+	}
+	scanner := discovery.Multi(sendingScanner("a", "b"), failing, sendingScanner("b", "c"))
+
+	results := make(chan *chromecast.Device, 5)
+	if err := scanner.Scan(context.Background(), results); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	found := make(map[string]int)
+	for d := range results {
+		found[d.ID()]++
+	}
+	if len(found) != 3 || found["a"] != 1 || found["b"] != 1 || found["c"] != 1 {
+		t.Errorf("each device should have been found once, got %v", found)
+	}
+}
+
+func TestMultiAllFailing(t *testing.T) {
+	failing := &MockedScanner{
+		ScanFunc: func(ctx context.Context, results chan<- *chromecast.Device) error {
+			return errors.New("multicast blocked")
+		},
+	}
+	scanner := discovery.Multi(failing, failing)
+	if err := scanner.Scan(context.Background(), make(chan *chromecast.Device)); err == nil {
+		t.Errorf("an error was expected")
+	}
+}
@@ -0,0 +1,216 @@
+// Package probe provides a Scanner which probes the local networks for an open cast port
+// It opens a lot of connections: it should only be used as a fallback when multicast is broken (see discovery.Fallback)
+package probe
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/discovery"
+	"github.com/oliverpool/go-chromecast/setupapi"
+)
+
+// MaxHosts is the maximum number of hosts probed per network
+const MaxHosts = 4096
+
+// Scanner probes all the addresses of the networks
+// Nil values are fine
+type Scanner struct {
+	// Networks to probe (the /24 of each local IPv4 address if empty)
+	Networks []*net.IPNet
+	// Port to probe (8009 if 0)
+	Port int
+	// Concurrency is the maximum number of simultaneous connections (32 if 0)
+	Concurrency int
+	// Timeout of each connection (500ms if 0)
+	Timeout time.Duration
+	// HTTPClient to query the setup API of the devices found (client with a 2s timeout if nil)
+	HTTPClient *http.Client
+	Logger     chromecast.Logger
+	// Errors receives the discovery.ScanError encountered (nil to ignore them)
+	Errors chan<- error
+}
+
+// Scan probes every address once and sends the chromecast found into the results channel.
+// The results channel is closed when all addresses have been probed (or when the context is done).
+func (s Scanner) Scan(ctx context.Context, results chan<- *chromecast.Device) error {
+	networks := s.Networks
+	if len(networks) == 0 {
+		var err error
+		networks, err = localNetworks()
+		if err != nil {
+			return s.fail("list local networks", err)
+		}
+	}
+	var hosts []net.IP
+	for _, n := range networks {
+		h, err := Hosts(n)
+		if err != nil {
+			return s.fail("list hosts", err)
+		}
+		hosts = append(hosts, h...)
+	}
+
+	concurrency := s.Concurrency
+	if concurrency <= 0 {
+		concurrency = 32
+	}
+
+	go func() {
+		defer close(results)
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, concurrency)
+		for _, ip := range hosts {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				break
+			}
+			wg.Add(1)
+			go func(ip net.IP) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				device := s.probe(ctx, ip)
+				if device == nil {
+					return
+				}
+				select {
+				case results <- device:
+				case <-ctx.Done():
+				}
+			}(ip)
+		}
+		wg.Wait()
+	}()
+	return nil
+}
+
+// probe returns a device if the cast port is open (enriched by the setup API if available)
+func (s Scanner) probe(ctx context.Context, ip net.IP) *chromecast.Device {
+	port := s.Port
+	if port == 0 {
+		port = 8009
+	}
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = 500 * time.Millisecond
+	}
+
+	device := discovery.NewDevice(ip, port, nil)
+	conn, err := (&net.Dialer{Timeout: timeout}).DialContext(ctx, "tcp", device.Addr())
+	if err != nil {
+		return nil
+	}
+	conn.Close()
+
+	client := setupapi.New(device)
+	client.HTTPClient = s.HTTPClient
+	if client.HTTPClient == nil {
+		client.HTTPClient = &http.Client{Timeout: 2 * time.Second}
+	}
+	info, err := client.EurekaInfo(ctx)
+	if err != nil {
+		s.log("step", "eureka_info", "addr", device.Addr(), "err", err)
+		return device
+	}
+	device.Info = info
+	device.Properties["fn"] = info.Name
+	device.Properties["id"] = strings.Replace(info.SSDPUDN, "-", "", -1)
+	return device
+}
+
+// Hosts returns the host addresses of an IPv4 network (without the network and broadcast addresses)
+func Hosts(n *net.IPNet) ([]net.IP, error) {
+	ip := n.IP.To4()
+	if ip == nil {
+		return nil, fmt.Errorf("network %s is not IPv4", n)
+	}
+	ones, bits := n.Mask.Size()
+	size := uint32(1) << uint(bits-ones)
+	if size > MaxHosts {
+		return nil, fmt.Errorf("network %s is too large (more than %d hosts)", n, MaxHosts)
+	}
+	first := binary.BigEndian.Uint32(ip.Mask(n.Mask))
+	if size <= 2 {
+		// point-to-point networks have no network and broadcast addresses
+		hosts := make([]net.IP, 0, size)
+		for i := uint32(0); i < size; i++ {
+			hosts = append(hosts, uint32ToIP(first+i))
+		}
+		return hosts, nil
+	}
+	hosts := make([]net.IP, 0, size-2)
+	for i := uint32(1); i < size-1; i++ {
+		hosts = append(hosts, uint32ToIP(first+i))
+	}
+	return hosts, nil
+}
+
+func uint32ToIP(u uint32) net.IP {
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, u)
+	return ip
+}
+
+// localNetworks returns the /24 of each local IPv4 address
+func localNetworks() ([]*net.IPNet, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var networks []*net.IPNet
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipnet, ok := addr.(*net.IPNet)
+			if !ok || ipnet.IP.To4() == nil {
+				continue
+			}
+			mask := net.CIDRMask(24, 32)
+			n := &net.IPNet{IP: ipnet.IP.To4().Mask(mask), Mask: mask}
+			if seen[n.String()] {
+				continue
+			}
+			seen[n.String()] = true
+			networks = append(networks, n)
+		}
+	}
+	if len(networks) == 0 {
+		return nil, fmt.Errorf("no local IPv4 network found")
+	}
+	return networks, nil
+}
+
+// fail logs, reports and returns a discovery.ScanError
+func (s Scanner) fail(step string, err error) error {
+	s.log("step", step, "err", err)
+	err = discovery.ScanError{Scanner: "probe", Step: step, Err: err}
+	discovery.ReportError(s.Errors, err)
+	return err
+}
+
+func (s Scanner) log(keyvals ...interface{}) {
+	if s.Logger == nil {
+		return
+	}
+	vals := make([]interface{}, 0, len(keyvals)+2)
+	vals = append(vals, "package", "probe")
+	vals = append(vals, keyvals...)
+	s.Logger.Log(vals...)
+}
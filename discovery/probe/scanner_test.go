@@ -0,0 +1,70 @@
+package probe_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/discovery"
+	"github.com/oliverpool/go-chromecast/discovery/probe"
+)
+
+// Ensure interface is satisfied
+var _ discovery.Scanner = probe.Scanner{}
+
+func TestHosts(t *testing.T) {
+	cc := []struct {
+		cidr  string
+		count int
+		first string
+		last  string
+	}{
+		{"192.168.1.0/24", 254, "192.168.1.1", "192.168.1.254"},
+		{"192.168.1.77/24", 254, "192.168.1.1", "192.168.1.254"},
+		{"10.0.0.0/31", 2, "10.0.0.0", "10.0.0.1"},
+	}
+	for _, c := range cc {
+		_, n, _ := net.ParseCIDR(c.cidr)
+		hosts, err := probe.Hosts(n)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", c.cidr, err)
+		}
+		if len(hosts) != c.count || hosts[0].String() != c.first || hosts[len(hosts)-1].String() != c.last {
+			t.Errorf("unexpected hosts for %s: %d [%s - %s]", c.cidr, len(hosts), hosts[0], hosts[len(hosts)-1])
+		}
+	}
+
+	_, large, _ := net.ParseCIDR("10.0.0.0/8")
+	if _, err := probe.Hosts(large); err == nil {
+		t.Errorf("an error was expected for a large network")
+	}
+}
+
+func TestScan(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	_, n, _ := net.ParseCIDR("127.0.0.0/30")
+	scanner := probe.Scanner{Networks: []*net.IPNet{n}, Port: port, Timeout: 100 * time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	results := make(chan *chromecast.Device, 5)
+	if err := scanner.Scan(ctx, results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var found []string
+	for d := range results {
+		found = append(found, d.Addr())
+	}
+	if len(found) != 1 || found[0] != listener.Addr().String() {
+		t.Errorf("only %s should have been found, got %v", listener.Addr(), found)
+	}
+}
@@ -0,0 +1,88 @@
+package discovery
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Selector of a device (only one of the fields is set, except the Port which comes with the IP)
+type Selector struct {
+	Name string
+	ID   string
+	IP   net.IP
+	Port int // 0 if not given
+}
+
+// ParseSelector parses a device selector like name:"Living Room", uuid:abcd or ip:192.168.1.4:8009 (the port is optional).
+// A selector without prefix is an IP (with an optional port) or a UUID (with or without dashes) if it looks like one,
+// or else a name.
+func ParseSelector(s string) (Selector, error) {
+	kind, value := "", s
+	if i := strings.Index(s, ":"); i > 0 {
+		switch prefix := strings.ToLower(s[:i]); prefix {
+		case "name", "uuid", "id", "ip":
+			kind, value = prefix, s[i+1:]
+		}
+	}
+	value = strings.TrimSpace(value)
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+	if value == "" {
+		return Selector{}, fmt.Errorf("empty device selector '%s'", s)
+	}
+
+	switch kind {
+	case "name":
+		return Selector{Name: value}, nil
+	case "uuid", "id":
+		return Selector{ID: value}, nil
+	case "ip":
+		sel, ok := parseIPSelector(value)
+		if !ok {
+			return Selector{}, fmt.Errorf("invalid IP in the device selector '%s'", s)
+		}
+		return sel, nil
+	}
+
+	if sel, ok := parseIPSelector(value); ok {
+		return sel, nil
+	}
+	if isUUID(value) {
+		return Selector{ID: value}, nil
+	}
+	return Selector{Name: value}, nil
+}
+
+// parseIPSelector parses an IP with an optional port (like 192.168.1.4:8009 or [fe80::1]:8009)
+func parseIPSelector(s string) (Selector, bool) {
+	if ip := net.ParseIP(strings.Trim(s, "[]")); ip != nil {
+		return Selector{IP: ip}, true
+	}
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		return Selector{}, false
+	}
+	ip := net.ParseIP(host)
+	p, err := strconv.Atoi(port)
+	if ip == nil || err != nil || p <= 0 || p > 65535 {
+		return Selector{}, false
+	}
+	return Selector{IP: ip, Port: p}, true
+}
+
+// isUUID returns true for 32 hexadecimal digits (dashes are ignored)
+func isUUID(s string) bool {
+	id := normalizeID(s)
+	if len(id) != 32 {
+		return false
+	}
+	for _, c := range id {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
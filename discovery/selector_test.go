@@ -0,0 +1,46 @@
+package discovery_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/oliverpool/go-chromecast/discovery"
+)
+
+func TestParseSelector(t *testing.T) {
+	cc := []struct {
+		selector string
+		expected discovery.Selector
+	}{
+		{`name:"Living Room"`, discovery.Selector{Name: "Living Room"}},
+		{"name:Living Room", discovery.Selector{Name: "Living Room"}},
+		{"name:192.168.1.4", discovery.Selector{Name: "192.168.1.4"}},
+		{"uuid:abcd", discovery.Selector{ID: "abcd"}},
+		{"id:abcd", discovery.Selector{ID: "abcd"}},
+		{"ip:192.168.1.4:8009", discovery.Selector{IP: net.ParseIP("192.168.1.4"), Port: 8009}},
+		{"ip:192.168.1.4", discovery.Selector{IP: net.ParseIP("192.168.1.4")}},
+		{"ip:[fe80::1]:8009", discovery.Selector{IP: net.ParseIP("fe80::1"), Port: 8009}},
+		{"192.168.1.4:8010", discovery.Selector{IP: net.ParseIP("192.168.1.4"), Port: 8010}},
+		{"fe80::1", discovery.Selector{IP: net.ParseIP("fe80::1")}},
+		{"3e1cc7c0-f4f3-4d3b-8f3a-1234567890ab", discovery.Selector{ID: "3e1cc7c0-f4f3-4d3b-8f3a-1234567890ab"}},
+		{"Living Room", discovery.Selector{Name: "Living Room"}},
+		{"Kitchen: speaker", discovery.Selector{Name: "Kitchen: speaker"}},
+		{"cafe", discovery.Selector{Name: "cafe"}},
+	}
+	for _, c := range cc {
+		got, err := discovery.ParseSelector(c.selector)
+		if err != nil {
+			t.Errorf("%s: %v", c.selector, err)
+			continue
+		}
+		if got.Name != c.expected.Name || got.ID != c.expected.ID || !got.IP.Equal(c.expected.IP) || got.Port != c.expected.Port {
+			t.Errorf("%s: got %+v, expected %+v", c.selector, got, c.expected)
+		}
+	}
+
+	for _, invalid := range []string{"", "name:", `uuid:""`, "ip:living-room", "ip:192.168.1.4:0"} {
+		if _, err := discovery.ParseSelector(invalid); err == nil {
+			t.Errorf("%q should be invalid", invalid)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+// Package ssdp provides a Scanner backed by SSDP/DIAL discovery (UPnP M-SEARCH)
+// It can find devices on networks where multicast DNS is filtered
+package ssdp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/discovery"
+)
+
+// SearchTarget is the DIAL service type that the chromecast answer to
+const SearchTarget = "urn:dial-multiscreen-org:service:dial:1"
+
+// CastPort is the port of the cast protocol (SSDP only advertises the DIAL HTTP port)
+const CastPort = 8009
+
+var multicastAddr = &net.UDPAddr{IP: net.IPv4(239, 255, 255, 250), Port: 1900}
+
+// Scanner backed by SSDP M-SEARCH requests
+// Nil values are fine
+type Scanner struct {
+	Logger chromecast.Logger
+	// Interval between two M-SEARCH requests (5s if 0)
+	Interval time.Duration
+	// HTTPClient used to fetch the device descriptions (http.DefaultClient if nil)
+	HTTPClient *http.Client
+	// Interfaces to send the M-SEARCH requests on (default multicast interface if empty)
+	Interfaces []net.Interface
+	// Errors receives the discovery.ScanError encountered (nil to ignore them)
+	Errors chan<- error
+}
+
+// Scan repeatedly sends M-SEARCH requests and sends the chromecast found into the results channel.
+// It finishes when the context is done.
+func (s Scanner) Scan(ctx context.Context, results chan<- *chromecast.Device) error {
+	conns, err := s.listen()
+	if err != nil {
+		return s.fail("listen", err)
+	}
+	for _, conn := range conns {
+		if err = s.search(conn); err != nil {
+			for _, conn := range conns {
+				conn.Close()
+			}
+			return s.fail("search", err)
+		}
+	}
+
+	responses := make(chan response, 5)
+	for _, conn := range conns {
+		go func(conn *net.UDPConn) {
+			<-ctx.Done()
+			conn.Close()
+		}(conn)
+		go s.searchRepeatedly(ctx, conn)
+		go s.read(ctx, conn, responses)
+	}
+
+	go func() {
+		defer close(results)
+		descriptions := make(map[string]*chromecast.Device)
+		for {
+			var r response
+			select {
+			case r = <-responses:
+			case <-ctx.Done():
+				return
+			}
+			description, ok := descriptions[r.location]
+			if !ok {
+				var err error
+				description, err = s.describe(ctx, r.location)
+				if err != nil {
+					s.fail("describe "+r.location, err)
+					continue
+				}
+				descriptions[r.location] = description
+			}
+			c := *description
+			c.TTL = r.ttl
+			select {
+			case results <- &c:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// listen opens one connection per interface (or a single one if no interfaces are specified)
+func (s Scanner) listen() ([]*net.UDPConn, error) {
+	if len(s.Interfaces) == 0 {
+		conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen for ssdp responses: %w", err)
+		}
+		return []*net.UDPConn{conn}, nil
+	}
+	conns := make([]*net.UDPConn, 0, len(s.Interfaces))
+	for i := range s.Interfaces {
+		// a multicast connection ensures that the M-SEARCH is sent on this interface
+		conn, err := net.ListenMulticastUDP("udp4", &s.Interfaces[i], multicastAddr)
+		if err != nil {
+			for _, conn := range conns {
+				conn.Close()
+			}
+			return nil, fmt.Errorf("failed to listen on interface %s: %w", s.Interfaces[i].Name, err)
+		}
+		conns = append(conns, conn)
+	}
+	return conns, nil
+}
+
+// read forwards the M-SEARCH responses, until the connection is closed
+func (s Scanner) read(ctx context.Context, conn net.PacketConn, responses chan<- response) {
+	buf := make([]byte, 2048)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			s.fail("read", err)
+			continue
+		}
+		r, err := parseResponse(buf[:n])
+		if err != nil {
+			s.log("step", "parse", "err", err)
+			continue
+		}
+		select {
+		case responses <- r:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s Scanner) searchRepeatedly(ctx context.Context, conn net.PacketConn) {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.search(conn); err != nil {
+				s.fail("search", err)
+			}
+		}
+	}
+}
+
+func (s Scanner) search(conn net.PacketConn) error {
+	req := "M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: " + multicastAddr.String() + "\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 1\r\n" +
+		"ST: " + SearchTarget + "\r\n" +
+		"\r\n"
+	if _, err := conn.WriteTo([]byte(req), multicastAddr); err != nil {
+		return fmt.Errorf("failed to send M-SEARCH: %w", err)
+	}
+	return nil
+}
+
+type response struct {
+	location string
+	ttl      time.Duration
+}
+
+// parseResponse returns the LOCATION and the max-age of an M-SEARCH response
+func parseResponse(b []byte) (r response, err error) {
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(b)), nil)
+	if err != nil {
+		return r, fmt.Errorf("could not read response: %w", err)
+	}
+	resp.Body.Close()
+	if st := resp.Header.Get("ST"); st != SearchTarget {
+		return r, fmt.Errorf("unexpected search target '%s'", st)
+	}
+	r.location = resp.Header.Get("LOCATION")
+	if r.location == "" {
+		return r, fmt.Errorf("no location in response")
+	}
+	for _, directive := range strings.Split(resp.Header.Get("CACHE-CONTROL"), ",") {
+		directive = strings.TrimSpace(directive)
+		if strings.HasPrefix(directive, "max-age=") {
+			seconds, _ := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			r.ttl = time.Duration(seconds) * time.Second
+		}
+	}
+	return r, nil
+}
+
+// describe fetches the device description and turns it into a chromecast.Device
+func (s Scanner) describe(ctx context.Context, location string) (*chromecast.Device, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse location: %w", err)
+	}
+	host := u.Hostname()
+	var zone string
+	if i := strings.LastIndex(host, "%"); i >= 0 {
+		host, zone = host[:i], host[i+1:]
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, fmt.Errorf("location host '%s' is not an IP", u.Hostname())
+	}
+
+	req, err := http.NewRequest("GET", location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare request: %w", err)
+	}
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch description: %w", err)
+	}
+	defer resp.Body.Close()
+
+	properties, err := decodeDescription(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	d := discovery.NewDevice(ip, CastPort, properties)
+	d.Zone = zone
+	return d, nil
+}
+
+// decodeDescription extracts the properties of a UPnP device description
+// (using the same keys as the mDNS TXT records)
+func decodeDescription(body io.Reader) ([]string, error) {
+	var description struct {
+		Device struct {
+			FriendlyName string `xml:"friendlyName"`
+			ModelName    string `xml:"modelName"`
+			UDN          string `xml:"UDN"`
+		} `xml:"device"`
+	}
+	if err := xml.NewDecoder(body).Decode(&description); err != nil {
+		return nil, fmt.Errorf("could not decode description: %w", err)
+	}
+	d := description.Device
+	id := strings.Replace(strings.TrimPrefix(d.UDN, "uuid:"), "-", "", -1)
+	return []string{
+		"fn=" + d.FriendlyName,
+		"md=" + d.ModelName,
+		"id=" + id,
+	}, nil
+}
+
+// fail logs, reports and returns a discovery.ScanError
+func (s Scanner) fail(step string, err error) error {
+	s.log("step", step, "err", err)
+	err = discovery.ScanError{Scanner: "ssdp", Step: step, Err: err}
+	discovery.ReportError(s.Errors, err)
+	return err
+}
+
+func (s Scanner) log(keyvals ...interface{}) {
+	if s.Logger == nil {
+		return
+	}
+	vals := make([]interface{}, 0, len(keyvals)+2)
+	vals = append(vals, "package", "ssdp")
+	vals = append(vals, keyvals...)
+	s.Logger.Log(vals...)
+}
@@ -0,0 +1,70 @@
+package ssdp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oliverpool/go-chromecast/discovery"
+)
+
+// Ensure interface is satisfied
+var _ discovery.Scanner = Scanner{}
+
+func TestParseResponse(t *testing.T) {
+	response := "HTTP/1.1 200 OK\r\n" +
+		"CACHE-CONTROL: max-age=1800\r\n" +
+		"EXT:\r\n" +
+		"LOCATION: http://192.168.1.10:8008/ssdp/device-desc.xml\r\n" +
+		"SERVER: Linux/3.8.13+, UPnP/1.0, Portable SDK for UPnP devices/1.6.18\r\n" +
+		"ST: urn:dial-multiscreen-org:service:dial:1\r\n" +
+		"USN: uuid:3e1cc7c0-f4f3-4d3b-8f3a-1234567890ab::urn:dial-multiscreen-org:service:dial:1\r\n" +
+		"\r\n"
+
+	got, err := parseResponse([]byte(response))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "http://192.168.1.10:8008/ssdp/device-desc.xml"
+	if got.location != expected {
+		t.Errorf("got '%s', expected '%s'", got.location, expected)
+	}
+	if got.ttl != 30*time.Minute {
+		t.Errorf("got a TTL of %s, expected 30m", got.ttl)
+	}
+
+	_, err = parseResponse([]byte(strings.Replace(response, "dial:1", "dial:2", -1)))
+	if err == nil {
+		t.Errorf("an error was expected for an other search target")
+	}
+}
+
+func TestDecodeDescription(t *testing.T) {
+	body := strings.NewReader(`<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <specVersion><major>1</major><minor>0</minor></specVersion>
+  <URLBase>http://192.168.1.10:8008</URLBase>
+  <device>
+    <deviceType>urn:dial-multiscreen-org:device:dial:1</deviceType>
+    <friendlyName>Living Room</friendlyName>
+    <manufacturer>Google Inc.</manufacturer>
+    <modelName>Eureka Dongle</modelName>
+    <UDN>uuid:3e1cc7c0-f4f3-4d3b-8f3a-1234567890ab</UDN>
+  </device>
+</root>`)
+
+	got, err := decodeDescription(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := discovery.NewDevice(nil, CastPort, got)
+	if d.Name() != "Living Room" {
+		t.Errorf("unexpected name: %s", d.Name())
+	}
+	if d.Type() != "Eureka Dongle" {
+		t.Errorf("unexpected type: %s", d.Type())
+	}
+	if d.ID() != "3e1cc7c0f4f34d3b8f3a1234567890ab" {
+		t.Errorf("unexpected ID: %s", d.ID())
+	}
+}
@@ -0,0 +1,82 @@
+package discovery
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// Tracker keeps track of the devices currently available on the network.
+// A device expires when it has not been seen during its TTL.
+type Tracker struct {
+	// TTL of the devices which do not announce one (DefaultTTL if 0)
+	TTL time.Duration
+
+	mu      sync.Mutex
+	devices map[string]trackedDevice
+}
+
+type trackedDevice struct {
+	device    *chromecast.Device
+	expiresAt time.Time
+}
+
+// Update records that the device has just been seen.
+// It returns DeviceFound or DeviceUpdated (changed is false if the device was already known as-is)
+func (t *Tracker) Update(device *chromecast.Device) (e EventType, changed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.devices == nil {
+		t.devices = make(map[string]trackedDevice)
+	}
+
+	ttl := device.TTL
+	if ttl <= 0 {
+		ttl = t.TTL
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	previous, ok := t.devices[uniqKey(device)]
+	t.devices[uniqKey(device)] = trackedDevice{
+		device:    device,
+		expiresAt: time.Now().Add(ttl),
+	}
+	if !ok {
+		return DeviceFound, true
+	}
+	return DeviceUpdated, !sameDevice(previous.device, device)
+}
+
+// Expire forgets and returns the devices whose TTL lapsed
+func (t *Tracker) Expire() (lost []*chromecast.Device) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	for id, tracked := range t.devices {
+		if now.Before(tracked.expiresAt) {
+			continue
+		}
+		delete(t.devices, id)
+		lost = append(lost, tracked.device)
+	}
+	return lost
+}
+
+// Devices returns the devices which have not expired yet (sorted by name)
+func (t *Tracker) Devices() []*chromecast.Device {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	devices := make([]*chromecast.Device, 0, len(t.devices))
+	for _, tracked := range t.devices {
+		if now.Before(tracked.expiresAt) {
+			devices = append(devices, tracked.device)
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Name() < devices[j].Name() })
+	return devices
+}
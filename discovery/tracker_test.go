@@ -0,0 +1,45 @@
+package discovery_test
+
+import (
+	"testing"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/discovery"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := discovery.Tracker{TTL: time.Hour}
+
+	short := &chromecast.Device{TTL: 10 * time.Millisecond, Properties: map[string]string{"id": "short", "fn": "b"}}
+	long := &chromecast.Device{Properties: map[string]string{"id": "long", "fn": "a"}}
+
+	if e, changed := tracker.Update(short); e != discovery.DeviceFound || !changed {
+		t.Errorf("short should have been found, got %s %v", e, changed)
+	}
+	if e, changed := tracker.Update(long); e != discovery.DeviceFound || !changed {
+		t.Errorf("long should have been found, got %s %v", e, changed)
+	}
+	if _, changed := tracker.Update(long); changed {
+		t.Errorf("long should not have changed")
+	}
+	updated := &chromecast.Device{Properties: map[string]string{"id": "long", "fn": "a", "rs": "playing"}}
+	if e, changed := tracker.Update(updated); e != discovery.DeviceUpdated || !changed {
+		t.Errorf("long should have been updated, got %s %v", e, changed)
+	}
+
+	if devices := tracker.Devices(); len(devices) != 2 || devices[0].ID() != "long" {
+		t.Errorf("unexpected devices %v", devices)
+	}
+	if lost := tracker.Expire(); len(lost) != 0 {
+		t.Errorf("no device should have expired yet, got %v", lost)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if lost := tracker.Expire(); len(lost) != 1 || lost[0].ID() != "short" {
+		t.Errorf("short should have expired, got %v", lost)
+	}
+	if devices := tracker.Devices(); len(devices) != 1 || devices[0].ID() != "long" {
+		t.Errorf("unexpected devices %v", devices)
+	}
+}
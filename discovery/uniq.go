@@ -1,19 +1,41 @@
 package discovery
 
-import chromecast "github.com/oliverpool/go-chromecast"
+import (
+	"context"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
 
 // Uniq forward all client deduplicated
 func Uniq(in <-chan *chromecast.Device, out chan<- *chromecast.Device) {
+	uniq(context.Background(), in, out)
+}
+
+// uniq stops forwarding when the ctx is done (but still drains in)
+func uniq(ctx context.Context, in <-chan *chromecast.Device, out chan<- *chromecast.Device) {
 	seen := make(map[string]struct{})
 	for c := range in {
 		if c == nil {
 			continue
 		}
-		if _, ok := seen[c.ID()]; ok {
+		key := uniqKey(c)
+		if _, ok := seen[key]; ok {
 			continue
 		}
-		out <- c
-		seen[c.ID()] = struct{}{}
+		select {
+		case out <- c:
+		case <-ctx.Done():
+		}
+		seen[key] = struct{}{}
 	}
 	close(out)
 }
+
+// uniqKey identifies the device: by ID, or by address if it has no ID
+// (like the devices found by probing, whose setup API did not return a UDN)
+func uniqKey(d *chromecast.Device) string {
+	if id := d.ID(); id != "" {
+		return id
+	}
+	return d.Addr()
+}
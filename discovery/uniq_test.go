@@ -1,6 +1,7 @@
 package discovery_test
 
 import (
+	"net"
 	"testing"
 
 	chromecast "github.com/oliverpool/go-chromecast"
@@ -37,3 +38,24 @@ func TestUniq(t *testing.T) {
 		t.Error("out should have been closed")
 	}
 }
+
+func TestUniqWithoutID(t *testing.T) {
+	in := make(chan *chromecast.Device, 10)
+	in <- &chromecast.Device{IP: net.ParseIP("192.168.1.4"), Port: 8009}
+	in <- &chromecast.Device{IP: net.ParseIP("192.168.1.5"), Port: 8009}
+	in <- &chromecast.Device{IP: net.ParseIP("192.168.1.4"), Port: 8009}
+	close(in)
+
+	out := make(chan *chromecast.Device, 3)
+	discovery.Uniq(in, out)
+	if c := <-out; c.Addr() != "192.168.1.4:8009" {
+		t.Errorf("unexpected address: %s", c.Addr())
+	}
+	if c := <-out; c.Addr() != "192.168.1.5:8009" {
+		t.Errorf("unexpected address: %s", c.Addr())
+	}
+	_, ok := <-out
+	if ok {
+		t.Error("out should have been closed")
+	}
+}
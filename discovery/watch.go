@@ -0,0 +1,113 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// DefaultTTL is the TTL announced by the chromecasts for their mDNS records
+const DefaultTTL = 2 * time.Minute
+
+// EventType indicates what happened to a device
+type EventType int
+
+// EventTypes
+const (
+	DeviceFound EventType = iota
+	DeviceUpdated
+	DeviceLost
+)
+
+func (t EventType) String() string {
+	switch t {
+	case DeviceFound:
+		return "found"
+	case DeviceUpdated:
+		return "updated"
+	case DeviceLost:
+		return "lost"
+	}
+	return fmt.Sprintf("EventType(%d)", int(t))
+}
+
+// Event is emitted by Watch when a device appears, changes or disappears
+type Event struct {
+	Type   EventType
+	Device *chromecast.Device
+}
+
+// Watch scans until cancellation of the context and emits an Event for every change of the devices on the network.
+// The scanner is restarted every TTL/2 and a device which has not been seen during its TTL
+// (or the TTL of the Service if it doesn't announce one) is considered lost.
+// The events channel is closed when the ctx is done (or if the scanner could not be restarted)
+func (s Service) Watch(ctx context.Context, matchers ...DeviceMatcher) (<-chan Event, error) {
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	scanned := make(chan *chromecast.Device, 5)
+	roundCtx, cancel := context.WithTimeout(ctx, ttl/2)
+	err := s.Scanner.Scan(roundCtx, scanned)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("could not initiliaze scanner: %w", err)
+	}
+
+	events := make(chan Event, 5)
+	go func() {
+		defer close(events)
+		defer func() { cancel() }()
+
+		emit := func(t EventType, device *chromecast.Device) bool {
+			select {
+			case events <- Event{Type: t, Device: device}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		match := matchAll(matchers...)
+		tracker := Tracker{TTL: ttl}
+		for {
+			for device := range scanned {
+				if device == nil || !match(device) {
+					continue
+				}
+				if t, changed := tracker.Update(device); changed {
+					if !emit(t, device) {
+						return
+					}
+				}
+			}
+
+			// the scanner closed the channel: wait for the end of the round
+			<-roundCtx.Done()
+			cancel()
+			for _, device := range tracker.Expire() {
+				if !emit(DeviceLost, device) {
+					return
+				}
+			}
+			if ctx.Err() != nil {
+				return
+			}
+
+			scanned = make(chan *chromecast.Device, 5)
+			roundCtx, cancel = context.WithTimeout(ctx, ttl/2)
+			if err := s.Scanner.Scan(roundCtx, scanned); err != nil {
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+func sameDevice(a, b *chromecast.Device) bool {
+	return a.Addr() == b.Addr() && reflect.DeepEqual(a.Properties, b.Properties)
+}
@@ -0,0 +1,85 @@
+package discovery_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/discovery"
+)
+
+func TestWatch(t *testing.T) {
+	rounds := [][]*chromecast.Device{
+		{
+			{Properties: map[string]string{"id": "a", "rs": "idle"}},
+			{Properties: map[string]string{"id": "b"}},
+		},
+		{
+			{Properties: map[string]string{"id": "a", "rs": "playing"}},
+		},
+	}
+	round := 0
+	scan := MockedScanner{
+		ScanFunc: func(ctx context.Context, results chan<- *chromecast.Device) error {
+			var devices []*chromecast.Device
+			if round < len(rounds) {
+				devices = rounds[round]
+			}
+			round++
+			go func() {
+				defer close(results)
+				for _, d := range devices {
+					results <- d
+				}
+				<-ctx.Done()
+			}()
+			return nil
+		},
+	}
+
+	service := discovery.Service{Scanner: &scan, TTL: 40 * time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	events, err := service.Watch(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := []struct {
+		Type discovery.EventType
+		ID   string
+	}{
+		{discovery.DeviceFound, "a"},
+		{discovery.DeviceFound, "b"},
+		{discovery.DeviceUpdated, "a"},
+	}
+	for _, e := range expected {
+		got, ok := <-events
+		if !ok {
+			t.Fatalf("events channel closed, expected %s %s", e.Type, e.ID)
+		}
+		if got.Type != e.Type || got.Device.ID() != e.ID {
+			t.Errorf("got %s %s, expected %s %s", got.Type, got.Device.ID(), e.Type, e.ID)
+		}
+	}
+	lost := make(map[string]bool)
+	for len(lost) < 2 {
+		got, ok := <-events
+		if !ok {
+			t.Fatalf("events channel closed, expected lost devices")
+		}
+		if got.Type != discovery.DeviceLost {
+			t.Errorf("got %s %s, expected a lost device", got.Type, got.Device.ID())
+		}
+		lost[got.Device.ID()] = true
+	}
+	if !lost["a"] || !lost["b"] {
+		t.Errorf("a and b should have been lost, got %v", lost)
+	}
+	cancel()
+	for range events {
+	}
+}
@@ -0,0 +1,39 @@
+package zeroconf
+
+import (
+	"net"
+	"testing"
+
+	"github.com/grandcat/zeroconf"
+)
+
+func TestDecodeAddr(t *testing.T) {
+	eth0 := net.Interface{Name: "eth0"}
+	linkLocal := net.ParseIP("fe80::1")
+	global := net.ParseIP("2001:db8::1")
+	ipv4 := net.IPv4(192, 168, 1, 2)
+
+	cc := []struct {
+		scanner  Scanner
+		ipv6     []net.IP
+		ipv4     []net.IP
+		expected string
+	}{
+		{Scanner{}, []net.IP{linkLocal, global}, []net.IP{ipv4}, "[2001:db8::1]:8009"},
+		{Scanner{}, []net.IP{linkLocal}, []net.IP{ipv4}, "192.168.1.2:8009"},
+		{Scanner{Interfaces: []net.Interface{eth0}}, []net.IP{linkLocal}, nil, "[fe80::1%eth0]:8009"},
+	}
+	for _, c := range cc {
+		entry := zeroconf.NewServiceEntry("casti", "_googlecast._tcp", "local")
+		entry.Port = 8009
+		entry.AddrIPv6 = c.ipv6
+		entry.AddrIPv4 = c.ipv4
+		d, err := c.scanner.decode(entry)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Addr() != c.expected {
+			t.Errorf("got '%s', expected '%s'", d.Addr(), c.expected)
+		}
+	}
+}
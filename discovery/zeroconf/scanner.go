@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/grandcat/zeroconf"
 	chromecast "github.com/oliverpool/go-chromecast"
@@ -19,6 +20,10 @@ type Scanner struct {
 	Logger chromecast.Logger
 	// nil value should be good enough
 	ClientOptions []zeroconf.ClientOption
+	// Interfaces to scan on (all multicast interfaces if empty)
+	Interfaces []net.Interface
+	// Errors receives the discovery.ScanError encountered (nil to ignore them)
+	Errors chan<- error
 }
 
 // Scan repeatedly scans the network and sends the chromecast found into the results channel.
@@ -26,15 +31,19 @@ type Scanner struct {
 func (s Scanner) Scan(ctx context.Context, results chan<- *chromecast.Device) error {
 	// generate entries
 	// Discover all services on the network (e.g. _workstation._tcp)
-	resolver, err := zeroconf.NewResolver(s.ClientOptions...)
+	options := s.ClientOptions
+	if len(s.Interfaces) > 0 {
+		options = append(options[:len(options):len(options)], zeroconf.SelectIfaces(s.Interfaces))
+	}
+	resolver, err := zeroconf.NewResolver(options...)
 	if err != nil {
-		return fmt.Errorf("failed to initialize resolver: %w", err)
+		return s.fail("initialize resolver", err)
 	}
 
 	entries := make(chan *zeroconf.ServiceEntry, 5)
 	err = resolver.Browse(ctx, "_googlecast._tcp", "local", entries)
 	if err != nil {
-		return fmt.Errorf("fail to browse services: %w", err)
+		return s.fail("browse services", err)
 	}
 
 	go func() {
@@ -63,14 +72,40 @@ func (s Scanner) decode(entry *zeroconf.ServiceEntry) (*chromecast.Device, error
 		return nil, fmt.Errorf("fdqn '%s does not contain '_googlecast.'", entry.Service)
 	}
 
-	var ip net.IP
+	ip, zone := s.decodeAddr(entry)
+	d := discovery.NewDevice(ip, entry.Port, entry.Text)
+	d.Zone = zone
+	d.TTL = time.Duration(entry.TTL) * time.Second
+	return d, nil
+}
+
+// decodeAddr picks a dialable address, by order of preference:
+// a global IPv6, an IPv4, a link-local IPv6 (with its zone if only one interface is scanned)
+func (s Scanner) decodeAddr(entry *zeroconf.ServiceEntry) (net.IP, string) {
+	for _, ip := range entry.AddrIPv6 {
+		if !ip.IsLinkLocalUnicast() {
+			return ip, ""
+		}
+	}
+	if len(entry.AddrIPv4) > 0 {
+		return entry.AddrIPv4[0], ""
+	}
 	if len(entry.AddrIPv6) > 0 {
-		ip = entry.AddrIPv6[0]
-	} else if len(entry.AddrIPv4) > 0 {
-		ip = entry.AddrIPv4[0]
+		var zone string
+		if len(s.Interfaces) == 1 {
+			zone = s.Interfaces[0].Name
+		}
+		return entry.AddrIPv6[0], zone
 	}
+	return nil, ""
+}
 
-	return discovery.NewDevice(ip, entry.Port, entry.Text), nil
+// fail logs, reports and returns a discovery.ScanError
+func (s Scanner) fail(step string, err error) error {
+	s.log("step", step, "err", err)
+	err = discovery.ScanError{Scanner: "zeroconf", Step: step, Err: err}
+	discovery.ReportError(s.Errors, err)
+	return err
 }
 
 func (s Scanner) log(keyvals ...interface{}) {
@@ -1,5 +1,7 @@
 package chromecast
 
+import "fmt"
+
 // ErrorString represents some constant errors
 type ErrorString string
 
@@ -10,3 +12,60 @@ func (e ErrorString) Error() string {
 
 // ErrAppNotFound is returned when a given app was not found
 const ErrAppNotFound = ErrorString("app not found")
+
+// ErrRequestTimeout is returned when no response was received before the context was done
+const ErrRequestTimeout = ErrorString("request timeout")
+
+// ErrConnectionClosed is returned when the connection was closed before the end of an operation
+const ErrConnectionClosed = ErrorString("connection closed")
+
+// ErrQueueFull is returned when a message could not be queued for sending
+const ErrQueueFull = ErrorString("send queue full")
+
+// ErrEmptyPayload is returned when the chromecast answered with an empty payload
+const ErrEmptyPayload = ErrorString("empty payload")
+
+// ErrNoSession is returned when no media session could be found
+const ErrNoSession = ErrorString("no media session")
+
+// ErrNoTransport is returned when an app has no transportId to connect to
+const ErrNoTransport = ErrorString("app has no transportId")
+
+// RequestError is returned when the chromecast answered a request with an error type
+// (like INVALID_REQUEST or INVALID_PLAYER_STATE)
+type RequestError struct {
+	Type   string
+	Reason string
+}
+
+func (e RequestError) Error() string {
+	if e.Reason == "" {
+		return "request failed: " + e.Type
+	}
+	return fmt.Sprintf("request failed: %s (%s)", e.Type, e.Reason)
+}
+
+// LaunchError is returned when the chromecast could not launch an app (LAUNCH_ERROR)
+type LaunchError struct {
+	Reason string
+}
+
+func (e LaunchError) Error() string {
+	return "launch failed: " + e.Reason
+}
+
+// LoadFailedError is returned when the chromecast could not load a media (LOAD_FAILED or LOAD_CANCELLED)
+type LoadFailedError struct {
+	Reason string
+	// Cancelled is true for a LOAD_CANCELLED (the load was interrupted by another request)
+	Cancelled bool
+	// DetailedErrorCode of the receiver (0 if not provided)
+	DetailedErrorCode int
+}
+
+func (e LoadFailedError) Error() string {
+	if e.DetailedErrorCode != 0 {
+		return fmt.Sprintf("load failed: %s (code %d)", e.Reason, e.DetailedErrorCode)
+	}
+	return "load failed: " + e.Reason
+}
@@ -1,6 +1,7 @@
 module github.com/oliverpool/go-chromecast
 
 require (
+	github.com/BurntSushi/toml v0.3.1
 	github.com/cenkalti/backoff v1.1.0 // indirect
 	github.com/go-kit/kit v0.7.0
 	github.com/go-logfmt/logfmt v0.3.0 // indirect
@@ -14,7 +15,7 @@ require (
 	github.com/mattn/go-isatty v0.0.12 // indirect
 	github.com/miekg/dns v1.0.8 // indirect
 	github.com/spf13/cobra v0.0.3
-	github.com/spf13/pflag v1.0.1 // indirect
+	github.com/spf13/pflag v1.0.1
 	golang.org/x/crypto v0.0.0-20200208060501-ecb85df21340 // indirect
 )
 
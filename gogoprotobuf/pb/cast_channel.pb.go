@@ -32,13 +32,22 @@ type CastMessage_ProtocolVersion int32
 
 const (
 	CastMessage_CASTV2_1_0 CastMessage_ProtocolVersion = 0
+	CastMessage_CASTV2_1_1 CastMessage_ProtocolVersion = 1
+	CastMessage_CASTV2_1_2 CastMessage_ProtocolVersion = 2
+	CastMessage_CASTV2_1_3 CastMessage_ProtocolVersion = 3
 )
 
 var CastMessage_ProtocolVersion_name = map[int32]string{
 	0: "CASTV2_1_0",
+	1: "CASTV2_1_1",
+	2: "CASTV2_1_2",
+	3: "CASTV2_1_3",
 }
 var CastMessage_ProtocolVersion_value = map[string]int32{
 	"CASTV2_1_0": 0,
+	"CASTV2_1_1": 1,
+	"CASTV2_1_2": 2,
+	"CASTV2_1_3": 3,
 }
 
 func (x CastMessage_ProtocolVersion) Enum() *CastMessage_ProtocolVersion {
@@ -150,8 +159,18 @@ type CastMessage struct {
 	PayloadType *CastMessage_PayloadType `protobuf:"varint,5,req,name=payload_type,enum=pb.CastMessage_PayloadType" json:"payload_type,omitempty"`
 	// Depending on payload_type, exactly one of the following optional fields
 	// will always be set.
-	PayloadUtf8      *string `protobuf:"bytes,6,opt,name=payload_utf8" json:"payload_utf8,omitempty"`
-	PayloadBinary    []byte  `protobuf:"bytes,7,opt,name=payload_binary" json:"payload_binary,omitempty"`
+	PayloadUtf8   *string `protobuf:"bytes,6,opt,name=payload_utf8" json:"payload_utf8,omitempty"`
+	PayloadBinary []byte  `protobuf:"bytes,7,opt,name=payload_binary" json:"payload_binary,omitempty"`
+	// Flag indicating whether there are more chunks to follow for this message.
+	// If the flag is false or is not present, then this is the last (or only)
+	// chunk of the message.
+	Continued *bool `protobuf:"varint,8,opt,name=continued" json:"continued,omitempty"`
+	// If this is a chunk of a larger message, and the remaining length of the
+	// message payload (the sum of the lengths of the payloads of the remaining
+	// chunks) is known, this field will indicate that length. For a given
+	// chunked message, this field should either be present in all of the chunks,
+	// or in none of them.
+	RemainingLength  *uint32 `protobuf:"varint,9,opt,name=remaining_length" json:"remaining_length,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
@@ -208,6 +227,20 @@ func (m *CastMessage) GetPayloadBinary() []byte {
 	return nil
 }
 
+func (m *CastMessage) GetContinued() bool {
+	if m != nil && m.Continued != nil {
+		return *m.Continued
+	}
+	return false
+}
+
+func (m *CastMessage) GetRemainingLength() uint32 {
+	if m != nil && m.RemainingLength != nil {
+		return *m.RemainingLength
+	}
+	return 0
+}
+
 // Messages for authentication protocol between a sender and a receiver.
 type AuthChallenge struct {
 	XXX_unrecognized []byte `json:"-"`
@@ -19,36 +19,43 @@ type Serializer struct {
 	sMu    sync.Mutex
 }
 
-// Receive receives a message
+// Receive receives a message (binary payloads are returned as-is)
 func (s *Serializer) Receive() (env chromecast.Envelope, pay []byte, err error) {
+	env, pay, _, err = s.ReceiveAny()
+	return env, pay, err
+}
+
+// ReceiveAny receives a message, isBinary is true for a binary payload
+func (s *Serializer) ReceiveAny() (env chromecast.Envelope, pay []byte, isBinary bool, err error) {
 	s.rMu.Lock()
 	defer s.rMu.Unlock()
 
-	var length uint32
-	err = binary.Read(s.Conn, binary.BigEndian, &length)
+	cmessage, err := s.readMessage()
 	if err != nil {
-		return env, pay, fmt.Errorf("failed to read packet length: %s", err)
+		return env, pay, false, err
 	}
-	if length == 0 {
-		return env, pay, fmt.Errorf("empty packet")
+	// the chunks of a large message are concatenated
+	for chunk := cmessage; chunk.GetContinued(); {
+		chunk, err = s.readMessage()
+		if err != nil {
+			return env, pay, false, fmt.Errorf("failed to read chunk: %w", err)
+		}
+		cmessage.PayloadBinary = append(cmessage.PayloadBinary, chunk.GetPayloadBinary()...)
+		utf8 := cmessage.GetPayloadUtf8() + chunk.GetPayloadUtf8()
+		cmessage.PayloadUtf8 = &utf8
 	}
 
-	packet := make([]byte, length)
-	_, err = io.ReadFull(s.Conn, packet)
-	if err != nil {
-		return env, pay, fmt.Errorf("failed to read full packet: %s", err)
-	}
-
-	cmessage := &pb.CastMessage{}
-	err = proto.Unmarshal(packet, cmessage)
-	if err != nil {
-		return env, pay, fmt.Errorf("failed to unmarshal packet: %s", err)
+	env = chromecast.Envelope{
+		Source:      cmessage.GetSourceId(),
+		Destination: cmessage.GetDestinationId(),
+		Namespace:   cmessage.GetNamespace(),
 	}
 
-	env = chromecast.Envelope{
-		Source:      *cmessage.SourceId,
-		Destination: *cmessage.DestinationId,
-		Namespace:   *cmessage.Namespace,
+	isBinary = cmessage.GetPayloadType() == pb.CastMessage_BINARY
+	if isBinary {
+		pay = cmessage.GetPayloadBinary()
+	} else {
+		pay = []byte(cmessage.GetPayloadUtf8())
 	}
 
 	s.Logger.Log(
@@ -56,22 +63,72 @@ func (s *Serializer) Receive() (env chromecast.Envelope, pay []byte, err error)
 		// "destination", env.Destination,
 		// "source", env.Source,
 		"namespace", env.Namespace,
-		"payload", strings.Replace(*cmessage.PayloadUtf8, `"`, `'`, -1),
+		"payload", logPayload(pay, isBinary),
 	)
 
-	return env, []byte(*cmessage.PayloadUtf8), nil
+	return env, pay, isBinary, nil
+}
+
+// MaxMessageSize is the maximum size of a packet (a larger message is split in chunks)
+const MaxMessageSize = 64 << 10
+
+func (s *Serializer) readMessage() (*pb.CastMessage, error) {
+	var length uint32
+	err := binary.Read(s.Conn, binary.BigEndian, &length)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read packet length: %s", err)
+	}
+	if length == 0 {
+		return nil, fmt.Errorf("empty packet")
+	}
+	if length > MaxMessageSize {
+		return nil, fmt.Errorf("packet too large: %d bytes", length)
+	}
+
+	packet := make([]byte, length)
+	_, err = io.ReadFull(s.Conn, packet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read full packet: %s", err)
+	}
+
+	cmessage := &pb.CastMessage{}
+	err = proto.Unmarshal(packet, cmessage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal packet: %s", err)
+	}
+	return cmessage, nil
 }
 
 // Send sends a payload
 func (s *Serializer) Send(env chromecast.Envelope, pay []byte) error {
 	payloadString := string(pay)
-	message := &pb.CastMessage{
+	return s.send(&pb.CastMessage{
 		ProtocolVersion: pb.CastMessage_CASTV2_1_0.Enum(),
 		SourceId:        &env.Source,
 		DestinationId:   &env.Destination,
 		Namespace:       &env.Namespace,
 		PayloadType:     pb.CastMessage_STRING.Enum(),
 		PayloadUtf8:     &payloadString,
+	}, pay, false)
+}
+
+// SendBinary sends a binary payload
+func (s *Serializer) SendBinary(env chromecast.Envelope, pay []byte) error {
+	return s.send(&pb.CastMessage{
+		ProtocolVersion: pb.CastMessage_CASTV2_1_0.Enum(),
+		SourceId:        &env.Source,
+		DestinationId:   &env.Destination,
+		Namespace:       &env.Namespace,
+		PayloadType:     pb.CastMessage_BINARY.Enum(),
+		PayloadBinary:   pay,
+	}, pay, true)
+}
+
+func (s *Serializer) send(message *pb.CastMessage, pay []byte, isBinary bool) error {
+	env := chromecast.Envelope{
+		Source:      message.GetSourceId(),
+		Destination: message.GetDestinationId(),
+		Namespace:   message.GetNamespace(),
 	}
 
 	proto.SetDefaults(message)
@@ -86,7 +143,7 @@ func (s *Serializer) Send(env chromecast.Envelope, pay []byte) error {
 		// "source", env.Source,
 		// "destination", env.Destination,
 		"namespace", env.Namespace,
-		"payload", strings.Replace(*message.PayloadUtf8, `"`, `'`, -1),
+		"payload", logPayload(pay, isBinary),
 	)
 
 	s.sMu.Lock()
@@ -103,3 +160,10 @@ func (s *Serializer) Send(env chromecast.Envelope, pay []byte) error {
 
 	return nil
 }
+
+func logPayload(pay []byte, isBinary bool) string {
+	if isBinary {
+		return fmt.Sprintf("<%d bytes>", len(pay))
+	}
+	return strings.Replace(string(pay), `"`, `'`, -1)
+}
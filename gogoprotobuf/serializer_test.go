@@ -0,0 +1,72 @@
+package gogoprotobuf_test
+
+import (
+	"bytes"
+	"testing"
+
+	gogoproto "github.com/gogo/protobuf/proto"
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/gogoprotobuf"
+	"github.com/oliverpool/go-chromecast/log"
+	"github.com/oliverpool/go-chromecast/proto"
+)
+
+var _ chromecast.BinarySerializer = &gogoprotobuf.Serializer{}
+
+func TestRoundTrip(t *testing.T) {
+	var conn bytes.Buffer
+	s := gogoprotobuf.Serializer{Conn: &conn, Logger: log.NopLogger()}
+	env := chromecast.Envelope{Source: "sender-0", Destination: "receiver-0", Namespace: "urn:x-cast:com.example.custom"}
+
+	binaryPayload := []byte{0x00, 0xff, 0xfe, 'a', 0x80}
+	if err := s.SendBinary(env, binaryPayload); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(env, []byte(`{"type":"PING"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	gotEnv, pay, isBinary, err := s.ReceiveAny()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotEnv != env || !isBinary || !bytes.Equal(pay, binaryPayload) {
+		t.Errorf("unexpected binary message: %v %v %v", gotEnv, isBinary, pay)
+	}
+
+	gotEnv, pay, isBinary, err = s.ReceiveAny()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotEnv != env || isBinary || string(pay) != `{"type":"PING"}` {
+		t.Errorf("unexpected string message: %v %v %s", gotEnv, isBinary, pay)
+	}
+}
+
+func TestReceiveChunks(t *testing.T) {
+	var conn bytes.Buffer
+	chunks := []string{`{"type":`, `"MEDIA_`, `STATUS"}`}
+	for i, chunk := range chunks {
+		err := proto.WriteFrame(&conn, &proto.CastMessage{
+			ProtocolVersion: proto.CastMessage_CASTV2_1_2.Enum(),
+			SourceId:        gogoproto.String("receiver-0"),
+			DestinationId:   gogoproto.String("sender-0"),
+			Namespace:       gogoproto.String("urn:x-cast:com.google.cast.media"),
+			PayloadType:     proto.CastMessage_STRING.Enum(),
+			PayloadUtf8:     gogoproto.String(chunk),
+			Continued:       gogoproto.Bool(i < len(chunks)-1),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := gogoprotobuf.Serializer{Conn: &conn, Logger: log.NopLogger()}
+	_, pay, err := s.Receive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(pay) != `{"type":"MEDIA_STATUS"}` {
+		t.Errorf("the chunks should have been concatenated, got %s", pay)
+	}
+}
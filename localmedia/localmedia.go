@@ -0,0 +1,184 @@
+// Package localmedia serves local files over HTTP, so that a chromecast can play them
+package localmedia
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultPorts are tried first, so that a single firewall rule is enough
+// (a random port is used if they are all taken)
+var DefaultPorts = []int{8010, 8011, 8012, 8013, 8014, 8015, 8016, 8017, 8018, 8019}
+
+// contentTypes of the extensions which are often unknown to the mime package
+var contentTypes = map[string]string{
+	".mkv":  "video/x-matroska",
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".webm": "video/webm",
+	".mp3":  "audio/mpeg",
+	".m4a":  "audio/mp4",
+	".aac":  "audio/aac",
+	".flac": "audio/flac",
+	".ogg":  "audio/ogg",
+	".opus": "audio/ogg",
+	".wav":  "audio/wav",
+	".vtt":  "text/vtt",
+	".m3u8": "application/x-mpegurl",
+	".mpd":  "application/dash+xml",
+}
+
+// ContentType returns the content-type of the file, according to its extension
+func ContentType(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if t, ok := contentTypes[ext]; ok {
+		return t
+	}
+	if t := mime.TypeByExtension(ext); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// IsLocal returns true if the argument is an existing file (and not a URL)
+func IsLocal(arg string) bool {
+	f, err := os.Stat(arg)
+	return err == nil && !f.IsDir()
+}
+
+// Server of a media file (and of its subtitles)
+type Server struct {
+	// URL of the media file, reachable from the LAN
+	URL         string
+	ContentType string
+	// Transcoded is true if the media file is transcoded by ffmpeg (see ServeTranscoded)
+	Transcoded bool
+
+	base   string // like http://192.168.1.10:8010
+	server *http.Server
+
+	mu    sync.Mutex
+	files map[string]http.Handler // by escaped URI
+}
+
+// Serve the file in the background on the given ip (the outbound IP if nil)
+// and on the first available port (DefaultPorts if none are given)
+func Serve(path string, ip net.IP, ports ...int) (*Server, error) {
+	f, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if f.IsDir() {
+		return nil, fmt.Errorf("'%s' is a folder", path)
+	}
+	s, err := newServer(ip, ports)
+	if err != nil {
+		return nil, err
+	}
+	s.ContentType = ContentType(path)
+	s.URL = s.add(filepath.Base(path), fileHandler(path, s.ContentType))
+	return s, nil
+}
+
+// newServer starts serving (without any file)
+func newServer(ip net.IP, ports []int) (*Server, error) {
+	var err error
+	if ip == nil {
+		if ip, err = OutboundIP(); err != nil {
+			return nil, fmt.Errorf("could not find the local IP: %w", err)
+		}
+	}
+	if len(ports) == 0 {
+		ports = DefaultPorts
+	}
+	listener, err := Listen(ports)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		base:  "http://" + net.JoinHostPort(ip.String(), strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)),
+		files: make(map[string]http.Handler),
+	}
+	s.server = &http.Server{Handler: s}
+	go s.server.Serve(listener)
+	return s, nil
+}
+
+// add the handler of the file name and returns its URL
+func (s *Server) add(name string, h http.Handler) string {
+	uri := "/" + url.PathEscape(name)
+	s.mu.Lock()
+	s.files[uri] = h
+	s.mu.Unlock()
+	return s.base + uri
+}
+
+// ServeHTTP serves the added files only
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	h, ok := s.files["/"+url.PathEscape(strings.TrimPrefix(r.URL.Path, "/"))]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	// the receivers fetch some media (like HLS or subtitles) with XHR
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	h.ServeHTTP(w, r)
+}
+
+// Close stops serving the files
+func (s *Server) Close() error {
+	return s.server.Close()
+}
+
+// Listen on the first available port (or on a random port if none is available)
+func Listen(ports []int) (net.Listener, error) {
+	for _, port := range ports {
+		listener, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+		if err == nil {
+			return listener, nil
+		}
+	}
+	return net.Listen("tcp", ":0")
+}
+
+// fileHandler serves the file (with Range support)
+func fileHandler(path string, contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, err := os.Open(path)
+		if err != nil {
+			http.Error(w, "could not open the file", http.StatusInternalServerError)
+			return
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			http.Error(w, "could not stat the file", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+	}
+}
+
+// OutboundIP returns the preferred outbound IP of this machine
+// (from https://stackoverflow.com/a/37382208)
+func OutboundIP() (net.IP, error) {
+	conn, err := net.Dial("udp", "1.1.1.1:80")
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
@@ -0,0 +1,97 @@
+package localmedia_test
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/oliverpool/go-chromecast/localmedia"
+)
+
+func TestContentType(t *testing.T) {
+	cc := map[string]string{
+		"movie.mkv":    "video/x-matroska",
+		"Movie.MP4":    "video/mp4",
+		"song.flac":    "audio/flac",
+		"image.png":    "image/png",
+		"unknown.xyz1": "application/octet-stream",
+	}
+	for name, expected := range cc {
+		if got := localmedia.ContentType(name); got != expected {
+			t.Errorf("%s: got '%s' instead of '%s'", name, got, expected)
+		}
+	}
+}
+
+func TestServe(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localmedia")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "my movie.mp4")
+	if err = ioutil.WriteFile(path, []byte("0123456789"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if !localmedia.IsLocal(path) || localmedia.IsLocal(dir) || localmedia.IsLocal("http://example.com/movie.mp4") {
+		t.Error("only existing files should be local")
+	}
+
+	srv, err := localmedia.Serve(path, net.IPv4(127, 0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	if !strings.HasSuffix(srv.URL, "/my%20movie.mp4") || srv.ContentType != "video/mp4" {
+		t.Errorf("unexpected server: %+v", srv)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Range", "bytes=2-5")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent || string(b) != "2345" {
+		t.Errorf("unexpected range response: %s '%s'", resp.Status, b)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "video/mp4" {
+		t.Errorf("unexpected content-type: %s", ct)
+	}
+	if resp.Header.Get("Access-Control-Allow-Origin") != "*" {
+		t.Error("CORS should be allowed")
+	}
+
+	resp, err = http.Get(strings.TrimSuffix(srv.URL, "/my%20movie.mp4") + "/other.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("only the file should be served, got %s", resp.Status)
+	}
+}
+
+func TestListenFallback(t *testing.T) {
+	busy, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+	port := busy.Addr().(*net.TCPAddr).Port
+
+	l, err := localmedia.Listen([]int{port})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if l.Addr().(*net.TCPAddr).Port == port {
+		t.Error("a random port should be used when the given ones are taken")
+	}
+}
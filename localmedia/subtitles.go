@@ -0,0 +1,65 @@
+package localmedia
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// srtTimestamp like 00:01:02,345 (WebVTT uses a dot before the milliseconds)
+var srtTimestamp = regexp.MustCompile(`(\d+:\d{2}:\d{2}),(\d{3})`)
+
+// SRTToVTT converts SubRip subtitles to WebVTT (the only format supported by the default receiver)
+func SRTToVTT(srt []byte) []byte {
+	srt = bytes.TrimPrefix(srt, []byte("\xef\xbb\xbf")) // BOM
+	srt = bytes.Replace(srt, []byte("\r\n"), []byte("\n"), -1)
+
+	var vtt bytes.Buffer
+	vtt.WriteString("WEBVTT\n\n")
+	for _, line := range strings.Split(string(srt), "\n") {
+		if strings.Contains(line, "-->") {
+			line = srtTimestamp.ReplaceAllString(line, "$1.$2")
+		}
+		vtt.WriteString(line)
+		vtt.WriteByte('\n')
+	}
+	return vtt.Bytes()
+}
+
+// ServeSubtitles serves the subtitles file (converted on the fly if it is a .srt file)
+// and returns its URL
+func (s *Server) ServeSubtitles(path string) (string, error) {
+	f, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	name := filepath.Base(path)
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".vtt":
+		return s.add(name, fileHandler(path, "text/vtt")), nil
+	case ".srt":
+		name = strings.TrimSuffix(name, filepath.Ext(name)) + ".vtt"
+		return s.add(name, srtHandler(path, f.ModTime())), nil
+	default:
+		return "", fmt.Errorf("unsupported subtitles format '%s' (only .srt and .vtt are supported)", ext)
+	}
+}
+
+// srtHandler serves the SubRip file as WebVTT
+func srtHandler(path string, modTime time.Time) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		srt, err := ioutil.ReadFile(path)
+		if err != nil {
+			http.Error(w, "could not read the file", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/vtt; charset=utf-8")
+		http.ServeContent(w, r, filepath.Base(path), modTime, bytes.NewReader(SRTToVTT(srt)))
+	}
+}
@@ -0,0 +1,71 @@
+package localmedia_test
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/oliverpool/go-chromecast/localmedia"
+)
+
+const srt = "\xef\xbb\xbf1\r\n00:00:01,000 --> 00:00:02,500\r\nHello, world\r\n\r\n2\r\n00:01:02,345 --> 00:01:04,000\r\nBye\r\n"
+
+func TestSRTToVTT(t *testing.T) {
+	expected := "WEBVTT\n\n1\n00:00:01.000 --> 00:00:02.500\nHello, world\n\n2\n00:01:02.345 --> 00:01:04.000\nBye\n\n"
+	if got := string(localmedia.SRTToVTT([]byte(srt))); got != expected {
+		t.Errorf("unexpected WebVTT:\n%q\nexpected:\n%q", got, expected)
+	}
+}
+
+func TestServeSubtitles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localmedia")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	movie := filepath.Join(dir, "movie.mp4")
+	subs := filepath.Join(dir, "movie.en.srt")
+	if err = ioutil.WriteFile(movie, []byte("0123456789"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(subs, []byte(srt), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := localmedia.Serve(movie, net.IPv4(127, 0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	if _, err = srv.ServeSubtitles(movie); err == nil {
+		t.Error("only .srt and .vtt subtitles should be supported")
+	}
+	u, err := srv.ServeSubtitles(subs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(u, "/movie.en.vtt") {
+		t.Errorf("the subtitles should be served as WebVTT: %s", u)
+	}
+
+	resp, err := http.Get(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.HasPrefix(string(b), "WEBVTT\n\n1\n00:00:01.000 --> 00:00:02.500") {
+		t.Errorf("unexpected subtitles: %q", b)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/vtt") {
+		t.Errorf("unexpected content-type: %s", ct)
+	}
+	if resp.Header.Get("Access-Control-Allow-Origin") != "*" {
+		t.Error("CORS should be allowed")
+	}
+}
@@ -0,0 +1,181 @@
+package localmedia
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Commands of ffmpeg (looked up in the PATH)
+var (
+	FFmpeg  = "ffmpeg"
+	FFprobe = "ffprobe"
+)
+
+// TranscodeMode tells when a file must be transcoded before being served
+type TranscodeMode string
+
+// Transcode modes
+const (
+	// TranscodeAuto transcodes the files with unsupported codecs or containers (if ffprobe is installed)
+	TranscodeAuto   TranscodeMode = "auto"
+	TranscodeAlways TranscodeMode = "always"
+	TranscodeNever  TranscodeMode = "never"
+)
+
+// ParseTranscodeMode returns the mode (auto, always or never)
+func ParseTranscodeMode(s string) (TranscodeMode, error) {
+	switch m := TranscodeMode(s); m {
+	case TranscodeAuto, TranscodeAlways, TranscodeNever:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown transcode mode '%s' (auto, always or never)", s)
+	}
+}
+
+// codecs and containers (extensions) supported by the default receiver
+var (
+	supportedVideoCodecs = map[string]bool{"h264": true, "vp8": true, "vp9": true}
+	supportedAudioCodecs = map[string]bool{"aac": true, "mp3": true, "opus": true, "vorbis": true, "flac": true}
+	supportedContainers  = map[string]bool{".mp4": true, ".m4v": true, ".m4a": true, ".webm": true, ".mp3": true, ".flac": true, ".ogg": true, ".opus": true, ".aac": true, ".wav": true}
+)
+
+// MediaInfo describes the first streams of a file
+type MediaInfo struct {
+	Container  string // extension of the file, like .mkv
+	VideoCodec string // like h264 (empty for an audio file)
+	AudioCodec string // like aac
+}
+
+// Supported returns true if the default receiver can play the file as is
+func (m MediaInfo) Supported() bool {
+	if !supportedContainers[m.Container] {
+		return false
+	}
+	if m.VideoCodec != "" && !supportedVideoCodecs[m.VideoCodec] {
+		return false
+	}
+	return m.AudioCodec == "" || supportedAudioCodecs[m.AudioCodec]
+}
+
+// ProbeMedia runs ffprobe to get the codecs of the file
+func ProbeMedia(path string) (MediaInfo, error) {
+	ffprobe, err := exec.LookPath(FFprobe)
+	if err != nil {
+		return MediaInfo{}, fmt.Errorf("%s is not installed: %v", FFprobe, err)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(ffprobe, "-v", "error", "-show_entries", "stream=codec_type,codec_name", "-of", "json", path)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		return MediaInfo{}, fmt.Errorf("%s could not probe '%s': %v (%s)", FFprobe, path, err, strings.TrimSpace(stderr.String()))
+	}
+	m, err := parseProbe(&stdout)
+	m.Container = strings.ToLower(filepath.Ext(path))
+	return m, err
+}
+
+func parseProbe(r io.Reader) (MediaInfo, error) {
+	var probe struct {
+		Streams []struct {
+			CodecType string `json:"codec_type"`
+			CodecName string `json:"codec_name"`
+		} `json:"streams"`
+	}
+	if err := json.NewDecoder(r).Decode(&probe); err != nil {
+		return MediaInfo{}, fmt.Errorf("could not parse the output of %s: %v", FFprobe, err)
+	}
+	var m MediaInfo
+	for _, s := range probe.Streams {
+		switch {
+		case s.CodecType == "video" && m.VideoCodec == "" && s.CodecName != "mjpeg" && s.CodecName != "png":
+			// the cover of an audio file is a video stream
+			m.VideoCodec = s.CodecName
+		case s.CodecType == "audio" && m.AudioCodec == "":
+			m.AudioCodec = s.CodecName
+		}
+	}
+	return m, nil
+}
+
+// TranscodeArgs returns the arguments of ffmpeg to stream the file as a fragmented MP4
+// (with H.264 and AAC): the supported streams are copied, the other ones are transcoded.
+func TranscodeArgs(path string, m MediaInfo) []string {
+	args := []string{"-v", "error", "-i", path}
+	if m.VideoCodec == "" {
+		args = append(args, "-vn")
+	} else {
+		args = append(args, "-map", "0:v:0")
+		if m.VideoCodec == "h264" {
+			args = append(args, "-c:v", "copy")
+		} else {
+			args = append(args, "-c:v", "libx264", "-preset", "veryfast", "-pix_fmt", "yuv420p")
+		}
+	}
+	if m.AudioCodec != "" {
+		args = append(args, "-map", "0:a:0")
+		if m.AudioCodec == "aac" || m.AudioCodec == "mp3" {
+			args = append(args, "-c:a", "copy")
+		} else {
+			args = append(args, "-c:a", "aac", "-ac", "2")
+		}
+	}
+	return append(args, "-movflags", "frag_keyframe+empty_moov+default_base_moof", "-f", "mp4", "pipe:1")
+}
+
+// ServeTranscoded serves the file like Serve, transcoded by ffmpeg according to the mode.
+// A transcoded file is streamed: the receiver can not seek inside it.
+func ServeTranscoded(path string, mode TranscodeMode, ip net.IP, ports ...int) (*Server, error) {
+	if mode == TranscodeNever {
+		return Serve(path, ip, ports...)
+	}
+	m, err := ProbeMedia(path)
+	if err != nil && mode == TranscodeAuto {
+		// without ffprobe, the file can only be served as is
+		return Serve(path, ip, ports...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if mode == TranscodeAuto && m.Supported() {
+		return Serve(path, ip, ports...)
+	}
+	ffmpeg, err := exec.LookPath(FFmpeg)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not installed: %v", FFmpeg, err)
+	}
+
+	s, err := newServer(ip, ports)
+	if err != nil {
+		return nil, err
+	}
+	s.ContentType = "video/mp4"
+	if m.VideoCodec == "" {
+		s.ContentType = "audio/mp4"
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + ".mp4"
+	s.URL = s.add(name, transcodeHandler(ffmpeg, TranscodeArgs(path, m), s.ContentType))
+	s.Transcoded = true
+	return s, nil
+}
+
+// transcodeHandler streams the output of ffmpeg (stopped when the request is done)
+func transcodeHandler(ffmpeg string, args []string, contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		if r.Method == http.MethodHead {
+			return
+		}
+		cmd := exec.CommandContext(r.Context(), ffmpeg, args...)
+		cmd.Stdout = w
+		// the headers are already sent: on error, the stream is just cut
+		cmd.Run()
+	}
+}
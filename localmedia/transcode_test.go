@@ -0,0 +1,84 @@
+package localmedia_test
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/oliverpool/go-chromecast/localmedia"
+)
+
+func TestParseTranscodeMode(t *testing.T) {
+	for _, s := range []string{"auto", "always", "never"} {
+		if m, err := localmedia.ParseTranscodeMode(s); err != nil || string(m) != s {
+			t.Errorf("%s: unexpected mode %s (%v)", s, m, err)
+		}
+	}
+	if _, err := localmedia.ParseTranscodeMode("sometimes"); err == nil {
+		t.Error("an unknown mode should be rejected")
+	}
+}
+
+func TestMediaInfoSupported(t *testing.T) {
+	cc := []struct {
+		info      localmedia.MediaInfo
+		supported bool
+	}{
+		{localmedia.MediaInfo{Container: ".mp4", VideoCodec: "h264", AudioCodec: "aac"}, true},
+		{localmedia.MediaInfo{Container: ".mp3", AudioCodec: "mp3"}, true},
+		{localmedia.MediaInfo{Container: ".mkv", VideoCodec: "h264", AudioCodec: "aac"}, false},
+		{localmedia.MediaInfo{Container: ".mp4", VideoCodec: "hevc", AudioCodec: "aac"}, false},
+		{localmedia.MediaInfo{Container: ".mp4", VideoCodec: "h264", AudioCodec: "dts"}, false},
+	}
+	for _, c := range cc {
+		if got := c.info.Supported(); got != c.supported {
+			t.Errorf("%+v: got supported=%v", c.info, got)
+		}
+	}
+}
+
+func TestTranscodeArgs(t *testing.T) {
+	remux := strings.Join(localmedia.TranscodeArgs("in.mkv", localmedia.MediaInfo{Container: ".mkv", VideoCodec: "h264", AudioCodec: "aac"}), " ")
+	if !strings.Contains(remux, "-c:v copy") || !strings.Contains(remux, "-c:a copy") {
+		t.Errorf("the supported streams should be copied: %s", remux)
+	}
+	transcode := strings.Join(localmedia.TranscodeArgs("in.mkv", localmedia.MediaInfo{Container: ".mkv", VideoCodec: "hevc", AudioCodec: "dts"}), " ")
+	if !strings.Contains(transcode, "-c:v libx264") || !strings.Contains(transcode, "-c:a aac") {
+		t.Errorf("the unsupported streams should be transcoded: %s", transcode)
+	}
+	audio := strings.Join(localmedia.TranscodeArgs("in.wma", localmedia.MediaInfo{Container: ".wma", AudioCodec: "wmav2"}), " ")
+	if !strings.Contains(audio, "-vn") || !strings.HasSuffix(audio, "-f mp4 pipe:1") {
+		t.Errorf("unexpected arguments for an audio file: %s", audio)
+	}
+}
+
+func TestServeTranscodedWithoutFFprobe(t *testing.T) {
+	defer func(ffprobe string) { localmedia.FFprobe = ffprobe }(localmedia.FFprobe)
+	localmedia.FFprobe = "ffprobe-not-installed"
+
+	dir, err := ioutil.TempDir("", "localmedia")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "movie.mkv")
+	if err = ioutil.WriteFile(path, []byte("0123456789"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := localmedia.ServeTranscoded(path, localmedia.TranscodeAuto, net.IPv4(127, 0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	if srv.Transcoded || !strings.HasSuffix(srv.URL, "/movie.mkv") {
+		t.Errorf("the file should be served as is without ffprobe: %+v", srv)
+	}
+
+	if _, err = localmedia.ServeTranscoded(path, localmedia.TranscodeAlways, net.IPv4(127, 0, 0, 1)); err == nil {
+		t.Error("the file can not be transcoded without ffprobe")
+	}
+}
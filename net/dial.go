@@ -7,12 +7,59 @@ import (
 	"context"
 )
 
+// ContextDialer dials the raw connections
+// It is implemented by *net.Dialer and by the SOCKS5 dialer of golang.org/x/net/proxy
+// (to go through a proxy, a VPN netstack or to bind a local address)
+type ContextDialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// Dial connects to the chromecast, accepting its (self-signed) certificate blindly
 func Dial(ctx context.Context, addr string) (*tls.Conn, error) {
-	deadline, _ := ctx.Deadline()
-	dialer := &net.Dialer{
-		Deadline: deadline,
+	return DialTLS(ctx, addr, nil)
+}
+
+// DialTLS connects to the chromecast with the given TLS configuration
+// (see Dial if config is nil and Pinned for certificate pinning)
+func DialTLS(ctx context.Context, addr string, config *tls.Config) (*tls.Conn, error) {
+	return DialWith(ctx, nil, addr, config)
+}
+
+// DialWith connects to the chromecast through the dialer (direct connection if nil),
+// with the given TLS configuration (see DialTLS)
+func DialWith(ctx context.Context, dialer ContextDialer, addr string, config *tls.Config) (*tls.Conn, error) {
+	if config == nil {
+		config = &tls.Config{
+			InsecureSkipVerify: true,
+		}
+	}
+	if dialer == nil {
+		dialer = &net.Dialer{}
+	}
+	raw, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// the handshake is bound to the context: the connection is closed as soon as ctx is done
+	// (so a failed handshake is caused by the context if it is done)
+	handshakeDone := make(chan struct{})
+	defer close(handshakeDone)
+	go func() {
+		select {
+		case <-ctx.Done():
+			raw.Close()
+		case <-handshakeDone:
+		}
+	}()
+
+	conn := tls.Client(raw, config)
+	if err = conn.Handshake(); err != nil {
+		raw.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
 	}
-	return tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{
-		InsecureSkipVerify: true,
-	})
+	return conn, nil
 }
@@ -0,0 +1,65 @@
+package net_test
+
+import (
+	"context"
+	gonet "net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/oliverpool/go-chromecast/net"
+)
+
+// countingDialer counts the dialed connections
+type countingDialer struct {
+	gonet.Dialer
+	dialed []string
+}
+
+func (d *countingDialer) DialContext(ctx context.Context, network, addr string) (gonet.Conn, error) {
+	d.dialed = append(d.dialed, addr)
+	return d.Dialer.DialContext(ctx, network, addr)
+}
+
+func TestDialWith(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	addr := server.Listener.Addr().String()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	dialer := &countingDialer{}
+	conn, err := net.DialWith(ctx, dialer, addr, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conn.Close()
+
+	if len(dialer.dialed) != 1 || dialer.dialed[0] != addr {
+		t.Errorf("the connection should have gone through the dialer, got %v", dialer.dialed)
+	}
+}
+
+func TestDialWithCancelledHandshake(t *testing.T) {
+	// the listener accepts the connection, but never answers the handshake
+	l, err := gonet.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := net.DialWith(ctx, nil, l.Addr().String(), nil); err != context.DeadlineExceeded {
+		t.Errorf("the context error was expected, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package net
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// PinStore stores the certificate fingerprints, by device ID
+type PinStore interface {
+	Load() (map[string]string, error)
+	Save(map[string]string) error
+}
+
+// FilePinStore stores the fingerprints as JSON inside a file
+type FilePinStore struct {
+	Path string
+}
+
+// Load reads the fingerprints (a missing file is not an error)
+func (f FilePinStore) Load() (map[string]string, error) {
+	b, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pins map[string]string
+	err = json.Unmarshal(b, &pins)
+	return pins, err
+}
+
+// Save writes the fingerprints (creating the parent directories if needed)
+func (f FilePinStore) Save(pins map[string]string) error {
+	b, err := json.MarshalIndent(pins, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(f.Path, b, 0600)
+}
+
+// CertificateMismatchError is returned when the certificate of a device does not match the pinned one
+type CertificateMismatchError struct {
+	ID       string
+	Expected string
+	Got      string
+}
+
+func (e CertificateMismatchError) Error() string {
+	return fmt.Sprintf("certificate of %s does not match the pinned one (expected %s, got %s)", e.ID, e.Expected, e.Got)
+}
+
+// Fingerprint returns the hex-encoded SHA-256 of a DER certificate
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
+
+// pinMu prevents concurrent Load/Save of the stores
+var pinMu sync.Mutex
+
+// Pinned returns a TLS configuration which pins the certificate of the device with the given ID
+// The first certificate seen is trusted and saved into the store (trust on first use),
+// a different certificate afterwards fails the handshake with a CertificateMismatchError
+func Pinned(store PinStore, id string) *tls.Config {
+	return &tls.Config{
+		// the chromecast certificate is self-signed: it is verified below
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return fmt.Errorf("no certificate presented by %s", id)
+			}
+			got := Fingerprint(rawCerts[0])
+
+			pinMu.Lock()
+			defer pinMu.Unlock()
+			pins, err := store.Load()
+			if err != nil {
+				return fmt.Errorf("could not load the pinned certificates: %w", err)
+			}
+			if expected, ok := pins[id]; ok {
+				if expected != got {
+					return CertificateMismatchError{ID: id, Expected: expected, Got: got}
+				}
+				return nil
+			}
+			if pins == nil {
+				pins = make(map[string]string, 1)
+			}
+			pins[id] = got
+			if err = store.Save(pins); err != nil {
+				return fmt.Errorf("could not save the pinned certificate: %w", err)
+			}
+			return nil
+		},
+	}
+}
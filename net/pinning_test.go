@@ -0,0 +1,59 @@
+package net_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/oliverpool/go-chromecast/net"
+)
+
+type memoryPinStore map[string]string
+
+func (m memoryPinStore) Load() (map[string]string, error) {
+	return m, nil
+}
+
+func (m memoryPinStore) Save(pins map[string]string) error {
+	for id, pin := range pins {
+		m[id] = pin
+	}
+	return nil
+}
+
+func TestPinned(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	addr := server.Listener.Addr().String()
+	store := memoryPinStore{}
+
+	// trust on first use
+	conn, err := net.DialTLS(context.Background(), addr, net.Pinned(store, "a1b2"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conn.Close()
+	if store["a1b2"] != net.Fingerprint(server.Certificate().Raw) {
+		t.Fatalf("the certificate should have been pinned, got %v", store)
+	}
+
+	// same certificate
+	conn, err = net.DialTLS(context.Background(), addr, net.Pinned(store, "a1b2"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conn.Close()
+
+	// other certificate
+	store["a1b2"] = "0000"
+	_, err = net.DialTLS(context.Background(), addr, net.Pinned(store, "a1b2"))
+	var mismatch net.CertificateMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("a CertificateMismatchError was expected, got %v", err)
+	}
+	if mismatch.Expected != "0000" {
+		t.Errorf("unexpected mismatch: %+v", mismatch)
+	}
+}
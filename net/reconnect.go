@@ -0,0 +1,215 @@
+package net
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// Backoff computes the delays between consecutive reconnection attempts
+// The delay starts at Min (500ms if 0) and is doubled after each failure, up to Max (30s if 0)
+type Backoff struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// Delay returns the delay to wait before the given attempt (starting at 0)
+func (b Backoff) Delay(attempt int) time.Duration {
+	min, max := b.Min, b.Max
+	if min <= 0 {
+		min = 500 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 30 * time.Second
+	}
+	delay := min
+	for i := 0; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+// ErrClosed is returned when reading or writing on a closed ReconnectingConn
+const ErrClosed = chromecast.ErrConnectionClosed
+
+// ReconnectingConn is a connection which redials (with exponential backoff) when a read or a write fails
+// The failing read or write returns its error (the partial message is lost),
+// the following ones are made on the new connection (they block during the reconnection)
+type ReconnectingConn struct {
+	// Dial returns a new connection (called for every reconnection attempt)
+	Dial func(ctx context.Context) (io.ReadWriteCloser, error)
+	// Backoff between the reconnection attempts
+	Backoff Backoff
+	// ReadTimeout closes the connection if nothing was read during this period (disabled if 0)
+	// It allows to detect a connection which went away silently (only applies to net.Conn)
+	ReadTimeout time.Duration
+	// WriteTimeout fails the writes which take longer (disabled if 0)
+	// A failed write triggers a reconnection (only applies to net.Conn)
+	WriteTimeout time.Duration
+	// OnDisconnect is called when the connection dropped, before reconnecting (can be nil)
+	OnDisconnect func()
+	// OnReconnect is called after a successful reconnection (can be nil)
+	OnReconnect func()
+	// OnError is called after a failed reconnection attempt (can be nil)
+	OnError func(err error)
+
+	mu         sync.Mutex
+	conn       io.ReadWriteCloser
+	generation uint64
+	initOnce   sync.Once
+	closed     chan struct{}
+	closeOnce  sync.Once
+}
+
+// Connect dials the first connection
+func (c *ReconnectingConn) Connect(ctx context.Context) error {
+	conn, err := c.Dial(ctx)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *ReconnectingConn) current() (io.ReadWriteCloser, uint64, error) {
+	select {
+	case <-c.done():
+		return nil, 0, ErrClosed
+	default:
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil, 0, ErrClosed
+	}
+	return c.conn, c.generation, nil
+}
+
+func (c *ReconnectingConn) done() <-chan struct{} {
+	c.initOnce.Do(func() {
+		c.closed = make(chan struct{})
+	})
+	return c.closed
+}
+
+// Read reads from the current connection (and reconnects on failure)
+func (c *ReconnectingConn) Read(p []byte) (int, error) {
+	conn, generation, err := c.current()
+	if err != nil {
+		return 0, err
+	}
+	if nc, ok := conn.(net.Conn); ok && c.ReadTimeout > 0 {
+		nc.SetReadDeadline(time.Now().Add(c.ReadTimeout))
+	}
+	n, err := conn.Read(p)
+	if err != nil {
+		c.reconnect(generation)
+	}
+	return n, err
+}
+
+// Write writes to the current connection (and reconnects on failure)
+func (c *ReconnectingConn) Write(p []byte) (int, error) {
+	conn, generation, err := c.current()
+	if err != nil {
+		return 0, err
+	}
+	if nc, ok := conn.(net.Conn); ok && c.WriteTimeout > 0 {
+		nc.SetWriteDeadline(time.Now().Add(c.WriteTimeout))
+	}
+	n, err := conn.Write(p)
+	if err != nil {
+		c.reconnect(generation)
+	}
+	return n, err
+}
+
+// reconnect redials until it succeeds or the connection is closed
+// It does nothing if the connection of the given generation has already been replaced
+func (c *ReconnectingConn) reconnect(generation uint64) {
+	c.mu.Lock()
+	if c.generation != generation || c.conn == nil {
+		c.mu.Unlock()
+		return
+	}
+	c.conn.Close()
+	if c.OnDisconnect != nil {
+		c.OnDisconnect()
+	}
+
+	done := c.done()
+	for attempt := 0; ; attempt++ {
+		select {
+		case <-done:
+			c.mu.Unlock()
+			return
+		case <-time.After(c.Backoff.Delay(attempt)):
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-done:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		conn, err := c.Dial(ctx)
+		cancel()
+		if err == nil {
+			c.conn = conn
+			c.generation++
+			break
+		}
+		if c.OnError != nil {
+			c.OnError(err)
+		}
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-done:
+		// closed during the last dial
+		c.Close()
+		return
+	default:
+	}
+	if c.OnReconnect != nil {
+		c.OnReconnect()
+	}
+}
+
+// Drop closes the current connection, which triggers a reconnection
+func (c *ReconnectingConn) Drop() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// Close closes the current connection and stops reconnecting
+func (c *ReconnectingConn) Close() error {
+	c.done()
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
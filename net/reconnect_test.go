@@ -0,0 +1,75 @@
+package net_test
+
+import (
+	"context"
+	"io"
+	gonet "net"
+	"testing"
+	"time"
+
+	"github.com/oliverpool/go-chromecast/net"
+)
+
+func TestBackoff(t *testing.T) {
+	b := net.Backoff{Min: time.Second, Max: 5 * time.Second}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for attempt, e := range expected {
+		if d := b.Delay(attempt); d != e {
+			t.Errorf("attempt %d: expected %s, got %s", attempt, e, d)
+		}
+	}
+}
+
+func TestReconnectingConn(t *testing.T) {
+	servers := make(chan gonet.Conn, 3)
+	reconnected := make(chan struct{}, 3)
+	conn := &net.ReconnectingConn{
+		Dial: func(ctx context.Context) (io.ReadWriteCloser, error) {
+			client, server := gonet.Pipe()
+			servers <- server
+			return client, nil
+		},
+		Backoff: net.Backoff{Min: time.Millisecond},
+		OnReconnect: func() {
+			reconnected <- struct{}{}
+		},
+	}
+	if err := conn.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	first := <-servers
+
+	// connection drop
+	first.Close()
+	buf := make([]byte, 5)
+	if _, err := conn.Read(buf); err == nil {
+		t.Fatal("the read on the dropped connection should fail")
+	}
+	select {
+	case <-reconnected:
+	case <-time.After(time.Second):
+		t.Fatal("OnReconnect should have been called")
+	}
+
+	// transparent resume
+	second := <-servers
+	go second.Write([]byte("hello"))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(buf[:n]) != "hello" {
+		t.Errorf("unexpected read: %q", buf[:n])
+	}
+
+	// no reconnection after Close
+	conn.Close()
+	if _, err := conn.Read(buf); err != net.ErrClosed {
+		t.Errorf("ErrClosed expected, got %v", err)
+	}
+	select {
+	case <-servers:
+		t.Error("no reconnection should happen after Close")
+	default:
+	}
+}
@@ -0,0 +1,50 @@
+// Package proto exposes the protobuf types of the cast channel
+// (generated from the cast_channel.proto of Chromium, see gogoprotobuf/pb),
+// to construct the frames directly.
+package proto
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	gogoproto "github.com/gogo/protobuf/proto"
+	"github.com/oliverpool/go-chromecast/gogoprotobuf/pb"
+)
+
+type (
+	CastMessage                 = pb.CastMessage
+	CastMessage_ProtocolVersion = pb.CastMessage_ProtocolVersion
+	CastMessage_PayloadType     = pb.CastMessage_PayloadType
+	AuthChallenge               = pb.AuthChallenge
+	AuthResponse                = pb.AuthResponse
+	AuthError                   = pb.AuthError
+	AuthError_ErrorType         = pb.AuthError_ErrorType
+	DeviceAuthMessage           = pb.DeviceAuthMessage
+)
+
+const (
+	CastMessage_CASTV2_1_0 = pb.CastMessage_CASTV2_1_0
+	CastMessage_CASTV2_1_1 = pb.CastMessage_CASTV2_1_1
+	CastMessage_CASTV2_1_2 = pb.CastMessage_CASTV2_1_2
+	CastMessage_CASTV2_1_3 = pb.CastMessage_CASTV2_1_3
+
+	CastMessage_STRING = pb.CastMessage_STRING
+	CastMessage_BINARY = pb.CastMessage_BINARY
+
+	AuthError_INTERNAL_ERROR = pb.AuthError_INTERNAL_ERROR
+	AuthError_NO_TLS         = pb.AuthError_NO_TLS
+)
+
+// WriteFrame writes the message, prefixed by its length (big-endian uint32)
+func WriteFrame(w io.Writer, message *CastMessage) error {
+	data, err := gogoproto.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %s", err)
+	}
+	frame := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	copy(frame[4:], data)
+	_, err = w.Write(frame)
+	return err
+}
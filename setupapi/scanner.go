@@ -0,0 +1,70 @@
+package setupapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// Scanner wraps a Scanner to enrich the devices found with their eureka_info
+type Scanner struct {
+	Scanner chromecast.Scanner
+	// HTTPClient used for the requests (a client with a 2s timeout if nil)
+	HTTPClient *http.Client
+	Logger     chromecast.Logger
+}
+
+// Scan forwards the devices of the wrapped Scanner, after having set their Info
+// (devices whose info could not be fetched are forwarded as-is)
+func (s Scanner) Scan(ctx context.Context, results chan<- *chromecast.Device) error {
+	scanned := make(chan *chromecast.Device, 5)
+	if err := s.Scanner.Scan(ctx, scanned); err != nil {
+		return err
+	}
+
+	httpClient := s.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 2 * time.Second}
+	}
+
+	go func() {
+		defer close(results)
+		infos := make(map[string]*chromecast.DeviceInfo)
+		for device := range scanned {
+			if device != nil && device.Info == nil {
+				info, ok := infos[device.ID()]
+				if !ok {
+					c := New(device)
+					c.HTTPClient = httpClient
+					var err error
+					info, err = c.EurekaInfo(ctx)
+					if err != nil {
+						s.log("step", "eureka_info", "addr", device.Addr(), "err", err)
+					} else {
+						infos[device.ID()] = info
+					}
+				}
+				enriched := *device
+				enriched.Info = info
+				device = &enriched
+			}
+			select {
+			case results <- device:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return nil
+}
+
+func (s Scanner) log(keyvals ...interface{}) {
+	if s.Logger == nil {
+		return
+	}
+	vals := make([]interface{}, 0, len(keyvals)+2)
+	vals = append(vals, "package", "setupapi")
+	vals = append(vals, keyvals...)
+	s.Logger.Log(vals...)
+}
@@ -0,0 +1,182 @@
+// Package setupapi queries the HTTP setup API of the devices (http://<ip>:8008/setup/)
+package setupapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+)
+
+// Port of the setup API
+const Port = 8008
+
+// SecurePort of the setup API (HTTPS, required by the recent firmwares to change the settings)
+const SecurePort = 8443
+
+// Client of the setup API of a device
+type Client struct {
+	// Addr of the setup API (like "192.168.1.2:8008")
+	Addr string
+	// HTTPClient used for the requests (http.DefaultClient if nil,
+	// or a client accepting the self-signed certificate if Secure)
+	HTTPClient *http.Client
+	// Secure uses HTTPS (see NewSecure)
+	Secure bool
+	// Token is sent as cast-local-authorization-token (if not empty)
+	Token string
+}
+
+// New returns a client for the setup API of the device
+func New(d *chromecast.Device) Client {
+	return Client{
+		Addr: net.JoinHostPort(host(d), strconv.Itoa(Port)),
+	}
+}
+
+// NewSecure returns a client for the HTTPS setup API of the device, with the local authorization token
+func NewSecure(d *chromecast.Device, token string) Client {
+	return Client{
+		Addr:   net.JoinHostPort(host(d), strconv.Itoa(SecurePort)),
+		Secure: true,
+		Token:  token,
+	}
+}
+
+func host(d *chromecast.Device) string {
+	host := d.IP.String()
+	if d.Zone != "" {
+		// the zone must be escaped inside an URL
+		host += "%25" + d.Zone
+	}
+	return host
+}
+
+// EurekaInfo fetches the metadata of the device
+func (c Client) EurekaInfo(ctx context.Context) (*chromecast.DeviceInfo, error) {
+	var info chromecast.DeviceInfo
+	if err := c.get(ctx, "eureka_info", &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Settings of the device which can be changed
+type Settings struct {
+	Name     string `json:"name"`
+	Locale   string `json:"locale"`
+	Timezone string `json:"timezone"`
+}
+
+// Settings fetches the name, locale and timezone of the device
+func (c Client) Settings(ctx context.Context) (*Settings, error) {
+	var settings Settings
+	if err := c.get(ctx, "eureka_info?params=name,locale,timezone", &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// SetName renames the device
+func (c Client) SetName(ctx context.Context, name string) error {
+	return c.post(ctx, "set_eureka_info", map[string]string{"name": name}, nil)
+}
+
+// SetLocale changes the locale of the device (like "en-US")
+func (c Client) SetLocale(ctx context.Context, locale string) error {
+	return c.post(ctx, "set_eureka_info", map[string]string{"locale": locale}, nil)
+}
+
+// SetTimezone changes the timezone of the device (like "Europe/Paris")
+func (c Client) SetTimezone(ctx context.Context, timezone string) error {
+	return c.post(ctx, "set_eureka_info", map[string]string{"timezone": timezone}, nil)
+}
+
+// Reboot restarts the device
+func (c Client) Reboot(ctx context.Context) error {
+	return c.post(ctx, "reboot", map[string]string{"params": "now"}, nil)
+}
+
+// FactoryReset erases all the settings of the device (it must be set up again)
+func (c Client) FactoryReset(ctx context.Context) error {
+	return c.post(ctx, "reboot", map[string]string{"params": "fdr"}, nil)
+}
+
+// Reboot restarts the device (see Client.Reboot)
+func Reboot(ctx context.Context, d *chromecast.Device) error {
+	return New(d).Reboot(ctx)
+}
+
+func (c Client) get(ctx context.Context, path string, v interface{}) error {
+	return c.do(ctx, "GET", path, nil, v)
+}
+
+// post sends body as JSON (v can be nil to ignore the response)
+func (c Client) post(ctx context.Context, path string, body interface{}, v interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("could not encode the body: %w", err)
+	}
+	return c.do(ctx, "POST", path, b, v)
+}
+
+func (c Client) do(ctx context.Context, method, path string, body []byte, v interface{}) error {
+	url := c.url(path)
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not prepare request '%s': %w", url, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("cast-local-authorization-token", c.Token)
+	}
+	resp, err := c.httpClient().Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("could not fetch '%s': %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status for '%s': %s", url, resp.Status)
+	}
+	if v == nil {
+		return nil
+	}
+	if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("could not decode '%s': %w", url, err)
+	}
+	return nil
+}
+
+func (c Client) url(path string) string {
+	scheme := "http://"
+	if c.Secure {
+		scheme = "https://"
+	}
+	return scheme + c.Addr + "/setup/" + strings.TrimPrefix(path, "/")
+}
+
+// insecureClient accepts the self-signed certificate of the devices
+var insecureClient = &http.Client{
+	Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	},
+}
+
+func (c Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	if c.Secure {
+		return insecureClient
+	}
+	return http.DefaultClient
+}
@@ -0,0 +1,108 @@
+package setupapi_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	chromecast "github.com/oliverpool/go-chromecast"
+	"github.com/oliverpool/go-chromecast/setupapi"
+)
+
+// Ensure interface is satisfied
+var _ chromecast.Scanner = setupapi.Scanner{}
+
+func TestEurekaInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/setup/eureka_info" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"build_version":"175244","cast_build_revision":"1.44.175244","connected":true,"ip_address":"192.168.1.2","locale":"fr","mac_address":"54:60:09:00:00:00","name":"Living Room","ssid":"home","uptime":3600.5}`))
+	}))
+	defer server.Close()
+
+	client := setupapi.Client{Addr: strings.TrimPrefix(server.URL, "http://")}
+	info, err := client.EurekaInfo(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Name != "Living Room" || info.SSID != "home" || info.CastBuildRevision != "1.44.175244" || info.Uptime != 3600.5 {
+		t.Errorf("unexpected info: %+v", info)
+	}
+}
+
+func TestNewAddr(t *testing.T) {
+	d := &chromecast.Device{
+		IP:   []byte{0xfe, 0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
+		Zone: "eth0",
+	}
+	if got := setupapi.New(d).Addr; got != "[fe80::1%25eth0]:8008" {
+		t.Errorf("unexpected addr: %s", got)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	var posted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/setup/eureka_info":
+			w.Write([]byte(`{"name":"Living Room","locale":"fr","timezone":"Europe/Paris"}`))
+		case "/setup/set_eureka_info":
+			body, _ := ioutil.ReadAll(r.Body)
+			posted = append(posted, r.Header.Get("cast-local-authorization-token")+" "+string(body))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := setupapi.Client{Addr: strings.TrimPrefix(server.URL, "http://"), Token: "secret"}
+	settings, err := client.Settings(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.Name != "Living Room" || settings.Locale != "fr" || settings.Timezone != "Europe/Paris" {
+		t.Errorf("unexpected settings: %+v", settings)
+	}
+
+	if err = client.SetName(context.Background(), "Kitchen"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = client.SetTimezone(context.Background(), "UTC"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(posted) != 2 || posted[0] != `secret {"name":"Kitchen"}` || posted[1] != `secret {"timezone":"UTC"}` {
+		t.Errorf("unexpected requests: %q", posted)
+	}
+}
+
+func TestReboot(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/setup/reboot" || r.Method != "POST" {
+			http.NotFound(w, r)
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer server.Close()
+
+	client := setupapi.Client{Addr: strings.TrimPrefix(server.URL, "http://")}
+	if err := client.Reboot(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body != `{"params":"now"}` {
+		t.Errorf("unexpected body: %s", body)
+	}
+	if err := client.FactoryReset(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body != `{"params":"fdr"}` {
+		t.Errorf("unexpected body: %s", body)
+	}
+}
@@ -0,0 +1,37 @@
+package chromecast
+
+// ConnectionState is the state of the connection to the chromecast
+type ConnectionState int
+
+// Connection states
+const (
+	// Connecting: the connection has not been established yet
+	Connecting ConnectionState = iota
+	// Connected: the messages can be exchanged
+	Connected
+	// Reconnecting: the connection dropped and is being reestablished
+	Reconnecting
+	// Closed: the connection has been closed (final state)
+	Closed
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case Connecting:
+		return "connecting"
+	case Connected:
+		return "connected"
+	case Reconnecting:
+		return "reconnecting"
+	case Closed:
+		return "closed"
+	}
+	return "unknown"
+}
+
+// StateNotifier notifies the changes of the connection state
+type StateNotifier interface {
+	// State returns a channel receiving the current state and then its changes
+	// (closed after the Closed state)
+	State() <-chan ConnectionState
+}
@@ -17,6 +17,22 @@ type StatusResponse struct {
 type Status struct {
 	Applications []*ApplicationSession `json:"applications"`
 	Volume       *Volume               `json:"volume,omitempty"`
+	// IsStandBy is true when the TV is in standby (only reported through HDMI-CEC)
+	IsStandBy *bool `json:"isStandBy,omitempty"`
+	// IsActiveInput is true when the TV shows the chromecast input (only reported through HDMI-CEC)
+	IsActiveInput *bool `json:"isActiveInput,omitempty"`
+}
+
+// OnScreen returns false if the TV is known to be in standby or on another input
+// (true if unknown)
+func (st Status) OnScreen() bool {
+	if st.IsStandBy != nil && *st.IsStandBy {
+		return false
+	}
+	if st.IsActiveInput != nil && !*st.IsActiveInput {
+		return false
+	}
+	return true
 }
 
 func (st Status) String() string {
@@ -38,6 +54,11 @@ func (st Status) String() string {
 			str.WriteString(" (muted)")
 		}
 	}
+	if st.IsStandBy != nil && *st.IsStandBy {
+		str.WriteString("\nTV in standby")
+	} else if st.IsActiveInput != nil && !*st.IsActiveInput {
+		str.WriteString("\nTV on another input")
+	}
 
 	return str.String()
 }
@@ -80,12 +101,14 @@ func (st Status) FirstDestinationSupporting(namespace string) (string, error) {
 }
 
 type ApplicationSession struct {
-	AppID       *string      `json:"appId,omitempty"`
-	DisplayName *string      `json:"displayName,omitempty"`
-	Namespaces  []*Namespace `json:"namespaces"`
-	SessionID   *string      `json:"sessionId,omitempty"`
-	StatusText  *string      `json:"statusText,omitempty"`
-	TransportId *string      `json:"transportId,omitempty"`
+	AppID       *string `json:"appId,omitempty"`
+	DisplayName *string `json:"displayName,omitempty"`
+	// IsIdleScreen is true for the backdrop (screensaver)
+	IsIdleScreen *bool        `json:"isIdleScreen,omitempty"`
+	Namespaces   []*Namespace `json:"namespaces"`
+	SessionID    *string      `json:"sessionId,omitempty"`
+	StatusText   *string      `json:"statusText,omitempty"`
+	TransportId  *string      `json:"transportId,omitempty"`
 }
 
 type Namespace struct {
@@ -95,4 +118,8 @@ type Namespace struct {
 type Volume struct {
 	Level *float64 `json:"level,omitempty"`
 	Muted *bool    `json:"muted,omitempty"`
+	// StepInterval is the volume step of the device (only in the status)
+	StepInterval *float64 `json:"stepInterval,omitempty"`
+	// ControlType is "attenuation", "fixed" or "master" (only in the status)
+	ControlType *string `json:"controlType,omitempty"`
 }